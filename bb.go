@@ -22,3 +22,35 @@ type Bb struct {
 	Value interface{}
 	Subs  []Bb
 }
+
+// isPureOperator reports whether bb is a bare OR()/AND() connector
+// (no key, no value, no subs)
+func isPureOperator(bb Bb) bool {
+	return (bb.Op == OR || bb.Op == AND) && bb.Key == "" && bb.Value == nil && len(bb.Subs) == 0
+}
+
+// splitOrGroups splits a condition list at OR() connectors
+//
+// Conditions are joined by AND unless separated by OR(), exactly as the SQL output does:
+//
+//	a AND b OR c  =>  [[a, b], [c]]
+//
+// Non-SQL backends (Qdrant, RediSearch, ...) use it to keep the same precedence.
+func splitOrGroups(bbs []Bb) [][]Bb {
+	groups := [][]Bb{}
+	current := []Bb{}
+	for _, bb := range bbs {
+		if isPureOperator(bb) {
+			if bb.Op == OR && len(current) > 0 {
+				groups = append(groups, current)
+				current = []Bb{}
+			}
+			continue
+		}
+		current = append(current, bb)
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
@@ -69,6 +69,12 @@ func (b *InsertBuilder) Set(k string, v interface{}) *InsertBuilder {
 	case []float32, []float64:
 		// ⭐ Vector array: keep as is (for Qdrant/Milvus)
 		// No JSON serialization
+	case MultiVector, []Vector, [][]float32:
+		// ⭐ Multivector (ColBERT): keep as is, Qdrant named vector
+	case SparseVector:
+		// ⭐ Sparse vector: keep as is, SparseVector.Value() writes pgvector sparsevec
+	case HalfVector, Int8Vector, BinaryVector:
+		// ⭐ Quantized vector: keep as is, Value() writes halfvec / bytea / bit
 	case interface{}:
 		bytes, _ := json.Marshal(v)
 		v = string(bytes)
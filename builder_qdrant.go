@@ -0,0 +1,81 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import "time"
+
+// Qdrant payload conditions (BuilderX extension)
+// Same functionality as the CondBuilder methods, but return *BuilderX for chaining
+//
+// Example:
+//
+//	xb.Of(&Restaurant{}).
+//	    Custom(xb.NewQdrantBuilder().Build()).
+//	    GeoRadius("location", 52.52, 13.40, 1000).
+//	    Datetime("opened_at", xb.Gte, since).
+//	    VectorSearch("embedding", vec, 10).
+//	    Build()
+
+func (x *BuilderX) MatchExcept(k string, vs ...interface{}) *BuilderX {
+	x.CondBuilder.MatchExcept(k, vs...)
+	return x
+}
+
+func (x *BuilderX) GeoRadius(k string, lat, lon float64, radiusMeters float64) *BuilderX {
+	x.CondBuilder.GeoRadius(k, lat, lon, radiusMeters)
+	return x
+}
+
+func (x *BuilderX) GeoBoundingBox(k string, topLeft, bottomRight QdrantGeoPoint) *BuilderX {
+	x.CondBuilder.GeoBoundingBox(k, topLeft, bottomRight)
+	return x
+}
+
+func (x *BuilderX) GeoPolygon(k string, exterior []QdrantGeoPoint, interiors ...[]QdrantGeoPoint) *BuilderX {
+	x.CondBuilder.GeoPolygon(k, exterior, interiors...)
+	return x
+}
+
+func (x *BuilderX) Datetime(k string, op Op, t time.Time) *BuilderX {
+	x.CondBuilder.Datetime(k, op, t)
+	return x
+}
+
+func (x *BuilderX) ValuesCount(k string, op Op, n int) *BuilderX {
+	x.CondBuilder.ValuesCount(k, op, n)
+	return x
+}
+
+func (x *BuilderX) IsEmpty(k string) *BuilderX {
+	x.CondBuilder.IsEmpty(k)
+	return x
+}
+
+func (x *BuilderX) IsNullValue(k string) *BuilderX {
+	x.CondBuilder.IsNullValue(k)
+	return x
+}
+
+func (x *BuilderX) HasId(ids ...interface{}) *BuilderX {
+	x.CondBuilder.HasId(ids...)
+	return x
+}
+
+func (x *BuilderX) Nested(k string, f func(cb *CondBuilder)) *BuilderX {
+	x.CondBuilder.Nested(k, f)
+	return x
+}
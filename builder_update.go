@@ -72,6 +72,10 @@ func (ub *UpdateBuilder) Set(k string, v interface{}) *UpdateBuilder {
 	case []float32, []float64:
 		// ⭐ Vector array: keep as is (for Qdrant/Milvus)
 		// No JSON serialization
+	case SparseVector:
+		// ⭐ Sparse vector: keep as is, SparseVector.Value() writes pgvector sparsevec
+	case HalfVector, Int8Vector, BinaryVector:
+		// ⭐ Quantized vector: keep as is, Value() writes halfvec / bytea / bit
 	case interface{}:
 		bytes, _ := json.Marshal(v)
 		v = string(bytes)
@@ -0,0 +1,101 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import "fmt"
+
+// ============================================================================
+// Qdrant update modes
+// ============================================================================
+//
+// Without a mode, Set() values are merged into the payload (POST /points/payload).
+// A mode selects another payload or vector endpoint, see JsonOfQdrantUpdate().
+// The points are selected by the conditions: id Eq/In => ids, otherwise filter.
+// SQL ignores these modes.
+
+// PayloadKey sets the payload under a nested key path instead of the root
+//
+// Example:
+//
+//	// {"payload": {"city": "Berlin"}, "key": "address", "points": [1]}
+//	builder.Eq("id", 1).Update(func(ub *xb.UpdateBuilder) {
+//	    ub.Set("city", "Berlin").PayloadKey("address")
+//	})
+func (ub *UpdateBuilder) PayloadKey(key string) *UpdateBuilder {
+	if key == "" {
+		panic("PayloadKey() requires a non-empty key")
+	}
+	ub.bbs = append(ub.bbs, Bb{Op: QDRANT_PAYLOAD_KEY, Value: key})
+	return ub
+}
+
+// OverwritePayload replaces the whole payload with the Set() values (PUT /points/payload)
+func (ub *UpdateBuilder) OverwritePayload() *UpdateBuilder {
+	ub.bbs = append(ub.bbs, Bb{Op: QDRANT_PAYLOAD_OVERWRITE})
+	return ub
+}
+
+// DeletePayloadKeys removes keys from the payload (POST /points/payload/delete)
+//
+// Example:
+//
+//	builder.Eq("language", "python").Update(func(ub *xb.UpdateBuilder) {
+//	    ub.DeletePayloadKeys("draft", "reviewer")
+//	})
+func (ub *UpdateBuilder) DeletePayloadKeys(keys ...string) *UpdateBuilder {
+	if len(keys) == 0 {
+		panic("DeletePayloadKeys() requires at least one key")
+	}
+	for _, k := range keys {
+		if k == "" {
+			panic("DeletePayloadKeys() key must not be empty")
+		}
+	}
+	ub.bbs = append(ub.bbs, Bb{Op: QDRANT_PAYLOAD_DELETE, Value: append([]string(nil), keys...)})
+	return ub
+}
+
+// ClearPayload removes the whole payload (POST /points/payload/clear)
+func (ub *UpdateBuilder) ClearPayload() *UpdateBuilder {
+	ub.bbs = append(ub.bbs, Bb{Op: QDRANT_PAYLOAD_CLEAR})
+	return ub
+}
+
+// Vector replaces a vector of the points (PUT /points/vectors)
+// name: named vector, "" for the default vector; points must be selected by id
+//
+// Example:
+//
+//	builder.In("id", 1, 2).Update(func(ub *xb.UpdateBuilder) {
+//	    ub.Vector("image", imageVec)
+//	})
+func (ub *UpdateBuilder) Vector(name string, vec Vector) *UpdateBuilder {
+	if len(vec) == 0 {
+		panic(fmt.Sprintf("Vector(%q) requires a non-empty vector", name))
+	}
+	ub.bbs = append(ub.bbs, Bb{Op: QDRANT_UPDATE_VECTOR, Key: name, Value: append(Vector(nil), vec...)})
+	return ub
+}
+
+// DeleteVectors removes named vectors from the points (POST /points/vectors/delete)
+func (ub *UpdateBuilder) DeleteVectors(names ...string) *UpdateBuilder {
+	if len(names) == 0 {
+		panic("DeleteVectors() requires at least one vector name")
+	}
+	ub.bbs = append(ub.bbs, Bb{Op: QDRANT_DELETE_VECTORS, Value: append([]string(nil), names...)})
+	return ub
+}
@@ -31,6 +31,34 @@ func (x *BuilderX) VectorSearch(field string, queryVector Vector, topK int) *Bui
 	return x
 }
 
+// MultiVectorSearch late-interaction (ColBERT) search (BuilderX extension)
+//
+// Example:
+//
+//	xb.Of(&Doc{}).
+//	    Custom(xb.NewQdrantBuilder().NamedVectors().Build()).
+//	    MultiVectorSearch("colbert", tokenVectors, 10).
+//	    Build().
+//	    JsonOfSelect()
+func (x *BuilderX) MultiVectorSearch(field string, query MultiVector, topK int) *BuilderX {
+	x.CondBuilder.MultiVectorSearch(field, query, topK)
+	return x
+}
+
+// SparseVectorSearch sparse vector (SPLADE, BM42) search (BuilderX extension)
+//
+// Example:
+//
+//	xb.Of(&Doc{}).
+//	    Eq("language", "en").
+//	    SparseVectorSearch("text_sparse", xb.NewSparseVector(weights), 10).
+//	    Build().
+//	    SqlOfVectorSearch()
+func (x *BuilderX) SparseVectorSearch(field string, query SparseVector, topK int) *BuilderX {
+	x.CondBuilder.SparseVectorSearch(field, query, topK)
+	return x
+}
+
 // VectorDistance sets vector distance metric (BuilderX extension)
 //
 // Example:
@@ -42,6 +70,17 @@ func (x *BuilderX) VectorDistance(metric VectorDistance) *BuilderX {
 	return x
 }
 
+// VectorEncoding sets the storage type of the searched column (BuilderX extension)
+//
+// Example:
+//
+//	builder.VectorSearch("embedding", vec, 10).
+//	    VectorEncoding(xb.BinaryEncoding)
+func (x *BuilderX) VectorEncoding(encoding VectorEncoding) *BuilderX {
+	x.CondBuilder.VectorEncoding(encoding)
+	return x
+}
+
 // VectorDistanceFilter vector distance filtering (BuilderX extension)
 //
 // Example:
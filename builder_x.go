@@ -50,6 +50,8 @@ type BuilderX struct {
 	offsetValue int                   // ⭐ OFFSET value (v0.10.1)
 	meta        *interceptor.Metadata // ⭐ Metadata (v0.9.2)
 	customImpl  Custom                // ⭐ Database-specific config (v0.11.0) (private field)
+	embedder    Embedder              // SemanticSearch() embedder, overrides the Custom's
+	rerank      *RerankParams         // Rerank() stage, stored on the VectorSearch by Build()
 	withs       []withClause
 	unions      []unionClause
 }
@@ -430,6 +432,12 @@ func (x *BuilderX) Build() *Built {
 		}
 	}
 
+	// SemanticSearch() texts are embedded by BuildContext(), Build() makes no network call
+	if pending := x.pendingSemanticSearch(); len(pending) > 0 {
+		panic(fmt.Sprintf("SemanticSearch(%q) requires BuildContext(ctx) to embed the text, use BuildContext instead of Build", x.bbs[pending[0]].Key))
+	}
+	x.applyRerank()
+
 	baseFrom := x.normalizeFrom()
 	withs := x.buildWithClauses()
 	unions := x.buildUnionClauses()
@@ -193,8 +193,7 @@ func (cb *CondBuilder) orAndSub(orAnd string, f func(cb *CondBuilder)) *CondBuil
 	hasRealCondition := false
 	for _, b := range c.bbs {
 		// Pure operator Bb: op=OR/AND, key="", value=nil, subs=nil/empty
-		isPureOperator := (b.Op == OR || b.Op == AND) && b.Key == "" && b.Value == nil && (b.Subs == nil || len(b.Subs) == 0)
-		if !isPureOperator {
+		if !isPureOperator(b) {
 			hasRealCondition = true
 			break
 		}
@@ -0,0 +1,220 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Qdrant payload conditions
+// ============================================================================
+//
+// Translated into the Qdrant filter (must), no SQL equivalent:
+// SQL generation panics on them instead of dropping the filter.
+// Full-text match: use Like(), it becomes match.text
+
+// MatchExcept Qdrant match.except: value is none of vs
+// For array payloads at least one element must be outside vs (Nin() requires all of them)
+//
+// Example:
+//
+//	builder.MatchExcept("tags", "draft", "spam")
+func (cb *CondBuilder) MatchExcept(k string, vs ...interface{}) *CondBuilder {
+	values := make([]interface{}, 0, len(vs))
+	for _, v := range vs {
+		if v == nil || v == "" {
+			continue
+		}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return cb
+	}
+	return cb.addBb(QDRANT_MATCH_EXCEPT, k, values)
+}
+
+// GeoRadius points within radiusMeters of (lat, lon)
+//
+// Example:
+//
+//	builder.GeoRadius("location", 52.52, 13.40, 1000)
+func (cb *CondBuilder) GeoRadius(k string, lat, lon float64, radiusMeters float64) *CondBuilder {
+	if radiusMeters <= 0 {
+		panic(fmt.Sprintf("GeoRadius(%q) radius must be > 0, got: %f", k, radiusMeters))
+	}
+	return cb.addBb(QDRANT_GEO_RADIUS, k, &QdrantGeoRadius{
+		Center: QdrantGeoPoint{Lon: lon, Lat: lat},
+		Radius: radiusMeters,
+	})
+}
+
+// GeoBoundingBox points inside the rectangle
+//
+// Example:
+//
+//	builder.GeoBoundingBox("location",
+//	    xb.QdrantGeoPoint{Lat: 52.52, Lon: 13.38},
+//	    xb.QdrantGeoPoint{Lat: 52.49, Lon: 13.45})
+func (cb *CondBuilder) GeoBoundingBox(k string, topLeft, bottomRight QdrantGeoPoint) *CondBuilder {
+	if topLeft.Lat < bottomRight.Lat {
+		panic(fmt.Sprintf("GeoBoundingBox(%q) top_left must be north of bottom_right", k))
+	}
+	return cb.addBb(QDRANT_GEO_BOUNDING_BOX, k, &QdrantGeoBoundingBox{
+		TopLeft:     topLeft,
+		BottomRight: bottomRight,
+	})
+}
+
+// GeoPolygon points inside exterior and outside every interior (hole)
+// Rings need at least 3 points, they are closed automatically
+func (cb *CondBuilder) GeoPolygon(k string, exterior []QdrantGeoPoint, interiors ...[]QdrantGeoPoint) *CondBuilder {
+	polygon := &QdrantGeoPolygon{Exterior: geoRing(k, exterior)}
+	for _, interior := range interiors {
+		polygon.Interiors = append(polygon.Interiors, geoRing(k, interior))
+	}
+	return cb.addBb(QDRANT_GEO_POLYGON, k, polygon)
+}
+
+func geoRing(k string, points []QdrantGeoPoint) QdrantGeoLineString {
+	if len(points) < 3 {
+		panic(fmt.Sprintf("GeoPolygon(%q) ring needs at least 3 points, got: %d", k, len(points)))
+	}
+	ring := append([]QdrantGeoPoint(nil), points...)
+	if ring[0] != ring[len(ring)-1] {
+		ring = append(ring, ring[0])
+	}
+	return QdrantGeoLineString{Points: ring}
+}
+
+// Datetime datetime range condition, t is written as RFC3339
+// op: xb.Gt, xb.Gte, xb.Lt, xb.Lte; zero t is ignored
+//
+// Example:
+//
+//	builder.Datetime("created_at", xb.Gte, from).
+//	    Datetime("created_at", xb.Lt, to)
+func (cb *CondBuilder) Datetime(k string, op Op, t time.Time) *CondBuilder {
+	if t.IsZero() {
+		return cb
+	}
+	r := QdrantRangeCondition{}
+	s := t.Format(time.RFC3339Nano)
+	switch rangeOp(k, "Datetime", op) {
+	case GT:
+		r.GtTime = s
+	case GTE:
+		r.GteTime = s
+	case LT:
+		r.LtTime = s
+	case LTE:
+		r.LteTime = s
+	}
+	return cb.addBb(QDRANT_DATETIME_RANGE, k, &r)
+}
+
+// ValuesCount condition on the number of values of an array field
+// op: xb.Eq, xb.Gt, xb.Gte, xb.Lt, xb.Lte
+//
+// Example:
+//
+//	builder.ValuesCount("tags", xb.Gte, 2)
+func (cb *CondBuilder) ValuesCount(k string, op Op, n int) *CondBuilder {
+	if n < 0 {
+		panic(fmt.Sprintf("ValuesCount(%q) must be >= 0, got: %d", k, n))
+	}
+	vc := &QdrantValuesCount{}
+	if op != nil && op() == EQ {
+		vc.Gte, vc.Lte = &n, &n
+		return cb.addBb(QDRANT_VALUES_COUNT, k, vc)
+	}
+	switch rangeOp(k, "ValuesCount", op) {
+	case GT:
+		vc.Gt = &n
+	case GTE:
+		vc.Gte = &n
+	case LT:
+		vc.Lt = &n
+	case LTE:
+		vc.Lte = &n
+	}
+	return cb.addBb(QDRANT_VALUES_COUNT, k, vc)
+}
+
+func rangeOp(k string, method string, op Op) string {
+	if op != nil {
+		switch o := op(); o {
+		case GT, GTE, LT, LTE:
+			return o
+		}
+	}
+	panic(fmt.Sprintf("%s(%q) op must be one of xb.Gt, xb.Gte, xb.Lt, xb.Lte", method, k))
+}
+
+// IsEmpty Qdrant is_empty: field is missing, null or []
+func (cb *CondBuilder) IsEmpty(k string) *CondBuilder {
+	return cb.null(QDRANT_IS_EMPTY, k)
+}
+
+// IsNullValue Qdrant is_null: field exists with value null
+// IsNull() follows SQL semantics (null or missing)
+func (cb *CondBuilder) IsNullValue(k string) *CondBuilder {
+	return cb.null(QDRANT_IS_NULL, k)
+}
+
+// HasId point id is one of ids (integer or UUID, see PointID)
+//
+// Example:
+//
+//	builder.HasId(1, 2, "5c56c793-69f3-4fbf-87e6-c4bf54c28c26")
+func (cb *CondBuilder) HasId(ids ...interface{}) *CondBuilder {
+	values := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		if id == nil || id == "" {
+			continue
+		}
+		values = append(values, id)
+	}
+	if len(values) == 0 {
+		return cb
+	}
+	return cb.addBb(QDRANT_HAS_ID, "", mustPointIDs("HasId()", values))
+}
+
+// Nested conditions on each element of an array of objects
+// Keys inside f are relative to the element, all of them must match the same element
+//
+// Example:
+//
+//	// payload: {"diet": [{"food": "meat", "likes": false}, ...]}
+//	builder.Nested("diet", func(cb *xb.CondBuilder) {
+//	    cb.Eq("food", "meat").Eq("likes", true)
+//	})
+func (cb *CondBuilder) Nested(k string, f func(cb *CondBuilder)) *CondBuilder {
+	c := subCondBuilder()
+	f(c)
+	if len(c.bbs) == 0 {
+		return cb
+	}
+	cb.bbs = append(cb.bbs, Bb{
+		Op:   QDRANT_NESTED,
+		Key:  k,
+		Subs: c.bbs,
+	})
+	return cb
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQdrantPayloadConditions_Geo(t *testing.T) {
+	got := qdrantFilterJSON(t, func(x *BuilderX) {
+		x.GeoRadius("location", 52.52, 13.40, 1000).
+			GeoBoundingBox("location",
+				QdrantGeoPoint{Lat: 52.52, Lon: 13.38},
+				QdrantGeoPoint{Lat: 52.49, Lon: 13.45}).
+			GeoPolygon("location",
+				[]QdrantGeoPoint{{Lon: 0, Lat: 0}, {Lon: 1, Lat: 0}, {Lon: 1, Lat: 1}},
+				[]QdrantGeoPoint{{Lon: 0.2, Lat: 0.2}, {Lon: 0.4, Lat: 0.2}, {Lon: 0.4, Lat: 0.4}, {Lon: 0.2, Lat: 0.2}})
+	})
+	assertJSONEqual(t, got, `{
+		"must": [
+			{"key": "location", "geo_radius": {"center": {"lon": 13.40, "lat": 52.52}, "radius": 1000}},
+			{"key": "location", "geo_bounding_box": {
+				"top_left": {"lon": 13.38, "lat": 52.52},
+				"bottom_right": {"lon": 13.45, "lat": 52.49}
+			}},
+			{"key": "location", "geo_polygon": {
+				"exterior": {"points": [
+					{"lon": 0, "lat": 0}, {"lon": 1, "lat": 0}, {"lon": 1, "lat": 1}, {"lon": 0, "lat": 0}
+				]},
+				"interiors": [{"points": [
+					{"lon": 0.2, "lat": 0.2}, {"lon": 0.4, "lat": 0.2}, {"lon": 0.4, "lat": 0.4}, {"lon": 0.2, "lat": 0.2}
+				]}]
+			}}
+		]
+	}`)
+}
+
+func TestQdrantPayloadConditions_DatetimeAndCounts(t *testing.T) {
+	from := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	got := qdrantFilterJSON(t, func(x *BuilderX) {
+		x.Datetime("created_at", Gte, from).
+			Datetime("created_at", Lt, to).
+			Datetime("updated_at", Gt, time.Time{}). // zero: ignored
+			Lte("published_at", from).               // time.Time via doGLE
+			ValuesCount("tags", Gte, 2).
+			ValuesCount("authors", Eq, 1)
+	})
+	assertJSONEqual(t, got, `{
+		"must": [
+			{"key": "created_at", "range": {"gte": "2025-01-02T03:04:05Z"}},
+			{"key": "created_at", "range": {"lt": "2025-02-01T00:00:00+01:00"}},
+			{"key": "published_at", "range": {"lte": "2025-01-02T03:04:05"}},
+			{"key": "tags", "values_count": {"gte": 2}},
+			{"key": "authors", "values_count": {"gte": 1, "lte": 1}}
+		]
+	}`)
+
+	// Round trip keeps numeric and datetime bounds apart
+	var r QdrantRangeCondition
+	if err := json.Unmarshal([]byte(`{"gte": "2025-01-02T03:04:05Z", "lt": 10}`), &r); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if r.GteTime != "2025-01-02T03:04:05Z" || r.Lt == nil || *r.Lt != 10 {
+		t.Errorf("unexpected range: %+v", r)
+	}
+}
+
+func TestQdrantPayloadConditions_MatchIdsAndNested(t *testing.T) {
+	got := qdrantFilterJSON(t, func(x *BuilderX) {
+		x.Like("content", "retry").
+			MatchExcept("tags", "draft", nil, "spam").
+			IsEmpty("reviews").
+			IsNullValue("owner").
+			HasId(1, uint64(2), "5c56c793-69f3-4fbf-87e6-c4bf54c28c26").
+			Nested("diet", func(cb *CondBuilder) {
+				cb.Eq("food", "meat").Eq("likes", true)
+			}).
+			Or(func(cb *CondBuilder) {
+				cb.IsEmpty("a").OR().HasId(9)
+			})
+	})
+	assertJSONEqual(t, got, `{
+		"must": [
+			{"key": "content", "match": {"text": "retry"}},
+			{"key": "tags", "match": {"except": ["draft", "spam"]}},
+			{"is_empty": {"key": "reviews"}},
+			{"is_null": {"key": "owner"}},
+			{"has_id": [1, 2, "5c56c793-69f3-4fbf-87e6-c4bf54c28c26"]},
+			{"nested": {"key": "diet", "filter": {"must": [
+				{"key": "food", "match": {"value": "meat"}},
+				{"key": "likes", "match": {"value": true}}
+			]}}},
+			{"should": [{"is_empty": {"key": "a"}}, {"has_id": [9]}]}
+		]
+	}`)
+
+	// Nested errors are reported with the nested key
+	_, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Nested("diet", func(cb *CondBuilder) { cb.X("food = 'meat'") }).
+		VectorSearch("embedding", Vector{0.1}, 10).
+		Build().
+		JsonOfSelect()
+	if err == nil {
+		t.Fatal("expected error for raw X inside Nested")
+	}
+}
+
+func TestQdrantPayloadConditions_Validation(t *testing.T) {
+	cases := map[string]func(x *BuilderX){
+		"radius":        func(x *BuilderX) { x.GeoRadius("loc", 0, 0, 0) },
+		"bounding box":  func(x *BuilderX) { x.GeoBoundingBox("loc", QdrantGeoPoint{Lat: 1}, QdrantGeoPoint{Lat: 2}) },
+		"polygon ring":  func(x *BuilderX) { x.GeoPolygon("loc", []QdrantGeoPoint{{}, {Lat: 1}}) },
+		"datetime op":   func(x *BuilderX) { x.Datetime("ts", Like, time.Now()) },
+		"values count":  func(x *BuilderX) { x.ValuesCount("tags", Gt, -1) },
+		"has_id type":   func(x *BuilderX) { x.HasId(1.5) },
+		"nil values op": func(x *BuilderX) { x.ValuesCount("tags", nil, 1) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn(Of(&CodeVectorForQdrant{}))
+		})
+	}
+}
+
+func TestQdrantPayloadConditions_SQLPanics(t *testing.T) {
+	conds := map[string]func(x *BuilderX){
+		"match except":  func(x *BuilderX) { x.MatchExcept("tags", "draft") },
+		"geo radius":    func(x *BuilderX) { x.GeoRadius("loc", 52.52, 13.40, 1000) },
+		"bounding box":  func(x *BuilderX) { x.GeoBoundingBox("loc", QdrantGeoPoint{Lat: 2}, QdrantGeoPoint{Lat: 1, Lon: 1}) },
+		"polygon":       func(x *BuilderX) { x.GeoPolygon("loc", []QdrantGeoPoint{{}, {Lat: 1}, {Lat: 1, Lon: 1}}) },
+		"datetime":      func(x *BuilderX) { x.Datetime("created_at", Gte, time.Now()) },
+		"values count":  func(x *BuilderX) { x.ValuesCount("tags", Gte, 2) },
+		"is empty":      func(x *BuilderX) { x.IsEmpty("reviews") },
+		"is null value": func(x *BuilderX) { x.IsNullValue("owner") },
+		"has id":        func(x *BuilderX) { x.HasId(1) },
+		"nested":        func(x *BuilderX) { x.Nested("diet", func(cb *CondBuilder) { cb.Eq("food", "meat") }) },
+		"inside or": func(x *BuilderX) {
+			x.Or(func(cb *CondBuilder) { cb.IsEmpty("a").OR().Eq("b", 1) })
+		},
+	}
+	gens := map[string]func(x *BuilderX){
+		"SqlOfSelect": func(x *BuilderX) { x.Build().SqlOfSelect() },
+		"SqlOfVectorSearch": func(x *BuilderX) {
+			x.VectorSearch("embedding", Vector{0.1}, 10).Build().SqlOfVectorSearch()
+		},
+	}
+	for cname, cond := range conds {
+		for gname, gen := range gens {
+			t.Run(cname+"/"+gname, func(t *testing.T) {
+				defer func() {
+					r := recover()
+					if r == nil {
+						t.Fatal("expected panic")
+					}
+					if s, _ := r.(string); !strings.Contains(s, "Qdrant-only condition") {
+						t.Errorf("panic = %v", r)
+					}
+				}()
+				x := Of(&CodeVectorForQdrant{}).Eq("language", "golang")
+				cond(x)
+				gen(x)
+			})
+		}
+	}
+}
@@ -16,6 +16,8 @@
 // limitations under the License.
 package xb
 
+import "fmt"
+
 // VectorSearch vector similarity search
 // field: vector field name
 // queryVector: query vector
@@ -73,6 +75,47 @@ func (cb *CondBuilder) VectorDistance(metric VectorDistance) *CondBuilder {
 			// Modify distance metric
 			if params, ok := cb.bbs[i].Value.(VectorSearchParams); ok {
 				params.DistanceMetric = metric
+				if params.Diversity != nil {
+					params.Diversity.DistanceMetric = metric
+				}
+				cb.bbs[i].Value = params
+			}
+			break
+		}
+	}
+
+	return cb
+}
+
+// VectorEncoding sets the storage type of the searched column
+// Must be called after VectorSearch(); the query vector is converted when the SQL is generated
+//
+//	Float16Encoding: embedding <-> ?::halfvec
+//	BinaryEncoding:  embedding <~> ?::bit (also sets HammingDistance)
+//	Int8Encoding:    Qdrant only (datatype uint8), SQL generation panics
+//
+// Vector databases quantize server-side and ignore it, see QdrantCollectionBuilder.Datatype()
+//
+// Example:
+//
+//	builder.VectorSearch("embedding", vec, 10).VectorEncoding(xb.Float16Encoding)
+func (cb *CondBuilder) VectorEncoding(encoding VectorEncoding) *CondBuilder {
+	switch encoding {
+	case "", Float32Encoding, Float16Encoding, Int8Encoding, BinaryEncoding:
+	default:
+		panic(fmt.Sprintf("VectorEncoding(): unknown encoding %q", encoding))
+	}
+
+	for i := len(cb.bbs) - 1; i >= 0; i-- {
+		if cb.bbs[i].Op == VECTOR_SEARCH {
+			if params, ok := cb.bbs[i].Value.(VectorSearchParams); ok {
+				if params.QueryVector == nil && params.QueryText == "" {
+					panic(fmt.Sprintf("VectorEncoding(): %q is not a dense VectorSearch", cb.bbs[i].Key))
+				}
+				params.Encoding = encoding
+				if encoding == BinaryEncoding {
+					params.DistanceMetric = HammingDistance
+				}
 				cb.bbs[i].Value = params
 			}
 			break
@@ -124,12 +167,112 @@ func (cb *CondBuilder) VectorDistanceFilter(
 	return cb
 }
 
+// MultiVectorSearch late-interaction (ColBERT) search: one query vector per token, scored by MaxSim
+// Qdrant: /points/query on a multivector (max_sim) vector; not supported by SQL databases
+//
+// Example:
+//
+//	builder.MultiVectorSearch("colbert", tokenVectors, 10)
+func (cb *CondBuilder) MultiVectorSearch(field string, query MultiVector, topK int) *CondBuilder {
+	if field == "" || len(query) == 0 {
+		return cb
+	}
+	for _, row := range query {
+		if len(row) == 0 || len(row) != len(query[0]) {
+			panic(fmt.Sprintf("MultiVectorSearch(%q) rows must be non-empty and of the same dimension", field))
+		}
+	}
+	if topK <= 0 {
+		topK = 10 // Default value
+	}
+
+	cb.bbs = append(cb.bbs, Bb{
+		Op:  VECTOR_SEARCH,
+		Key: field,
+		Value: VectorSearchParams{
+			QueryMultiVector: query,
+			TopK:             topK,
+			DistanceMetric:   CosineDistance,
+		},
+	})
+	return cb
+}
+
+// SparseVectorSearch sparse vector (SPLADE, BM42) search, scored by dot product
+// PostgreSQL: sparsevec with the inner product operator; Qdrant: named sparse vector
+// Empty vectors are ignored, invalid ones (unsorted indices, length mismatch) panic
+//
+// Example:
+//
+//	builder.SparseVectorSearch("text_sparse", xb.NewSparseVector(weights), 10)
+func (cb *CondBuilder) SparseVectorSearch(field string, query SparseVector, topK int) *CondBuilder {
+	if field == "" || query.Len() == 0 {
+		return cb
+	}
+	if err := query.Validate(); err != nil {
+		panic(fmt.Sprintf("SparseVectorSearch(%q): %v", field, err))
+	}
+	if topK <= 0 {
+		topK = 10 // Default value
+	}
+
+	cb.bbs = append(cb.bbs, Bb{
+		Op:  VECTOR_SEARCH,
+		Key: field,
+		Value: VectorSearchParams{
+			QuerySparseVector: &query,
+			TopK:              topK,
+			DistanceMetric:    InnerProduct,
+		},
+	})
+	return cb
+}
+
 // VectorSearchParams vector search parameters
 type VectorSearchParams struct {
-	QueryVector    Vector
-	TopK           int
-	DistanceMetric VectorDistance
-	Diversity      *DiversityParams // ⭐ Added: diversity parameters (optional)
+	QueryVector       Vector
+	QueryText         string        // Set by SemanticSearch(), embedded into QueryVector by BuildContext()
+	QueryMultiVector  MultiVector   // Set by MultiVectorSearch(), QueryVector is nil then
+	QuerySparseVector *SparseVector // Set by SparseVectorSearch(), QueryVector is nil then
+	TopK              int
+	DistanceMetric    VectorDistance
+	Encoding          VectorEncoding   // Set by VectorEncoding(), "" is Float32Encoding
+	Diversity         *DiversityParams // ⭐ Added: diversity parameters (optional)
+	Rerank            *RerankParams    // Set by BuilderX.Rerank(), the search over-fetches Rerank.Candidates
+}
+
+// fetchK the number of hits to fetch: topK, or the rerank candidates
+func (p VectorSearchParams) fetchK() int {
+	if p.Rerank != nil {
+		return p.Rerank.Candidates
+	}
+	return p.TopK
+}
+
+// queryArg the query as a SQL argument, encoded like the column
+func (p VectorSearchParams) queryArg() interface{} {
+	if p.QuerySparseVector != nil {
+		return *p.QuerySparseVector
+	}
+	switch p.Encoding {
+	case Float16Encoding:
+		return p.QueryVector.ToHalf()
+	case BinaryEncoding:
+		return p.QueryVector.Binarize()
+	}
+	return p.QueryVector
+}
+
+// queryPlaceholder "?", or "?::halfvec" / "?::bit(n)" for quantized columns
+// A bare bit is bit(1) in PostgreSQL, the cast carries the query dimension
+func (p VectorSearchParams) queryPlaceholder() string {
+	switch p.Encoding {
+	case Float16Encoding:
+		return "?::" + string(p.Encoding)
+	case BinaryEncoding:
+		return fmt.Sprintf("?::%s(%d)", p.Encoding, len(p.QueryVector))
+	}
+	return "?"
 }
 
 // VectorDistanceFilterParams vector distance filter parameters
@@ -167,6 +310,7 @@ func (cb *CondBuilder) WithDiversity(strategy DiversityStrategy, params ...inter
 					Enabled:         true,
 					Strategy:        strategy,
 					OverFetchFactor: 5, // Default 5x over-fetch
+					DistanceMetric:  searchParams.DistanceMetric,
 				}
 			}
 
@@ -0,0 +1,238 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import "fmt"
+
+// ============================================================================
+// Client-side diversity (applied to over-fetched results)
+// ============================================================================
+
+// Diversify applies DiversityParams to over-fetched search results
+//
+// WithDiversity/WithMMR/WithHashDiversity only enlarge the limit sent to the database
+// (TopK * OverFetchFactor), Diversify selects the final TopK on the application side:
+//   - DiversityByMMR:      Maximal Marginal Relevance, Lambda balances relevance and diversity
+//   - DiversityByDistance: greedy, keeps results at least MinDistance apart (Vector.Distance)
+//   - DiversityByHash:     keeps the best result per payload[HashField] value
+//
+// results must be in the order returned by the database (best first).
+// MMR and distance need Vector on each result (Qdrant: with_vector, set automatically).
+// If diversity leaves fewer than topK results, the best skipped ones fill the rest,
+// so min(topK, len(results)) results are always returned.
+//
+// Example:
+//
+//	built := xb.Of(&Doc{}).
+//	    Custom(xb.NewQdrantBuilder().Build()).
+//	    VectorSearch("embedding", vec, 10).
+//	    WithMMR(0.5).
+//	    Build()
+//
+//	// ... POST built.JsonOfSelect(), decode into []xb.ScoredPoint[Doc]
+//	params, topK := built.Diversity()
+//	top := xb.Diversify(results, params, topK)
+func Diversify[T any](results []ScoredPoint[T], params *DiversityParams, topK int) []ScoredPoint[T] {
+	if topK <= 0 || len(results) == 0 {
+		return []ScoredPoint[T]{}
+	}
+	if topK > len(results) {
+		topK = len(results)
+	}
+	if params == nil || !params.Enabled {
+		return append([]ScoredPoint[T](nil), results[:topK]...)
+	}
+
+	var picked []int
+	switch params.Strategy {
+	case DiversityByMMR:
+		picked = mmrSelect(results, params, topK)
+	case DiversityByDistance:
+		picked = minDistanceSelect(results, params, topK)
+	case DiversityByHash:
+		picked = hashSelect(results, params.HashField, topK)
+	}
+
+	picked = fillToTopK(picked, len(results), topK)
+
+	out := make([]ScoredPoint[T], 0, topK)
+	for _, i := range picked {
+		out = append(out, results[i])
+	}
+	return out
+}
+
+// Diversity returns the diversity parameters and TopK of the vector search
+// Returns nil, 0 if there is no VectorSearch
+func (built *Built) Diversity() (*DiversityParams, int) {
+	vectorBb := findVectorSearchBb(built.Conds)
+	if vectorBb == nil {
+		return nil, 0
+	}
+	params := vectorBb.Value.(VectorSearchParams)
+	topK := params.TopK
+	if built.PageCondition != nil && built.PageCondition.Rows > 0 {
+		topK = int(built.PageCondition.Rows)
+	}
+	return params.Diversity, topK
+}
+
+// mmrSelect Maximal Marginal Relevance
+// score(d) = λ·rel(d) - (1-λ)·max sim(d, selected), sim = 1 - cosine distance
+func mmrSelect[T any](results []ScoredPoint[T], params *DiversityParams, topK int) []int {
+	lambda := params.Lambda
+	rel := normalizedRelevance(results)
+
+	selected := make([]int, 0, topK)
+	used := make([]bool, len(results))
+	maxSim := make([]float32, len(results))
+
+	// Norms once, each similarity is then one dot product
+	norms := make([]float32, len(results))
+	for i := range results {
+		norms[i] = sqrt32(dotKernel(results[i].Vector, results[i].Vector))
+	}
+
+	for len(selected) < topK {
+		best, bestScore := -1, float32(0)
+		for i := range results {
+			if used[i] {
+				continue
+			}
+			score := lambda*rel[i] - (1-lambda)*maxSim[i]
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		used[best] = true
+		selected = append(selected, best)
+
+		// Update max similarity to the selected set
+		for i := range results {
+			if used[i] {
+				continue
+			}
+			a, b := results[i].Vector, results[best].Vector
+			if len(a) == 0 || len(a) != len(b) || norms[i] == 0 || norms[best] == 0 {
+				continue // Missing vectors add no similarity, zero vectors are completely dissimilar
+			}
+			if sim := dotKernel(a, b) / (norms[i] * norms[best]); sim > maxSim[i] {
+				maxSim[i] = sim
+			}
+		}
+	}
+	return selected
+}
+
+// minDistanceSelect keeps a result only if it is at least MinDistance away from every kept one
+func minDistanceSelect[T any](results []ScoredPoint[T], params *DiversityParams, topK int) []int {
+	metric := params.DistanceMetric
+	if metric == "" {
+		metric = CosineDistance
+	}
+
+	selected := make([]int, 0, topK)
+	for i := range results {
+		if len(selected) == topK {
+			break
+		}
+		ok := true
+		for _, j := range selected {
+			a, b := results[i].Vector, results[j].Vector
+			if len(a) == 0 || len(a) != len(b) {
+				continue
+			}
+			if a.Distance(b, metric) < params.MinDistance {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			selected = append(selected, i)
+		}
+	}
+	return selected
+}
+
+// hashSelect keeps the first (best) result of each payload[hashField] value
+// Results without the field are never treated as duplicates
+func hashSelect[T any](results []ScoredPoint[T], hashField string, topK int) []int {
+	seen := make(map[string]bool)
+	selected := make([]int, 0, topK)
+	for i := range results {
+		if len(selected) == topK {
+			break
+		}
+		v, ok := payloadField(results[i].Payload, hashField)
+		if ok && v != nil {
+			key := fmt.Sprint(v)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		selected = append(selected, i)
+	}
+	return selected
+}
+
+// fillToTopK appends the best skipped indexes until topK are picked
+func fillToTopK(picked []int, n, topK int) []int {
+	if len(picked) >= topK {
+		return picked[:topK]
+	}
+	in := make([]bool, n)
+	for _, i := range picked {
+		in[i] = true
+	}
+	for i := 0; i < n && len(picked) < topK; i++ {
+		if !in[i] {
+			picked = append(picked, i)
+		}
+	}
+	return picked
+}
+
+// normalizedRelevance min-max normalizes scores into [0, 1]
+// Results are in database order, so if scores ascend (distances) the scale is flipped
+func normalizedRelevance[T any](results []ScoredPoint[T]) []float32 {
+	rel := make([]float32, len(results))
+	lo, hi := results[0].Score, results[0].Score
+	for _, r := range results {
+		if r.Score < lo {
+			lo = r.Score
+		}
+		if r.Score > hi {
+			hi = r.Score
+		}
+	}
+	ascending := results[0].Score < results[len(results)-1].Score
+	for i, r := range results {
+		if hi == lo {
+			rel[i] = 1
+			continue
+		}
+		rel[i] = (r.Score - lo) / (hi - lo)
+		if ascending {
+			rel[i] = 1 - rel[i]
+		}
+	}
+	return rel
+}
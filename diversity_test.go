@@ -0,0 +1,134 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type diversityDoc struct {
+	Hash  string `json:"semantic_hash"`
+	Title string `json:"title"`
+}
+
+func diversityFixture() []ScoredPoint[diversityDoc] {
+	return []ScoredPoint[diversityDoc]{
+		{ID: 1, Score: 0.99, Vector: Vector{1, 0}, Payload: diversityDoc{Hash: "a"}},
+		{ID: 2, Score: 0.98, Vector: Vector{0.99, 0.01}, Payload: diversityDoc{Hash: "a"}},
+		{ID: 3, Score: 0.90, Vector: Vector{0, 1}, Payload: diversityDoc{Hash: "b"}},
+		{ID: 4, Score: 0.80, Vector: Vector{0.7, 0.7}, Payload: diversityDoc{Hash: "c"}},
+	}
+}
+
+func pointIDs[T any](points []ScoredPoint[T]) []interface{} {
+	out := []interface{}{}
+	for _, p := range points {
+		out = append(out, p.ID)
+	}
+	return out
+}
+
+func TestDiversify_Hash(t *testing.T) {
+	params := &DiversityParams{Enabled: true, Strategy: DiversityByHash, HashField: "semantic_hash"}
+	got := pointIDs(Diversify(diversityFixture(), params, 3))
+	if len(got) != 3 || got[0] != 1 || got[1] != 3 || got[2] != 4 {
+		t.Errorf("hash diversity = %v, want [1 3 4]", got)
+	}
+}
+
+func TestDiversify_HashOnMapPayload(t *testing.T) {
+	results := []ScoredPoint[map[string]interface{}]{
+		{ID: "x", Payload: map[string]interface{}{"h": "1"}},
+		{ID: "y", Payload: map[string]interface{}{"h": "1"}},
+		{ID: "z", Payload: map[string]interface{}{}},
+	}
+	params := &DiversityParams{Enabled: true, Strategy: DiversityByHash, HashField: "h"}
+	got := pointIDs(Diversify(results, params, 2))
+	if got[0] != "x" || got[1] != "z" {
+		t.Errorf("hash diversity = %v, want [x z]", got)
+	}
+}
+
+func TestDiversify_MinDistance(t *testing.T) {
+	params := &DiversityParams{Enabled: true, Strategy: DiversityByDistance, MinDistance: 0.2}
+	got := pointIDs(Diversify(diversityFixture(), params, 3))
+	// 2 is too close to 1, 4 is ~0.29 away from both 1 and 3
+	if got[0] != 1 || got[1] != 3 || got[2] != 4 {
+		t.Errorf("distance diversity = %v, want [1 3 4]", got)
+	}
+}
+
+func TestDiversify_FillsToTopK(t *testing.T) {
+	params := &DiversityParams{Enabled: true, Strategy: DiversityByDistance, MinDistance: 5}
+	got := pointIDs(Diversify(diversityFixture(), params, 3))
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("expected best skipped results to fill topK, got %v", got)
+	}
+
+	if n := len(Diversify(diversityFixture(), params, 10)); n != 4 {
+		t.Errorf("topK larger than results should return all results, got %d", n)
+	}
+}
+
+func TestDiversify_MMR(t *testing.T) {
+	relevanceOnly := &DiversityParams{Enabled: true, Strategy: DiversityByMMR, Lambda: 1}
+	got := pointIDs(Diversify(diversityFixture(), relevanceOnly, 2))
+	if got[0] != 1 || got[1] != 2 {
+		t.Errorf("lambda=1 should keep score order, got %v", got)
+	}
+
+	balanced := &DiversityParams{Enabled: true, Strategy: DiversityByMMR, Lambda: 0.5}
+	got = pointIDs(Diversify(diversityFixture(), balanced, 2))
+	if got[0] != 1 || got[1] != 3 {
+		t.Errorf("lambda=0.5 should skip the near-duplicate, got %v", got)
+	}
+}
+
+func TestDiversify_FromBuilt(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		VectorSearch("embedding", Vector{1, 0}, 2).
+		VectorDistance(L2Distance).
+		WithMinDistance(0.5).
+		Build()
+
+	params, topK := built.Diversity()
+	if params == nil || topK != 2 {
+		t.Fatalf("Diversity() = %v, %d", params, topK)
+	}
+	if params.DistanceMetric != L2Distance {
+		t.Errorf("DistanceMetric = %s, want L2", params.DistanceMetric)
+	}
+
+	jsonStr, err := built.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	var req QdrantSearchRequest
+	if err := json.Unmarshal([]byte(jsonStr), &req); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if !req.WithVector {
+		t.Errorf("min-distance diversity needs with_vector=true")
+	}
+
+	got := Diversify(diversityFixture(), params, topK)
+	if len(got) != 2 {
+		t.Errorf("expected 2 results, got %d", len(got))
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ============================================================================
+// Embedder: text => Vector for SemanticSearch()
+// ============================================================================
+
+// Embedder turns texts into vectors, one per text and in the same order
+// Model providers (OpenAI, Ollama, ONNX, ...) implement it outside xb
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([]Vector, error)
+}
+
+// EmbedderFunc adapts a function to Embedder
+type EmbedderFunc func(ctx context.Context, texts []string) ([]Vector, error)
+
+// Embed implements Embedder
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([]Vector, error) {
+	return f(ctx, texts)
+}
+
+// EmbedderCustom a Custom that carries the Embedder of its SemanticSearch() calls
+// QdrantCustom and MemoryVectorCustom implement it, see QdrantBuilder.Embedder()
+type EmbedderCustom interface {
+	Custom
+	QueryEmbedder() Embedder
+}
+
+// SemanticSearch vector search by text, the text is embedded by BuildContext()
+// The Embedder comes from BuilderX.Embedder() or the Custom (EmbedderCustom)
+// Build() panics while the text is not embedded, it never calls the Embedder
+//
+// Example:
+//
+//	built, err := xb.Of(&Doc{}).
+//	    Custom(xb.NewQdrantBuilder().Embedder(embedder).Build()).
+//	    Eq("lang", "en").
+//	    SemanticSearch("embedding", "how to retry a request", 10).
+//	    BuildContext(ctx)
+func (x *BuilderX) SemanticSearch(field string, text string, topK int) *BuilderX {
+	if field == "" || strings.TrimSpace(text) == "" {
+		return x
+	}
+	if topK <= 0 {
+		topK = 10 // Default value
+	}
+	x.bbs = append(x.bbs, Bb{
+		Op:  VECTOR_SEARCH,
+		Key: field,
+		Value: VectorSearchParams{
+			QueryText:      text,
+			TopK:           topK,
+			DistanceMetric: CosineDistance,
+		},
+	})
+	return x
+}
+
+// Embedder sets the Embedder of SemanticSearch(), it overrides the Custom's
+func (x *BuilderX) Embedder(embedder Embedder) *BuilderX {
+	x.embedder = embedder
+	return x
+}
+
+// BuildContext embeds the SemanticSearch() texts with ctx, then builds
+// Use it instead of Build() when the Embedder calls a remote model
+func (x *BuilderX) BuildContext(ctx context.Context) (*Built, error) {
+	if x == nil {
+		panic("xb.Builder is nil")
+	}
+	if err := x.resolveSemanticSearch(ctx); err != nil {
+		return nil, err
+	}
+	return x.Build(), nil
+}
+
+// pendingSemanticSearch indexes of the VECTOR_SEARCH bbs whose text is not embedded yet
+func (x *BuilderX) pendingSemanticSearch() []int {
+	var pending []int
+	for i, bb := range x.bbs {
+		if bb.Op != VECTOR_SEARCH {
+			continue
+		}
+		if params, ok := bb.Value.(VectorSearchParams); ok && params.QueryText != "" && params.QueryVector == nil {
+			pending = append(pending, i)
+		}
+	}
+	return pending
+}
+
+// resolveSemanticSearch embeds the pending texts in one Embed() call
+func (x *BuilderX) resolveSemanticSearch(ctx context.Context) error {
+	pending := x.pendingSemanticSearch()
+	if len(pending) == 0 {
+		return nil
+	}
+	texts := make([]string, len(pending))
+	for n, i := range pending {
+		texts[n] = x.bbs[i].Value.(VectorSearchParams).QueryText
+	}
+
+	embedder := x.embedder
+	if embedder == nil {
+		if ec, ok := x.customImpl.(EmbedderCustom); ok {
+			embedder = ec.QueryEmbedder()
+		}
+	}
+	if embedder == nil {
+		return fmt.Errorf("SemanticSearch(%q) requires an Embedder, set BuilderX.Embedder() or the Custom's", x.bbs[pending[0]].Key)
+	}
+
+	vectors, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("SemanticSearch: embed failed: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("SemanticSearch: embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+	for n, i := range pending {
+		if len(vectors[n]) == 0 {
+			return fmt.Errorf("SemanticSearch(%q): embedder returned an empty vector", x.bbs[i].Key)
+		}
+		params := x.bbs[i].Value.(VectorSearchParams)
+		params.QueryVector = vectors[n]
+		x.bbs[i].Value = params
+	}
+	return nil
+}
+
+// ============================================================================
+// EmbeddingCache: LRU in front of an Embedder
+// ============================================================================
+
+// EmbeddingCache LRU cache of text => Vector, only the missing texts reach the wrapped Embedder
+// Safe for concurrent use; cached vectors are shared, do not modify them
+//
+// Example:
+//
+//	embedder := xb.NewEmbeddingCache(openaiEmbedder, 10000)
+type EmbeddingCache struct {
+	embedder Embedder
+	size     int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // Front: most recently used
+}
+
+type embeddingEntry struct {
+	text   string
+	vector Vector
+}
+
+// NewEmbeddingCache wraps embedder, keeping at most size vectors
+func NewEmbeddingCache(embedder Embedder, size int) *EmbeddingCache {
+	if embedder == nil {
+		panic("NewEmbeddingCache() requires an Embedder")
+	}
+	if size < 1 {
+		panic(fmt.Sprintf("NewEmbeddingCache() size must be >= 1, got: %d", size))
+	}
+	return &EmbeddingCache{
+		embedder: embedder,
+		size:     size,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+	}
+}
+
+// Embed implements Embedder
+func (c *EmbeddingCache) Embed(ctx context.Context, texts []string) ([]Vector, error) {
+	out := make([]Vector, len(texts))
+	var missing []string
+	var missingIdx []int
+
+	c.mu.Lock()
+	for i, text := range texts {
+		if el, ok := c.entries[text]; ok {
+			c.lru.MoveToFront(el)
+			out[i] = el.Value.(*embeddingEntry).vector
+		} else {
+			missing = append(missing, text)
+			missingIdx = append(missingIdx, i)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(missing) == 0 {
+		return out, nil
+	}
+	vectors, err := c.embedder.Embed(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(missing) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(missing))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for n, i := range missingIdx {
+		out[i] = vectors[n]
+		c.put(missing[n], vectors[n])
+	}
+	return out, nil
+}
+
+func (c *EmbeddingCache) put(text string, vector Vector) {
+	if el, ok := c.entries[text]; ok {
+		el.Value.(*embeddingEntry).vector = vector
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[text] = c.lru.PushFront(&embeddingEntry{text: text, vector: vector})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*embeddingEntry).text)
+	}
+}
+
+// Len number of cached vectors
+func (c *EmbeddingCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// ============================================================================
+// HashEmbedder: deterministic fake for tests
+// ============================================================================
+
+// HashEmbedder deterministic bag-of-words embedder for tests and examples
+// Each lower-cased word is hashed (FNV-1a) into one of Dim buckets with a ±1 sign, then the
+// vector is L2-normalized: texts sharing words are close, identical texts are equal.
+// It has no semantics (synonyms are unrelated), never use it in production.
+type HashEmbedder struct {
+	Dim int
+}
+
+// NewHashEmbedder creates a HashEmbedder of dim dimensions
+func NewHashEmbedder(dim int) HashEmbedder {
+	return HashEmbedder{Dim: vectorSize(dim)}
+}
+
+// Embed implements Embedder
+func (e HashEmbedder) Embed(ctx context.Context, texts []string) ([]Vector, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]Vector, len(texts))
+	for i, text := range texts {
+		out[i] = e.embed(text)
+	}
+	return out, nil
+}
+
+func (e HashEmbedder) embed(text string) Vector {
+	v := make(Vector, vectorSize(e.Dim))
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		sum := h.Sum64()
+		if sum>>63 == 1 {
+			v[sum%uint64(e.Dim)]--
+		} else {
+			v[sum%uint64(e.Dim)]++
+		}
+	}
+	return v.Normalize()
+}
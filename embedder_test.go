@@ -0,0 +1,211 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// countingEmbedder records the texts of every Embed() call
+type countingEmbedder struct {
+	HashEmbedder
+	calls [][]string
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string) ([]Vector, error) {
+	e.calls = append(e.calls, append([]string(nil), texts...))
+	return e.HashEmbedder.Embed(ctx, texts)
+}
+
+func TestHashEmbedder_Deterministic(t *testing.T) {
+	e := NewHashEmbedder(64)
+	vs, err := e.Embed(context.Background(), []string{"Retry the request", "retry, the REQUEST!", "bake a cake", ""})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if vs[0].Dim() != 64 || vs[0].Distance(vs[1], CosineDistance) > 1e-6 {
+		t.Errorf("same words should embed equally: %v", vs[0].Distance(vs[1], CosineDistance))
+	}
+	if d := vs[0].Distance(vs[2], CosineDistance); d < 0.5 {
+		t.Errorf("unrelated texts too close: %v", d)
+	}
+	for _, f := range vs[3] {
+		if f != 0 {
+			t.Fatalf("empty text = %v", vs[3])
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := e.Embed(ctx, []string{"a"}); err == nil {
+		t.Error("cancelled context should fail")
+	}
+}
+
+func TestEmbeddingCache_LRU(t *testing.T) {
+	inner := &countingEmbedder{HashEmbedder: NewHashEmbedder(8)}
+	cache := NewEmbeddingCache(inner, 2)
+	ctx := context.Background()
+
+	first, _ := cache.Embed(ctx, []string{"a", "b"})
+	again, _ := cache.Embed(ctx, []string{"b", "a", "c"}) // c evicts b: a was used after b
+	if len(inner.calls) != 2 || strings.Join(inner.calls[1], ",") != "c" {
+		t.Fatalf("calls = %v", inner.calls)
+	}
+	if &again[1][0] != &first[0][0] {
+		t.Error("cached vector should be reused")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len = %d", cache.Len())
+	}
+
+	cache.Embed(ctx, []string{"a", "b"})
+	if strings.Join(inner.calls[2], ",") != "b" {
+		t.Errorf("calls = %v, want b re-embedded", inner.calls)
+	}
+
+	failing := NewEmbeddingCache(EmbedderFunc(func(context.Context, []string) ([]Vector, error) {
+		return nil, errors.New("model down")
+	}), 4)
+	if _, err := failing.Embed(ctx, []string{"a"}); err == nil || failing.Len() != 0 {
+		t.Errorf("err = %v, Len = %d", err, failing.Len())
+	}
+}
+
+func TestSemanticSearch_Build(t *testing.T) {
+	e := NewHashEmbedder(4)
+	want, _ := e.Embed(context.Background(), []string{"retry request"})
+
+	// Embedder of the builder, SQL
+	built, err := Of(&CodeVector{}).
+		Embedder(e).
+		Eq("language", "golang").
+		SemanticSearch("embedding", "retry request", 5).
+		VectorDistance(L2Distance).
+		BuildContext(context.Background())
+	if err != nil {
+		t.Fatalf("BuildContext failed: %v", err)
+	}
+	sql, args := built.SqlOfVectorSearch()
+	if sql != "SELECT *, embedding <#> ? AS distance FROM code_vectors WHERE language = ? ORDER BY distance LIMIT 5" {
+		t.Errorf("sql = %s", sql)
+	}
+	if v, ok := args[0].(Vector); !ok || v.Distance(want[0], L2Distance) != 0 {
+		t.Errorf("args = %v", args)
+	}
+
+	// Embedder of the Custom, one Embed() call for several searches
+	inner := &countingEmbedder{HashEmbedder: e}
+	built, err = Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Embedder(inner).Build()).
+		SemanticSearch("embedding", "retry request", 5).
+		BuildContext(context.Background())
+	if err != nil {
+		t.Fatalf("BuildContext failed: %v", err)
+	}
+	js, err := built.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	if len(inner.calls) != 1 || !strings.Contains(js, `"limit": 5`) {
+		t.Errorf("calls = %v, json = %s", inner.calls, js)
+	}
+
+	// Empty text is ignored like a nil vector
+	sql, _ = Of(&CodeVector{}).SemanticSearch("embedding", " ", 5).Build().SqlOfVectorSearch()
+	if strings.Contains(sql, "distance") {
+		t.Errorf("sql = %s", sql)
+	}
+}
+
+func TestSemanticSearch_BuildContext(t *testing.T) {
+	_, err := Of(&CodeVector{}).SemanticSearch("embedding", "text", 5).BuildContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "requires an Embedder") {
+		t.Errorf("err = %v", err)
+	}
+
+	down := EmbedderFunc(func(context.Context, []string) ([]Vector, error) {
+		return nil, errors.New("model down")
+	})
+	_, err = Of(&CodeVector{}).Embedder(down).SemanticSearch("embedding", "text", 5).BuildContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "model down") {
+		t.Errorf("err = %v", err)
+	}
+
+	short := EmbedderFunc(func(context.Context, []string) ([]Vector, error) {
+		return []Vector{}, nil
+	})
+	_, err = Of(&CodeVector{}).Embedder(short).SemanticSearch("embedding", "text", 5).BuildContext(context.Background())
+	if err == nil {
+		t.Error("vector count mismatch should fail")
+	}
+
+	// Build() never calls the Embedder, an unembedded text is a misuse
+	inner := &countingEmbedder{HashEmbedder: NewHashEmbedder(4)}
+	func() {
+		defer func() {
+			r := recover()
+			if r == nil || !strings.Contains(fmt.Sprint(r), "BuildContext") {
+				t.Errorf("Build() should panic with a BuildContext hint, got %v", r)
+			}
+		}()
+		Of(&CodeVector{}).Embedder(inner).SemanticSearch("embedding", "text", 5).Build()
+	}()
+	if len(inner.calls) != 0 {
+		t.Errorf("Build() called the Embedder: %v", inner.calls)
+	}
+}
+
+func TestMemoryVector_SemanticSearch(t *testing.T) {
+	e := NewHashEmbedder(32)
+	mem := NewMemoryVectorBuilder().Embedder(e).Build()
+	docs := map[int]string{
+		1: "retry the http request with backoff",
+		2: "bake bread with sourdough",
+		3: "http client timeout",
+	}
+	for id, text := range docs {
+		vs, _ := e.Embed(context.Background(), []string{text})
+		_, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+			Insert(func(ib *InsertBuilder) {
+				ib.Set("id", id).Set("embedding", vs[0]).Set("content", text)
+			}).
+			Build().
+			MemoryOfExec()
+		if err != nil {
+			t.Fatalf("insert %d failed: %v", id, err)
+		}
+	}
+
+	built, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		SemanticSearch("embedding", "retry http request", 2).
+		BuildContext(context.Background())
+	if err != nil {
+		t.Fatalf("BuildContext failed: %v", err)
+	}
+	results, err := MemorySearch[map[string]interface{}](built)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := memoryIDs(results); got != "1 3 " {
+		t.Errorf("ids = %s", got)
+	}
+}
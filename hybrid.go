@@ -0,0 +1,361 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HybridFusion how the legs of a hybrid search are merged
+type HybridFusion string
+
+const (
+	// FusionRRF Reciprocal Rank Fusion: sum(weight / (k + rank))
+	FusionRRF HybridFusion = "rrf"
+
+	// FusionWeighted weighted sum of min-max normalized scores
+	FusionWeighted HybridFusion = "weighted"
+
+	// FusionDBSF Distribution-Based Score Fusion (Qdrant native only)
+	FusionDBSF HybridFusion = "dbsf"
+)
+
+// HybridLegKind kind of a hybrid search leg
+type HybridLegKind string
+
+const (
+	HybridKeyword HybridLegKind = "keyword"
+	HybridVector  HybridLegKind = "vector"
+	HybridSparse  HybridLegKind = "sparse"
+)
+
+// HybridLeg one retrieval leg of a hybrid search
+type HybridLeg struct {
+	Kind           HybridLegKind
+	Field          string         // Text field (keyword) or vector field (vector, sparse)
+	Text           string         // Keyword query
+	QueryVector    Vector         // Vector query
+	QuerySparse    *SparseVector  // Sparse vector query
+	DistanceMetric VectorDistance // Vector metric
+	Limit          int            // Candidates fetched by this leg
+	Weight         float32        // Fusion weight (default 1)
+}
+
+// HybridParams hybrid search parameters
+type HybridParams struct {
+	Legs       []HybridLeg
+	Fusion     HybridFusion
+	RRFK       int    // RRF constant k (default 60)
+	Limit      int    // Final top-N (default 10)
+	IdKey      string // Id column used to merge legs in SQL (default "id")
+	TextConfig string // PostgreSQL text search config (default "simple")
+}
+
+// HybridBuilder hybrid search builder
+type HybridBuilder struct {
+	params HybridParams
+}
+
+// Hybrid keyword + vector search, legs fused with RRF (default) or weighted scores
+//
+// Scalar conditions (Eq/In/Gt...) apply to every leg.
+//
+// Example:
+//
+//	built := xb.Of(&Doc{}).
+//	    Eq("tenant_id", 42).
+//	    Hybrid(func(h *xb.HybridBuilder) {
+//	        h.Keyword("content", "golang generics").
+//	            Vector("embedding", queryVector).
+//	            Limit(10)
+//	    }).
+//	    Build()
+//
+//	sql, args, err := built.SqlOfHybridSearch() // PostgreSQL: CTE per leg + fusion
+//	json, err := built.JsonOfSelect()            // Qdrant: /points/query with prefetch + fusion
+func (x *BuilderX) Hybrid(fn func(h *HybridBuilder)) *BuilderX {
+	if fn == nil {
+		return x
+	}
+	h := &HybridBuilder{
+		params: HybridParams{
+			Fusion:     FusionRRF,
+			RRFK:       60,
+			Limit:      10,
+			IdKey:      "id",
+			TextConfig: "simple",
+		},
+	}
+	fn(h)
+	if len(h.params.Legs) == 0 {
+		return x
+	}
+	for i := range h.params.Legs {
+		if h.params.Legs[i].Limit <= 0 {
+			h.params.Legs[i].Limit = h.params.Limit * 5 // Default 5x over-fetch per leg
+		}
+	}
+	x.bbs = append(x.bbs, Bb{
+		Op:    HYBRID_SEARCH,
+		Value: h.params,
+	})
+	return x
+}
+
+// Keyword adds a full-text leg (PostgreSQL tsvector / Qdrant match.text)
+// Empty text is ignored
+func (h *HybridBuilder) Keyword(field string, text string) *HybridBuilder {
+	if field == "" || text == "" {
+		return h
+	}
+	h.params.Legs = append(h.params.Legs, HybridLeg{
+		Kind:   HybridKeyword,
+		Field:  field,
+		Text:   text,
+		Weight: 1,
+	})
+	return h
+}
+
+// Vector adds a vector leg, optional limit overrides the candidate count of this leg
+// Empty vectors are ignored
+func (h *HybridBuilder) Vector(field string, queryVector Vector, limit ...int) *HybridBuilder {
+	if field == "" || len(queryVector) == 0 {
+		return h
+	}
+	leg := HybridLeg{
+		Kind:           HybridVector,
+		Field:          field,
+		QueryVector:    queryVector,
+		DistanceMetric: CosineDistance,
+		Weight:         1,
+	}
+	if len(limit) > 0 {
+		leg.Limit = limit[0]
+	}
+	h.params.Legs = append(h.params.Legs, leg)
+	return h
+}
+
+// Sparse adds a sparse vector leg (SPLADE, BM42) scored by dot product,
+// optional limit overrides the candidate count of this leg
+// Empty vectors are ignored, invalid ones panic
+//
+// Example:
+//
+//	h.Vector("embedding", denseVec).
+//	    Sparse("text_sparse", xb.NewSparseVector(weights))
+func (h *HybridBuilder) Sparse(field string, query SparseVector, limit ...int) *HybridBuilder {
+	if field == "" || query.Len() == 0 {
+		return h
+	}
+	if err := query.Validate(); err != nil {
+		panic(fmt.Sprintf("Sparse(%q): %v", field, err))
+	}
+	leg := HybridLeg{
+		Kind:           HybridSparse,
+		Field:          field,
+		QuerySparse:    &query,
+		DistanceMetric: InnerProduct,
+		Weight:         1,
+	}
+	if len(limit) > 0 {
+		leg.Limit = limit[0]
+	}
+	h.params.Legs = append(h.params.Legs, leg)
+	return h
+}
+
+// Weight sets the fusion weight of the last added leg
+func (h *HybridBuilder) Weight(weight float32) *HybridBuilder {
+	if weight < 0 {
+		panic(fmt.Sprintf("Weight must be >= 0, got: %f", weight))
+	}
+	if n := len(h.params.Legs); n > 0 {
+		h.params.Legs[n-1].Weight = weight
+	}
+	return h
+}
+
+// Distance sets the distance metric of the last added vector leg
+func (h *HybridBuilder) Distance(metric VectorDistance) *HybridBuilder {
+	if n := len(h.params.Legs); n > 0 && h.params.Legs[n-1].Kind == HybridVector {
+		h.params.Legs[n-1].DistanceMetric = metric
+	}
+	return h
+}
+
+// Fusion sets the fusion method (FusionRRF by default)
+func (h *HybridBuilder) Fusion(fusion HybridFusion) *HybridBuilder {
+	h.params.Fusion = fusion
+	return h
+}
+
+// RRFK sets the RRF constant k (default 60)
+func (h *HybridBuilder) RRFK(k int) *HybridBuilder {
+	if k < 1 {
+		panic(fmt.Sprintf("RRFK must be >= 1, got: %d", k))
+	}
+	h.params.RRFK = k
+	return h
+}
+
+// Limit sets the final number of fused results (default 10)
+func (h *HybridBuilder) Limit(limit int) *HybridBuilder {
+	if limit > 0 {
+		h.params.Limit = limit
+	}
+	return h
+}
+
+// IdKey sets the id column used to merge legs in SQL (default "id")
+func (h *HybridBuilder) IdKey(key string) *HybridBuilder {
+	if key != "" {
+		h.params.IdKey = key
+	}
+	return h
+}
+
+// TextConfig sets the PostgreSQL text search configuration (default "simple")
+func (h *HybridBuilder) TextConfig(config string) *HybridBuilder {
+	if config != "" {
+		h.params.TextConfig = config
+	}
+	return h
+}
+
+// Hybrid returns the hybrid search parameters, nil if Hybrid() was not used
+func (built *Built) Hybrid() *HybridParams {
+	for _, bb := range built.Conds {
+		if bb.Op == HYBRID_SEARCH {
+			params := bb.Value.(HybridParams)
+			return &params
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Client-side fusion (for backends without native support)
+// ============================================================================
+
+// FuseRRF merges ranked lists with Reciprocal Rank Fusion
+// score(d) = sum over lists of 1 / (k + rank), rank starts at 1
+// Points are matched by ID, payload and vector are taken from the first occurrence
+func FuseRRF[T any](k int, lists ...[]ScoredPoint[T]) []ScoredPoint[T] {
+	weights := make([]float32, len(lists))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return fuseRRF(k, weights, lists)
+}
+
+// FuseWeighted merges lists with a weighted sum of min-max normalized scores
+// Each list is normalized to [0, 1] (ascending lists, i.e. distances, are flipped)
+// Missing weights default to 1
+func FuseWeighted[T any](weights []float32, lists ...[]ScoredPoint[T]) []ScoredPoint[T] {
+	fused := newFusion[T]()
+	for i, list := range lists {
+		if len(list) == 0 {
+			continue
+		}
+		w := legWeight(weights, i)
+		rel := normalizedRelevance(list)
+		for j, p := range list {
+			fused.add(p, w*rel[j])
+		}
+	}
+	return fused.sorted()
+}
+
+// FuseHybrid fuses per-leg results with the Fusion, weights and Limit of params
+// lists must be in the order of params.Legs
+func FuseHybrid[T any](params *HybridParams, lists ...[]ScoredPoint[T]) ([]ScoredPoint[T], error) {
+	if params == nil {
+		return nil, fmt.Errorf("no hybrid parameters")
+	}
+	weights := make([]float32, len(params.Legs))
+	for i, leg := range params.Legs {
+		weights[i] = leg.Weight
+	}
+
+	var fused []ScoredPoint[T]
+	switch params.Fusion {
+	case FusionRRF, "":
+		fused = fuseRRF(params.RRFK, weights, lists)
+	case FusionWeighted:
+		fused = FuseWeighted(weights, lists...)
+	default:
+		return nil, fmt.Errorf("fusion %q is not supported client-side", params.Fusion)
+	}
+
+	if params.Limit > 0 && len(fused) > params.Limit {
+		fused = fused[:params.Limit]
+	}
+	return fused, nil
+}
+
+func fuseRRF[T any](k int, weights []float32, lists [][]ScoredPoint[T]) []ScoredPoint[T] {
+	if k < 1 {
+		k = 60
+	}
+	fused := newFusion[T]()
+	for i, list := range lists {
+		w := legWeight(weights, i)
+		for rank, p := range list {
+			fused.add(p, w/float32(k+rank+1))
+		}
+	}
+	return fused.sorted()
+}
+
+func legWeight(weights []float32, i int) float32 {
+	if i < len(weights) {
+		return weights[i]
+	}
+	return 1
+}
+
+// fusion accumulates scores per point ID, keeping first-seen order for ties
+type fusion[T any] struct {
+	index  map[string]int
+	points []ScoredPoint[T]
+}
+
+func newFusion[T any]() *fusion[T] {
+	return &fusion[T]{index: make(map[string]int)}
+}
+
+func (f *fusion[T]) add(p ScoredPoint[T], score float32) {
+	key := fmt.Sprint(p.ID)
+	if i, ok := f.index[key]; ok {
+		f.points[i].Score += score
+		return
+	}
+	p.Score = score
+	f.index[key] = len(f.points)
+	f.points = append(f.points, p)
+}
+
+func (f *fusion[T]) sorted() []ScoredPoint[T] {
+	out := append([]ScoredPoint[T](nil), f.points...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Score > out[j].Score
+	})
+	return out
+}
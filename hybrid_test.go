@@ -0,0 +1,241 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHybrid_SqlRRF(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Eq("language", "golang").
+		Hybrid(func(h *HybridBuilder) {
+			h.Keyword("content", "generic constraints").
+				Vector("embedding", Vector{0.1, 0.2}, 20).Weight(2).
+				Limit(5)
+		}).
+		Build()
+
+	sql, args, err := built.SqlOfHybridSearch()
+	if err != nil {
+		t.Fatalf("SqlOfHybridSearch failed: %v", err)
+	}
+	t.Logf("SQL: %s", sql)
+
+	want := "WITH leg_0 AS (SELECT id, ROW_NUMBER() OVER (ORDER BY score DESC) AS rank, " +
+		"COALESCE((score - MIN(score) OVER ()) / NULLIF(MAX(score) OVER () - MIN(score) OVER (), 0), 1) AS norm " +
+		"FROM (SELECT id, ts_rank(to_tsvector(?::regconfig, content), plainto_tsquery(?::regconfig, ?)) AS score FROM code_vectors " +
+		"WHERE to_tsvector(?::regconfig, content) @@ plainto_tsquery(?::regconfig, ?) AND (language = ?) ORDER BY score DESC LIMIT 25) c), " +
+		"leg_1 AS (SELECT id, ROW_NUMBER() OVER (ORDER BY distance) AS rank, " +
+		"COALESCE((MAX(distance) OVER () - distance) / NULLIF(MAX(distance) OVER () - MIN(distance) OVER (), 0), 1) AS norm " +
+		"FROM (SELECT id, embedding <-> ? AS distance FROM code_vectors WHERE language = ? ORDER BY distance LIMIT 20) c) " +
+		"SELECT id, SUM(score) AS score FROM (" +
+		"SELECT id, 1.0 / (60 + rank) AS score FROM leg_0 UNION ALL " +
+		"SELECT id, 2.0 / (60 + rank) AS score FROM leg_1) fused GROUP BY id ORDER BY score DESC LIMIT 5"
+	if sql != want {
+		t.Errorf("SQL mismatch\n got: %s\nwant: %s", sql, want)
+	}
+	if len(args) != 9 || args[0] != "simple" || args[2] != "generic constraints" || args[5] != "generic constraints" ||
+		args[6] != "golang" || args[8] != "golang" {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestHybrid_SqlTextConfigIsBound(t *testing.T) {
+	config := "english', content) OR true --"
+	sql, args, err := Of(&CodeVectorForQdrant{}).
+		Hybrid(func(h *HybridBuilder) {
+			h.Keyword("content", "rust").TextConfig(config)
+		}).
+		Build().
+		SqlOfHybridSearch()
+	if err != nil {
+		t.Fatalf("SqlOfHybridSearch failed: %v", err)
+	}
+	if strings.Contains(sql, config) || !strings.Contains(sql, "to_tsvector(?::regconfig, content)") {
+		t.Errorf("sql = %s", sql)
+	}
+	if args[0] != config || args[1] != config || args[3] != config || args[4] != config {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestHybrid_SqlWeightedAndPaged(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Hybrid(func(h *HybridBuilder) {
+			h.Keyword("content", "rust").Weight(0.3).
+				Vector("embedding", Vector{1, 0}).Weight(0.7).Distance(L2Distance).
+				Fusion(FusionWeighted)
+		}).
+		Paged(func(pb *PageBuilder) {
+			pb.Page(2).Rows(10)
+		}).
+		Build()
+
+	sql, _, err := built.SqlOfHybridSearch()
+	if err != nil {
+		t.Fatalf("SqlOfHybridSearch failed: %v", err)
+	}
+	for _, part := range []string{
+		"embedding <#> ? AS distance",
+		"SELECT id, 0.3 * norm AS score FROM leg_0",
+		"SELECT id, 0.7 * norm AS score FROM leg_1",
+		"LIMIT 10 OFFSET 10",
+	} {
+		if !strings.Contains(sql, part) {
+			t.Errorf("SQL should contain %q: %s", part, sql)
+		}
+	}
+
+	dbsf := Of(&CodeVectorForQdrant{}).
+		Hybrid(func(h *HybridBuilder) {
+			h.Vector("embedding", Vector{1, 0}).Fusion(FusionDBSF)
+		}).
+		Build()
+	if _, _, err := dbsf.SqlOfHybridSearch(); err == nil {
+		t.Errorf("dbsf should not be supported in SQL")
+	}
+}
+
+func TestHybrid_QdrantQuery(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().HnswEf(256).Build()).
+		Eq("language", "golang").
+		Hybrid(func(h *HybridBuilder) {
+			h.Keyword("content", "error handling").
+				Vector("embedding", Vector{0.1, 0.2}, 30).
+				Limit(8)
+		}).
+		Build()
+
+	jsonStr, err := built.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	t.Logf("JSON:\n%s", jsonStr)
+
+	var req QdrantQueryRequest
+	if err := json.Unmarshal([]byte(jsonStr), &req); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if req.Limit != 8 || len(req.Prefetch) != 2 || req.Params != nil {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if q, ok := req.Query.(map[string]interface{}); !ok || q["fusion"] != "rrf" {
+		t.Errorf("query should be rrf fusion, got %v", req.Query)
+	}
+
+	keyword, vector := req.Prefetch[0], req.Prefetch[1]
+	if keyword.Limit != 40 || len(keyword.Filter.Must) != 2 || keyword.Filter.Must[1].Match.Text != "error handling" {
+		t.Errorf("keyword prefetch should add match.text, got %+v", keyword)
+	}
+	if vector.Limit != 30 || len(vector.Filter.Must) != 1 || vector.Using != "" {
+		t.Errorf("unexpected vector prefetch: %+v", vector)
+	}
+	if vector.Params == nil || vector.Params.HnswEf != 256 {
+		t.Errorf("prefetch should carry hnsw_ef, got %+v", vector.Params)
+	}
+}
+
+func TestHybrid_QdrantNamedVectorsAndErrors(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Hybrid(func(h *HybridBuilder) {
+			h.Vector("dense", Vector{1, 0}).
+				Vector("title", Vector{0, 1}).
+				Fusion(FusionDBSF)
+		}).
+		Build()
+
+	jsonStr, err := built.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	var req QdrantQueryRequest
+	if err := json.Unmarshal([]byte(jsonStr), &req); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if req.Prefetch[0].Using != "dense" || req.Prefetch[1].Using != "title" {
+		t.Errorf("different vector fields should set using: %+v", req.Prefetch)
+	}
+	if q := req.Query.(map[string]interface{}); q["fusion"] != "dbsf" {
+		t.Errorf("expected dbsf fusion, got %v", q)
+	}
+
+	weighted := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Hybrid(func(h *HybridBuilder) {
+			h.Vector("embedding", Vector{1, 0}).Fusion(FusionWeighted)
+		}).
+		Build()
+	if _, err := weighted.JsonOfSelect(); err == nil {
+		t.Errorf("weighted fusion is not native in Qdrant, expected error")
+	}
+
+	keywordOnly := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Hybrid(func(h *HybridBuilder) {
+			h.Keyword("content", "go")
+		}).
+		Build()
+	if _, err := keywordOnly.JsonOfSelect(); err == nil {
+		t.Errorf("keyword-only hybrid needs a vector leg in Qdrant, expected error")
+	}
+}
+
+func TestFuseRRF(t *testing.T) {
+	keyword := []ScoredPoint[map[string]interface{}]{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	vector := []ScoredPoint[map[string]interface{}]{{ID: "b"}, {ID: "d"}, {ID: "a"}}
+
+	got := FuseRRF(60, keyword, vector)
+	ids := pointIDs(got)
+	if len(ids) != 4 || ids[0] != "b" || ids[1] != "a" || ids[2] != "d" || ids[3] != "c" {
+		t.Errorf("RRF order = %v, want [b a d c]", ids)
+	}
+	want := float32(1.0/62 + 1.0/61)
+	if diff := got[0].Score - want; diff > 1e-6 || diff < -1e-6 {
+		t.Errorf("RRF score of b = %f, want %f", got[0].Score, want)
+	}
+}
+
+func TestFuseWeighted(t *testing.T) {
+	// keyword scores descend, vector distances ascend: both normalize to "best = 1"
+	keyword := []ScoredPoint[map[string]interface{}]{{ID: 1, Score: 9}, {ID: 2, Score: 3}}
+	vector := []ScoredPoint[map[string]interface{}]{{ID: 2, Score: 0.1}, {ID: 3, Score: 0.5}}
+
+	ids := pointIDs(FuseWeighted([]float32{0.2, 0.8}, keyword, vector))
+	if ids[0] != 2 || ids[1] != 1 || ids[2] != 3 {
+		t.Errorf("weighted order = %v, want [2 1 3]", ids)
+	}
+
+	params := &HybridParams{
+		Legs:   []HybridLeg{{Weight: 0.2}, {Weight: 0.8}},
+		Fusion: FusionWeighted,
+		Limit:  1,
+	}
+	fused, err := FuseHybrid(params, keyword, vector)
+	if err != nil || len(fused) != 1 || fused[0].ID != 2 {
+		t.Errorf("FuseHybrid = %v, %v", fused, err)
+	}
+
+	params.Fusion = FusionDBSF
+	if _, err := FuseHybrid(params, keyword, vector); err == nil {
+		t.Errorf("dbsf is not supported client-side, expected error")
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// ============================================================================
+// HNSW graph of MemoryVectorCustom
+// ============================================================================
+//
+// Hierarchical Navigable Small World (Malkov & Yashunin): a greedy descent through
+// sparse upper layers, then a best-first search with ef candidates on layer 0.
+// Approximate: for fixtures of thousands of points, not a production index.
+
+type hnswGraph struct {
+	m, m0          int // links per node on upper layers / layer 0
+	efConstruction int
+	metric         VectorDistance
+	levelMult      float64
+	rng            *rand.Rand
+
+	nodes    []hnswNode
+	entry    int
+	maxLevel int
+}
+
+type hnswNode struct {
+	point *memoryPoint
+	vec   Vector
+	links [][]int // links[level] neighbour node ids
+}
+
+// newHNSWGraph creates an empty graph, seeded so results are reproducible
+func newHNSWGraph(m int, metric VectorDistance) *hnswGraph {
+	return &hnswGraph{
+		m:              m,
+		m0:             2 * m,
+		efConstruction: max(100, 2*m),
+		metric:         metric,
+		levelMult:      1 / math.Log(float64(m)),
+		rng:            rand.New(rand.NewSource(42)),
+		entry:          -1,
+	}
+}
+
+func (g *hnswGraph) distance(a, b Vector) float32 {
+	return a.Distance(b, g.metric)
+}
+
+// add inserts a point
+func (g *hnswGraph) add(p *memoryPoint, vec Vector) {
+	level := int(-math.Log(1-g.rng.Float64()) * g.levelMult)
+	id := len(g.nodes)
+	g.nodes = append(g.nodes, hnswNode{point: p, vec: vec, links: make([][]int, level+1)})
+	if g.entry < 0 {
+		g.entry, g.maxLevel = id, level
+		return
+	}
+
+	entry := g.entry
+	for l := g.maxLevel; l > level; l-- {
+		entry = g.greedy(vec, entry, l)
+	}
+	for l := min(level, g.maxLevel); l >= 0; l-- {
+		candidates := g.searchLayer(vec, []int{entry}, g.efConstruction, l, nil)
+		neighbours := g.closest(candidates, g.maxLinks(l))
+		g.nodes[id].links[l] = neighbours
+		for _, n := range neighbours {
+			g.link(n, id, l)
+		}
+		entry = candidates[0].id
+	}
+	if level > g.maxLevel {
+		g.entry, g.maxLevel = id, level
+	}
+}
+
+func (g *hnswGraph) maxLinks(level int) int {
+	if level == 0 {
+		return g.m0
+	}
+	return g.m
+}
+
+// link adds to -> from, keeping the closest maxLinks neighbours
+func (g *hnswGraph) link(from, to, level int) {
+	links := append(g.nodes[from].links[level], to)
+	if len(links) > g.maxLinks(level) {
+		candidates := make([]hnswCandidate, len(links))
+		for i, n := range links {
+			candidates[i] = hnswCandidate{id: n, distance: g.distance(g.nodes[from].vec, g.nodes[n].vec)}
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+		links = g.closest(candidates, g.maxLinks(level))
+	}
+	g.nodes[from].links[level] = links
+}
+
+// closest ids of the first n candidates, candidates are sorted by distance
+func (g *hnswGraph) closest(candidates []hnswCandidate, n int) []int {
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	ids := make([]int, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+// greedy moves to the closest neighbour until no neighbour is closer
+func (g *hnswGraph) greedy(query Vector, entry, level int) int {
+	best := g.distance(query, g.nodes[entry].vec)
+	for changed := true; changed; {
+		changed = false
+		for _, n := range g.nodes[entry].links[level] {
+			if d := g.distance(query, g.nodes[n].vec); d < best {
+				entry, best, changed = n, d, true
+			}
+		}
+	}
+	return entry
+}
+
+// searchLayer best-first search keeping ef results, sorted by distance
+// accept (optional) limits which nodes count as results, every node is still traversed
+func (g *hnswGraph) searchLayer(query Vector, entries []int, ef, level int, accept func(id int) bool) []hnswCandidate {
+	visited := map[int]bool{}
+	candidates := &hnswHeap{}
+	results := &hnswHeap{max: true}
+	for _, e := range entries {
+		visited[e] = true
+		c := hnswCandidate{id: e, distance: g.distance(query, g.nodes[e].vec)}
+		heap.Push(candidates, c)
+		if accept == nil || accept(e) {
+			heap.Push(results, c)
+		}
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswCandidate)
+		if results.Len() >= ef && c.distance > results.items[0].distance {
+			break
+		}
+		for _, n := range g.nodes[c.id].links[level] {
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			d := g.distance(query, g.nodes[n].vec)
+			if results.Len() < ef || d < results.items[0].distance {
+				heap.Push(candidates, hnswCandidate{id: n, distance: d})
+				if accept == nil || accept(n) {
+					heap.Push(results, hnswCandidate{id: n, distance: d})
+					if results.Len() > ef {
+						heap.Pop(results)
+					}
+				}
+			}
+		}
+	}
+
+	out := append([]hnswCandidate(nil), results.items...)
+	sort.Slice(out, func(i, j int) bool { return out[i].distance < out[j].distance })
+	return out
+}
+
+// filteredSearch k nearest points that pass matches, fewer if the graph search misses them
+func (g *hnswGraph) filteredSearch(query Vector, k, ef int, matches func(p *memoryPoint) (bool, error)) ([]memoryHit, error) {
+	if g.entry < 0 || len(query) != len(g.nodes[g.entry].vec) {
+		return nil, nil
+	}
+	entry := g.entry
+	for l := g.maxLevel; l > 0; l-- {
+		entry = g.greedy(query, entry, l)
+	}
+
+	var matchErr error
+	accept := func(id int) bool {
+		ok, err := matches(g.nodes[id].point)
+		if err != nil && matchErr == nil {
+			matchErr = err
+		}
+		return ok
+	}
+	candidates := g.searchLayer(query, []int{entry}, max(ef, k), 0, accept)
+	if matchErr != nil {
+		return nil, matchErr
+	}
+
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+	hits := make([]memoryHit, len(candidates))
+	for i, c := range candidates {
+		hits[i] = memoryHit{point: g.nodes[c.id].point, distance: c.distance}
+	}
+	return hits, nil
+}
+
+type hnswCandidate struct {
+	id       int
+	distance float32
+}
+
+// hnswHeap min-heap by distance, max-heap if max
+type hnswHeap struct {
+	items []hnswCandidate
+	max   bool
+}
+
+func (h hnswHeap) Len() int { return len(h.items) }
+func (h hnswHeap) Less(i, j int) bool {
+	if h.max {
+		return h.items[i].distance > h.items[j].distance
+	}
+	return h.items[i].distance < h.items[j].distance
+}
+func (h hnswHeap) Swap(i, j int)       { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *hnswHeap) Push(x interface{}) { h.items = append(h.items, x.(hnswCandidate)) }
+func (h *hnswHeap) Pop() interface{} {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMemoryVector_HNSWRecall(t *testing.T) {
+	const n, dim, k = 2000, 16, 10
+	rng := rand.New(rand.NewSource(1))
+	randomVector := func() Vector {
+		v := make(Vector, dim)
+		for i := range v {
+			v[i] = rng.Float32()*2 - 1
+		}
+		return v
+	}
+
+	exact := NewMemoryVectorBuilder().Build()
+	approx := NewMemoryVectorBuilder().HNSW(16, 64).Build()
+	for i := 1; i <= n; i++ {
+		payload := map[string]interface{}{"id": i, "embedding": randomVector(), "even": i%2 == 0}
+		if err := exact.Put("points", payload); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if err := approx.Put("points", payload); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	found, total := 0, 0
+	for q := 0; q < 20; q++ {
+		query := randomVector()
+		search := func(custom *MemoryVectorCustom) []ScoredPoint[map[string]interface{}] {
+			results, err := Of("points").Custom(custom).
+				Eq("even", true).
+				VectorSearch("embedding", query, k).
+				Build().
+				MemoryOfSelect()
+			if err != nil {
+				t.Fatalf("search failed: %v", err)
+			}
+			return results
+		}
+		want := map[interface{}]bool{}
+		for _, r := range search(exact) {
+			want[r.ID] = true
+		}
+		got := search(approx)
+		if len(got) != k {
+			t.Fatalf("want %d results, got %d", k, len(got))
+		}
+		for _, r := range got {
+			if r.Payload["even"] != true {
+				t.Fatalf("filter not applied: %v", r.Payload)
+			}
+			if want[r.ID] {
+				found++
+			}
+		}
+		total += k
+	}
+	recall := float64(found) / float64(total)
+	t.Logf("recall@%d: %.2f", k, recall)
+	if recall < 0.9 {
+		t.Errorf("recall %.2f < 0.9", recall)
+	}
+}
+
+func TestMemoryVector_HNSWRebuildAfterWrite(t *testing.T) {
+	mem := NewMemoryVectorBuilder().HNSW(4, 16).Build()
+	memoryFixture(t, mem)
+	search := func() string {
+		results, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+			VectorSearch("embedding", Vector{0, 0, 1}, 1).
+			Build().
+			MemoryOfSelect()
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		return memoryIDs(results)
+	}
+	if got := search(); got != "5 " {
+		t.Fatalf("ids = %s", got)
+	}
+	if _, err := Of(&CodeVectorForQdrant{}).Custom(mem).Eq("id", 5).Build().MemoryOfDelete(); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if got := search(); got == "5 " {
+		t.Errorf("deleted point still found")
+	}
+}
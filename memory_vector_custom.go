@@ -0,0 +1,855 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// MemoryVectorBuilder: Builder Pattern Configuration Builder
+// ============================================================================
+
+// MemoryVectorBuilder in-memory vector store configuration builder
+type MemoryVectorBuilder struct {
+	custom *MemoryVectorCustom
+}
+
+// NewMemoryVectorBuilder creates an in-memory vector store
+// The same Built runs against it as against Qdrant, for unit tests and CLI tools
+//
+// Example:
+//
+//	mem := xb.NewMemoryVectorBuilder().Build()
+//
+//	xb.Of(&CodeVector{}).Custom(mem).
+//	    Insert(func(ib *xb.InsertBuilder) {
+//	        ib.Set("id", 1).Set("embedding", vec).Set("language", "golang")
+//	    }).
+//	    Build().
+//	    MemoryOfExec()
+//
+//	results, err := xb.MemorySearch[CodeVector](
+//	    xb.Of(&CodeVector{}).Custom(mem).
+//	        Eq("language", "golang").
+//	        VectorSearch("embedding", queryVector, 10).
+//	        Build())
+func NewMemoryVectorBuilder() *MemoryVectorBuilder {
+	return &MemoryVectorBuilder{
+		custom: newMemoryVectorCustom(),
+	}
+}
+
+// HNSW searches with an HNSW graph instead of brute force, for larger fixtures
+// m: links per node (16 is a good default), efSearch: candidate list size of a search (>= topK)
+// The graph is built on the first search after a write, filtered searches that find
+// fewer than topK points fall back to brute force
+func (mb *MemoryVectorBuilder) HNSW(m, efSearch int) *MemoryVectorBuilder {
+	if m < 2 {
+		panic(fmt.Sprintf("HNSW m must be >= 2, got: %d", m))
+	}
+	if efSearch <= 0 {
+		panic(fmt.Sprintf("HNSW efSearch must be > 0, got: %d", efSearch))
+	}
+	mb.custom.HnswM = m
+	mb.custom.HnswEf = efSearch
+	return mb
+}
+
+// Embedder sets the Embedder of SemanticSearch() queries using this Custom
+func (mb *MemoryVectorBuilder) Embedder(embedder Embedder) *MemoryVectorBuilder {
+	mb.custom.embedder = embedder
+	return mb
+}
+
+// Build constructs and returns MemoryVectorCustom
+func (mb *MemoryVectorBuilder) Build() *MemoryVectorCustom {
+	return mb.custom
+}
+
+// ============================================================================
+// MemoryVectorCustom: In-Memory Vector Store
+// ============================================================================
+
+// MemoryVectorCustom in-process vector store implementing Custom
+//
+// Generate() runs the Built instead of generating a query:
+//   - select: []ScoredPoint[map[string]interface{}], see MemoryOfSelect() / MemorySearch[T]()
+//   - insert / update / delete: int, the number of points written, see MemoryOfExec() / MemoryOfDelete()
+//
+// Collections are the table names of Of(), points are keyed by the "id" field.
+// Every inserted field is kept in the payload; Vector, []float32 and []float64 fields
+// can be searched; like Qdrant, the payload modes of UpdateBuilder keep them and DeleteVectors()
+// removes only them. Results are copies. Filters have the semantics of QdrantFilter.Matches.
+// Score is the Qdrant score: cosine similarity (CosineDistance), the dot product (InnerProduct),
+// the distance (L2Distance as Qdrant Euclid, HammingDistance: lower is more similar).
+// MultiVector fields are searched by MultiVectorSearch(), Score is MaxSim().
+// SparseVector fields are searched by SparseVectorSearch(), Score is Dot().
+// QdrantX ScoreThreshold() keeps scores >= threshold (<= for the distance scores).
+type MemoryVectorCustom struct {
+	HnswM  int // HNSW links per node, 0 means brute force
+	HnswEf int // HNSW search candidate list size
+
+	embedder    Embedder // SemanticSearch() embedder
+	mu          sync.RWMutex
+	collections map[string]*memoryCollection
+}
+
+type memoryCollection struct {
+	points  []*memoryPoint
+	index   map[PointID]int
+	graphs  map[memoryGraphKey]*hnswGraph // Built lazily, dropped on write
+	version int
+}
+
+type memoryGraphKey struct {
+	field  string
+	metric VectorDistance
+}
+
+type memoryPoint struct {
+	id      PointID
+	payload map[string]interface{}
+	vectors map[string]Vector
+	multi   map[string]MultiVector
+	sparse  map[string]SparseVector
+}
+
+// newMemoryVectorCustom internal function: creates an empty store
+func newMemoryVectorCustom() *MemoryVectorCustom {
+	return &MemoryVectorCustom{collections: map[string]*memoryCollection{}}
+}
+
+// QueryEmbedder implements EmbedderCustom
+func (c *MemoryVectorCustom) QueryEmbedder() Embedder {
+	return c.embedder
+}
+
+// Generate implements Custom interface
+func (c *MemoryVectorCustom) Generate(built *Built) (interface{}, error) {
+	collection := strings.TrimSpace(built.OrFromSql)
+	if collection == "" {
+		return nil, fmt.Errorf("memory collection is empty, use Of(&Po{}) or Of(\"collection\")")
+	}
+	switch {
+	case built.Inserts != nil && len(*built.Inserts) > 0:
+		return c.insert(collection, *built.Inserts)
+	case built.Updates != nil && len(*built.Updates) > 0:
+		return c.update(collection, built)
+	case built.Delete:
+		return c.delete(collection, built)
+	}
+	return c.selectPoints(collection, built)
+}
+
+// MemoryOfSelect runs the select Built against MemoryVectorCustom
+func (built *Built) MemoryOfSelect() ([]ScoredPoint[map[string]interface{}], error) {
+	result, err := built.generateMemory()
+	if err != nil {
+		return nil, err
+	}
+	if points, ok := result.([]ScoredPoint[map[string]interface{}]); ok {
+		return points, nil
+	}
+	return nil, fmt.Errorf("MemoryOfSelect() requires a select Built, got: %T", result)
+}
+
+// MemoryOfExec runs the insert or update Built against MemoryVectorCustom
+// Returns the number of points written
+func (built *Built) MemoryOfExec() (int, error) {
+	result, err := built.generateMemory()
+	if err != nil {
+		return 0, err
+	}
+	if n, ok := result.(int); ok {
+		return n, nil
+	}
+	return 0, fmt.Errorf("MemoryOfExec() requires an insert or update Built, use MemoryOfDelete() for deletes")
+}
+
+// MemoryOfDelete deletes the points matching the Built's conditions
+// Returns the number of points deleted
+func (built *Built) MemoryOfDelete() (int, error) {
+	// ⭐ Automatically set Delete flag, like JsonOfDelete()
+	built.Delete = true
+	return built.MemoryOfExec()
+}
+
+// MemorySearch runs the select Built and decodes each payload into T by json tags
+// A Rerank() stage is applied to the decoded results
+func MemorySearch[T any](built *Built) ([]ScoredPoint[T], error) {
+	points, err := built.MemoryOfSelect()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ScoredPoint[T], len(points))
+	for i, p := range points {
+		bytes, err := json.Marshal(p.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("point %v: %w", p.ID, err)
+		}
+		if err := json.Unmarshal(bytes, &out[i].Payload); err != nil {
+			return nil, fmt.Errorf("point %v: %w", p.ID, err)
+		}
+		out[i].ID, out[i].Score, out[i].Vector = p.ID, p.Score, p.Vector
+	}
+	return ApplyRerank(context.Background(), built, out)
+}
+
+func (built *Built) generateMemory() (interface{}, error) {
+	if _, ok := built.Custom.(*MemoryVectorCustom); !ok {
+		return nil, fmt.Errorf("Custom is %T, use Custom(xb.NewMemoryVectorBuilder().Build())", built.Custom)
+	}
+	return built.Custom.Generate(built)
+}
+
+// Put stores a point directly, without a Built; payload must contain "id"
+// Handy for loading fixtures
+func (c *MemoryVectorCustom) Put(collection string, payload map[string]interface{}) error {
+	id, err := ParsePointID(payload["id"])
+	if err != nil {
+		return fmt.Errorf("point id: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.collection(collection).put(newMemoryPoint(id, maps.Clone(payload)))
+	return nil
+}
+
+// Len number of points in the collection
+func (c *MemoryVectorCustom) Len(collection string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if col, ok := c.collections[collection]; ok {
+		return len(col.points)
+	}
+	return 0
+}
+
+// collection gets or creates a collection, callers hold the write lock
+func (c *MemoryVectorCustom) collection(name string) *memoryCollection {
+	col, ok := c.collections[name]
+	if !ok {
+		col = &memoryCollection{index: map[PointID]int{}}
+		c.collections[name] = col
+	}
+	return col
+}
+
+func newMemoryPoint(id PointID, payload map[string]interface{}) *memoryPoint {
+	p := &memoryPoint{id: id, payload: payload}
+	p.reindex()
+	return p
+}
+
+// reindex collects the vector fields of the payload
+func (p *memoryPoint) reindex() {
+	p.vectors = map[string]Vector{}
+	p.multi = map[string]MultiVector{}
+	p.sparse = map[string]SparseVector{}
+	for k, v := range p.payload {
+		if vec, ok := memoryVectorOf(v); ok {
+			p.vectors[k] = vec
+		} else if multi, ok := memoryMultiVectorOf(v); ok {
+			p.multi[k] = multi
+		} else if sv, ok := v.(SparseVector); ok {
+			p.sparse[k] = sv
+		}
+	}
+}
+
+// isVector the payload field is one of the point's vectors
+func (p *memoryPoint) isVector(key string) bool {
+	if _, ok := p.vectors[key]; ok {
+		return true
+	}
+	if _, ok := p.multi[key]; ok {
+		return true
+	}
+	_, ok := p.sparse[key]
+	return ok
+}
+
+func memoryVectorOf(v interface{}) (Vector, bool) {
+	switch vec := v.(type) {
+	case Vector:
+		return vec, len(vec) > 0
+	case []float32:
+		return vec, len(vec) > 0
+	case []float64:
+		out := make(Vector, len(vec))
+		for i, f := range vec {
+			out[i] = float32(f)
+		}
+		return out, len(out) > 0
+	case HalfVector:
+		return vec.ToVector(), len(vec) > 0
+	case Int8Vector:
+		return vec.ToVector(), len(vec.Values) > 0
+	}
+	return nil, false
+}
+
+func memoryMultiVectorOf(v interface{}) (MultiVector, bool) {
+	switch multi := v.(type) {
+	case MultiVector:
+		return multi, len(multi) > 0
+	case []Vector:
+		return multi, len(multi) > 0
+	case [][]float32:
+		out := make(MultiVector, len(multi))
+		for i, row := range multi {
+			out[i] = row
+		}
+		return out, len(out) > 0
+	}
+	return nil, false
+}
+
+func (col *memoryCollection) put(p *memoryPoint) {
+	if i, ok := col.index[p.id]; ok {
+		col.points[i] = p
+	} else {
+		col.index[p.id] = len(col.points)
+		col.points = append(col.points, p)
+	}
+	col.changed()
+}
+
+// changed drops the HNSW graphs, the next search rebuilds them
+func (col *memoryCollection) changed() {
+	col.graphs = nil
+	col.version++
+}
+
+// ============================================================================
+// Writes
+// ============================================================================
+
+func (c *MemoryVectorCustom) insert(collection string, bbs []Bb) (int, error) {
+	payload := map[string]interface{}{}
+	for _, bb := range bbs {
+		payload[bb.Key] = bb.Value
+	}
+	id, err := ParsePointID(payload["id"])
+	if err != nil {
+		return 0, fmt.Errorf("point must have a valid 'id' field: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.collection(collection).put(newMemoryPoint(id, payload))
+	return 1, nil
+}
+
+// update supports Set() and the payload/vector modes of UpdateBuilder
+func (c *MemoryVectorCustom) update(collection string, built *Built) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	points, err := c.matching(collection, built)
+	if err != nil {
+		return 0, err
+	}
+
+	set := map[string]interface{}{}
+	vectors := map[string]interface{}{}
+	var key string
+	var overwrite, clear bool
+	var deletePayload, deleteVectors []string
+	for _, bb := range *built.Updates {
+		switch bb.Op {
+		case "":
+			set[bb.Key] = bb.Value
+		case QDRANT_UPDATE_VECTOR:
+			vectors[bb.Key] = bb.Value
+		case QDRANT_PAYLOAD_KEY:
+			key = bb.Value.(string)
+		case QDRANT_PAYLOAD_OVERWRITE:
+			overwrite = true
+		case QDRANT_PAYLOAD_CLEAR:
+			clear = true
+		case QDRANT_PAYLOAD_DELETE:
+			deletePayload = append(deletePayload, bb.Value.([]string)...)
+		case QDRANT_DELETE_VECTORS:
+			deleteVectors = append(deleteVectors, bb.Value.([]string)...)
+		default:
+			return 0, fmt.Errorf("update %q (%s) is not supported by the memory store", bb.Key, bb.Op)
+		}
+	}
+	if id, ok := set["id"]; ok && !overwrite {
+		return 0, fmt.Errorf("update can not change the point id to %v", id)
+	}
+
+	// A new map per point, results handed out earlier keep the old one
+	for _, p := range points {
+		var payload map[string]interface{}
+		switch {
+		case clear, overwrite:
+			// Like Qdrant, clearing or overwriting the payload keeps the vectors
+			payload = map[string]interface{}{"id": p.payload["id"]}
+			for k, v := range p.payload {
+				if p.isVector(k) {
+					payload[k] = v
+				}
+			}
+			if overwrite {
+				for k, v := range set {
+					payload[k] = v
+				}
+			}
+		case key != "":
+			payload = maps.Clone(p.payload)
+			nested, _ := payload[key].(map[string]interface{})
+			nested = maps.Clone(nested)
+			if nested == nil {
+				nested = map[string]interface{}{}
+			}
+			for k, v := range set {
+				nested[k] = v
+			}
+			payload[key] = nested
+		default:
+			payload = maps.Clone(p.payload)
+			for k, v := range set {
+				payload[k] = v
+			}
+		}
+		for k, v := range vectors {
+			payload[k] = v
+		}
+		for _, k := range deletePayload {
+			if k != "id" && !p.isVector(k) {
+				delete(payload, k)
+			}
+		}
+		for _, k := range deleteVectors {
+			if p.isVector(k) {
+				delete(payload, k)
+			}
+		}
+		p.payload = payload
+		p.reindex()
+	}
+	if len(points) > 0 {
+		c.collections[collection].changed()
+	}
+	return len(points), nil
+}
+
+func (c *MemoryVectorCustom) delete(collection string, built *Built) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	points, err := c.matching(collection, built)
+	if err != nil || len(points) == 0 {
+		return 0, err
+	}
+	col := c.collections[collection]
+	removed := map[PointID]bool{}
+	for _, p := range points {
+		removed[p.id] = true
+	}
+	kept := col.points[:0]
+	col.index = map[PointID]int{}
+	for _, p := range col.points {
+		if !removed[p.id] {
+			col.index[p.id] = len(kept)
+			kept = append(kept, p)
+		}
+	}
+	col.points = kept
+	col.changed()
+	return len(points), nil
+}
+
+// matching points of the Built's filter, an update or delete without conditions is refused
+func (c *MemoryVectorCustom) matching(collection string, built *Built) ([]*memoryPoint, error) {
+	filter, err := buildQdrantFilter(built.Conds)
+	if err != nil {
+		return nil, err
+	}
+	if nonEmptyQdrantFilter(filter) == nil {
+		return nil, fmt.Errorf("update or delete requires conditions, use Eq(\"id\", ...) or a filter")
+	}
+	col, ok := c.collections[collection]
+	if !ok {
+		return nil, nil
+	}
+	var points []*memoryPoint
+	for _, p := range col.points {
+		if filter.Matches(p.id, p.payload) {
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
+
+// ============================================================================
+// Select
+// ============================================================================
+
+func (c *MemoryVectorCustom) selectPoints(collection string, built *Built) ([]ScoredPoint[map[string]interface{}], error) {
+	filter, err := buildQdrantFilter(built.Conds)
+	if err != nil {
+		return nil, err
+	}
+	var distanceFilters []VectorDistanceFilterParams
+	var distanceFields []string
+	for _, bb := range built.Conds {
+		if bb.Op == VECTOR_DISTANCE_FILTER {
+			distanceFilters = append(distanceFilters, bb.Value.(VectorDistanceFilterParams))
+			distanceFields = append(distanceFields, bb.Key)
+		}
+	}
+	matches := func(p *memoryPoint) (bool, error) {
+		for i, f := range distanceFilters {
+			vec, ok := p.vectors[distanceFields[i]]
+			if !ok {
+				return false, nil
+			}
+			if len(vec) != len(f.QueryVector) {
+				return false, fmt.Errorf("point %s: %s has dimension %d, query has %d", p.id, distanceFields[i], len(vec), len(f.QueryVector))
+			}
+			if !compareDistance(vec.Distance(f.QueryVector, f.DistanceMetric), f.Operator, f.Threshold) {
+				return false, nil
+			}
+		}
+		return filter.Matches(p.id, p.payload), nil
+	}
+
+	limit, offset := memoryPage(built)
+	vectorBb := findVectorSearchBb(built.Conds)
+
+	// The HNSW graph is built under the write lock, searches share the read lock
+	if vectorBb != nil && c.HnswM > 0 && vectorBb.Value.(VectorSearchParams).QueryVector != nil {
+		if err := c.ensureGraph(collection, vectorBb); err != nil {
+			return nil, err
+		}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	col, ok := c.collections[collection]
+	if !ok {
+		return []ScoredPoint[map[string]interface{}]{}, nil
+	}
+
+	if vectorBb == nil {
+		var results []ScoredPoint[map[string]interface{}]
+		for _, p := range col.points {
+			ok, err := matches(p)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				results = append(results, ScoredPoint[map[string]interface{}]{ID: p.id, Payload: maps.Clone(p.payload)})
+			}
+		}
+		sortMemoryResults(results, built.Sorts)
+		return pageMemoryResults(results, offset, limit), nil
+	}
+
+	params := vectorBb.Value.(VectorSearchParams)
+	if limit <= 0 {
+		limit = params.TopK
+	}
+	if params.Rerank != nil {
+		limit = max(limit, params.Rerank.Candidates)
+	}
+	fetch := limit
+	if params.Diversity != nil && params.Diversity.Enabled {
+		factor := params.Diversity.OverFetchFactor
+		if factor <= 0 {
+			factor = 5
+		}
+		fetch = limit * factor
+	}
+
+	var hits []memoryHit
+	switch {
+	case params.QueryMultiVector != nil:
+		hits, err = bruteForceMulti(col.points, vectorBb.Key, params, matches)
+	case params.QuerySparseVector != nil:
+		hits, err = bruteForceSparse(col.points, vectorBb.Key, params, matches)
+	default:
+		if graph := col.graphs[memoryGraphKey{vectorBb.Key, params.DistanceMetric}]; graph != nil {
+			hits, err = graph.filteredSearch(params.QueryVector, offset+fetch, c.HnswEf, matches)
+		}
+		if err == nil && len(hits) < offset+fetch {
+			// Brute force: no graph, or the filter removed too many graph candidates
+			hits, err = bruteForce(col.points, vectorBb.Key, params, matches)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := memoryScoreThreshold(built.Conds)
+	results := make([]ScoredPoint[map[string]interface{}], 0, len(hits))
+	for _, h := range hits {
+		score := memoryScore(params, h.distance)
+		if threshold != nil && !passesScoreThreshold(params, score, *threshold) {
+			continue
+		}
+		results = append(results, ScoredPoint[map[string]interface{}]{
+			ID:      h.point.id,
+			Score:   score,
+			Payload: maps.Clone(h.point.payload),
+			Vector:  h.point.vectors[vectorBb.Key],
+		})
+	}
+	results = pageMemoryResults(results, offset, fetch)
+	if fetch > limit {
+		results = Diversify(results, params.Diversity, limit)
+	}
+	return results, nil
+}
+
+// memoryScore the score Qdrant returns for a distance
+// Euclid is scored by the distance in Qdrant, Hamming has no Qdrant metric and keeps it too
+func memoryScore(params VectorSearchParams, distance float32) float32 {
+	switch {
+	case params.QueryMultiVector != nil, params.QuerySparseVector != nil:
+		return -distance // MaxSim, Dot
+	case params.DistanceMetric == L2Distance, params.DistanceMetric == HammingDistance:
+		return distance
+	case params.DistanceMetric == InnerProduct:
+		return -distance
+	}
+	return 1 - distance
+}
+
+// passesScoreThreshold Qdrant score_threshold: a minimum similarity, a maximum distance
+func passesScoreThreshold(params VectorSearchParams, score, threshold float32) bool {
+	if params.QueryMultiVector == nil && params.QuerySparseVector == nil &&
+		(params.DistanceMetric == L2Distance || params.DistanceMetric == HammingDistance) {
+		return score <= threshold
+	}
+	return score >= threshold
+}
+
+// memoryScoreThreshold the QdrantX ScoreThreshold(), nil if not set
+func memoryScoreThreshold(bbs []Bb) *float32 {
+	for _, bb := range bbs {
+		if bb.Op == QDRANT_SCORE_THRESHOLD {
+			threshold := bb.Value.(float32)
+			return &threshold
+		}
+	}
+	return nil
+}
+
+// memoryHit a point and its distance to the query
+type memoryHit struct {
+	point    *memoryPoint
+	distance float32
+}
+
+func bruteForce(points []*memoryPoint, field string, params VectorSearchParams, matches func(p *memoryPoint) (bool, error)) ([]memoryHit, error) {
+	var hits []memoryHit
+	for _, p := range points {
+		vec, ok := p.vectors[field]
+		if !ok {
+			continue
+		}
+		if len(vec) != len(params.QueryVector) {
+			return nil, fmt.Errorf("point %s: %s has dimension %d, query has %d", p.id, field, len(vec), len(params.QueryVector))
+		}
+		ok, err := matches(p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			hits = append(hits, memoryHit{point: p, distance: vec.Distance(params.QueryVector, params.DistanceMetric)})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].distance < hits[j].distance
+	})
+	return hits, nil
+}
+
+// bruteForceMulti scores multivectors by MaxSim, the distance is -MaxSim
+func bruteForceMulti(points []*memoryPoint, field string, params VectorSearchParams, matches func(p *memoryPoint) (bool, error)) ([]memoryHit, error) {
+	query := params.QueryMultiVector
+	var hits []memoryHit
+	for _, p := range points {
+		multi, ok := p.multi[field]
+		if !ok {
+			continue
+		}
+		if multi.Dim() != query.Dim() {
+			return nil, fmt.Errorf("point %s: %s has dimension %d, query has %d", p.id, field, multi.Dim(), query.Dim())
+		}
+		ok, err := matches(p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			hits = append(hits, memoryHit{point: p, distance: -query.MaxSim(multi, params.DistanceMetric)})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].distance < hits[j].distance
+	})
+	return hits, nil
+}
+
+// bruteForceSparse scores sparse vectors by dot product, the distance is -Dot
+func bruteForceSparse(points []*memoryPoint, field string, params VectorSearchParams, matches func(p *memoryPoint) (bool, error)) ([]memoryHit, error) {
+	var hits []memoryHit
+	for _, p := range points {
+		sv, ok := p.sparse[field]
+		if !ok {
+			continue
+		}
+		ok, err := matches(p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			hits = append(hits, memoryHit{point: p, distance: params.QuerySparseVector.Distance(sv)})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].distance < hits[j].distance
+	})
+	return hits, nil
+}
+
+// ensureGraph builds the HNSW graph of the field if a write dropped it
+func (c *MemoryVectorCustom) ensureGraph(collection string, vectorBb *Bb) error {
+	params := vectorBb.Value.(VectorSearchParams)
+	key := memoryGraphKey{vectorBb.Key, params.DistanceMetric}
+
+	c.mu.RLock()
+	col, ok := c.collections[collection]
+	built := ok && col.graphs[key] != nil
+	c.mu.RUnlock()
+	if !ok || built {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if col.graphs[key] != nil {
+		return nil
+	}
+	graph := newHNSWGraph(c.HnswM, params.DistanceMetric)
+	for _, p := range col.points {
+		vec, ok := p.vectors[key.field]
+		if !ok {
+			continue
+		}
+		if len(vec) != len(params.QueryVector) {
+			return fmt.Errorf("point %s: %s has dimension %d, query has %d", p.id, key.field, len(vec), len(params.QueryVector))
+		}
+		graph.add(p, vec)
+	}
+	if col.graphs == nil {
+		col.graphs = map[memoryGraphKey]*hnswGraph{}
+	}
+	col.graphs[key] = graph
+	return nil
+}
+
+// memoryPage limit and offset of Paged() or Limit()/Offset(), 0 limit means TopK / all
+func memoryPage(built *Built) (limit, offset int) {
+	if built.PageCondition != nil && built.PageCondition.Rows > 0 {
+		limit = int(built.PageCondition.Rows)
+		if built.PageCondition.Page > 1 {
+			offset = int((built.PageCondition.Page - 1) * built.PageCondition.Rows)
+		}
+		return limit, offset
+	}
+	return built.LimitValue, built.OffsetValue
+}
+
+func pageMemoryResults(results []ScoredPoint[map[string]interface{}], offset, limit int) []ScoredPoint[map[string]interface{}] {
+	if offset >= len(results) {
+		return []ScoredPoint[map[string]interface{}]{}
+	}
+	results = results[offset:]
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results
+}
+
+// compareDistance distance <op> threshold, op of VectorDistanceFilter
+func compareDistance(distance float32, op string, threshold float32) bool {
+	switch op {
+	case "<=":
+		return distance <= threshold
+	case ">":
+		return distance > threshold
+	case ">=":
+		return distance >= threshold
+	case "=":
+		return distance == threshold
+	}
+	return distance < threshold
+}
+
+// sortMemoryResults orders by Sort()s; missing values sort last
+func sortMemoryResults(results []ScoredPoint[map[string]interface{}], sorts []Sort) {
+	if len(sorts) == 0 {
+		return
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		for _, s := range sorts {
+			a, okA := results[i].Payload[s.orderBy]
+			b, okB := results[j].Payload[s.orderBy]
+			if okA != okB {
+				return okA
+			}
+			cmp := comparePayload(a, b)
+			if cmp == 0 {
+				continue
+			}
+			if strings.EqualFold(s.direction, desc) {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+// comparePayload compares numbers by value, times by instant, anything else as text
+func comparePayload(a, b interface{}) int {
+	if x, ok := payloadNumber(a); ok {
+		if y, ok := payloadNumber(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if x, ok := payloadTime(a); ok {
+		if y, ok := payloadTime(b); ok {
+			return x.Compare(y)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,328 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"fmt"
+	"testing"
+)
+
+// memoryFixture five code vectors in "code_vectors"
+func memoryFixture(t *testing.T, custom *MemoryVectorCustom) {
+	t.Helper()
+	rows := []struct {
+		id       int
+		vec      Vector
+		language string
+		layer    string
+		hash     string
+	}{
+		{1, Vector{1, 0, 0}, "golang", "service", "a"},
+		{2, Vector{0.9, 0.1, 0}, "golang", "repository", "a"},
+		{3, Vector{0.8, 0.2, 0.1}, "python", "service", "b"},
+		{4, Vector{0, 1, 0}, "python", "repository", "c"},
+		{5, Vector{0, 0, 1}, "rust", "service", "d"},
+	}
+	for _, r := range rows {
+		n, err := Of(&CodeVectorForQdrant{}).Custom(custom).
+			Insert(func(ib *InsertBuilder) {
+				ib.Set("id", r.id).Set("embedding", r.vec).
+					Set("language", r.language).Set("layer", r.layer).Set("semantic_hash", r.hash)
+			}).
+			Build().
+			MemoryOfExec()
+		if err != nil || n != 1 {
+			t.Fatalf("insert %d: n=%d err=%v", r.id, n, err)
+		}
+	}
+}
+
+func memoryIDs[T any](points []ScoredPoint[T]) string {
+	ids := ""
+	for _, p := range points {
+		ids += fmt.Sprint(p.ID) + " "
+	}
+	return ids
+}
+
+func TestMemoryVector_SearchWithFilter(t *testing.T) {
+	mem := NewMemoryVectorBuilder().Build()
+	memoryFixture(t, mem)
+
+	results, err := MemorySearch[CodeVectorForQdrant](Of(&CodeVectorForQdrant{}).Custom(mem).
+		Eq("layer", "service").
+		VectorSearch("embedding", Vector{1, 0, 0}, 2).
+		Build())
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := memoryIDs(results); got != "1 3 " {
+		t.Fatalf("ids = %s", got)
+	}
+	// Cosine similarity, as Qdrant: higher is more similar
+	if results[0].Payload.Language != "golang" || results[0].Score != 1 || results[1].Score >= 1 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	// Or groups, In and Ne
+	results2, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		In("language", "python", "rust").
+		Or(func(cb *CondBuilder) { cb.Eq("layer", "service").OR().Ne("id", 4) }).
+		VectorSearch("embedding", Vector{0, 1, 0}, 10).
+		VectorDistance(L2Distance).
+		Build().
+		MemoryOfSelect()
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := memoryIDs(results2); got != "3 5 " {
+		t.Errorf("ids = %s", got)
+	}
+}
+
+func TestMemoryVector_DistanceFilterAndPaging(t *testing.T) {
+	mem := NewMemoryVectorBuilder().Build()
+	memoryFixture(t, mem)
+
+	results, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		VectorSearch("embedding", Vector{1, 0, 0}, 10).
+		VectorDistanceFilter("embedding", Vector{1, 0, 0}, "<", 0.1).
+		Build().
+		MemoryOfSelect()
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := memoryIDs(results); got != "1 2 3 " {
+		t.Errorf("ids = %s", got)
+	}
+
+	paged, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		VectorSearch("embedding", Vector{1, 0, 0}, 10).
+		Paged(func(pb *PageBuilder) { pb.Page(2).Rows(2) }).
+		Build().
+		MemoryOfSelect()
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := memoryIDs(paged); got != "3 4 " {
+		t.Errorf("page 2 ids = %s", got)
+	}
+
+	// Without VectorSearch: filter, sort and limit
+	sorted, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		Gte("id", 2).
+		Sort("language", DESC).
+		Sort("id", ASC).
+		Limit(3).
+		Build().
+		MemoryOfSelect()
+	if err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	if got := memoryIDs(sorted); got != "5 3 4 " {
+		t.Errorf("sorted ids = %s", got)
+	}
+}
+
+func TestMemoryVector_ScoreThreshold(t *testing.T) {
+	mem := NewMemoryVectorBuilder().Build()
+	memoryFixture(t, mem)
+
+	search := func(metric VectorDistance, threshold float32) ([]ScoredPoint[CodeVectorForQdrant], error) {
+		return MemorySearch[CodeVectorForQdrant](Of(&CodeVectorForQdrant{}).Custom(mem).
+			VectorSearch("embedding", Vector{1, 0, 0}, 10).
+			VectorDistance(metric).
+			QdrantX(func(qx *QdrantXBuilder) { qx.ScoreThreshold(threshold) }).
+			Build())
+	}
+	cases := []struct {
+		metric    VectorDistance
+		threshold float32
+		want      string
+		best      float32
+	}{
+		{CosineDistance, 0.9, "1 2 3 ", 1}, // similarity >= 0.9, the orthogonal 4 and 5 are dropped
+		{InnerProduct, 0.85, "1 2 ", 1},    // dot product >= 0.85
+		{L2Distance, 0.5, "1 2 3 ", 0},     // Euclid: distance <= 0.5
+	}
+	for _, c := range cases {
+		results, err := search(c.metric, c.threshold)
+		if err != nil {
+			t.Fatalf("%s search failed: %v", c.metric, err)
+		}
+		if got := memoryIDs(results); got != c.want || results[0].Score != c.best {
+			t.Errorf("%s: ids = %s, scores %+v", c.metric, got, results)
+		}
+	}
+}
+
+func TestMemoryVector_Diversity(t *testing.T) {
+	mem := NewMemoryVectorBuilder().Build()
+	memoryFixture(t, mem)
+
+	results, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		VectorSearch("embedding", Vector{1, 0, 0}, 3).
+		WithHashDiversity("semantic_hash").
+		Build().
+		MemoryOfSelect()
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	// 2 has the same hash as 1
+	if got := memoryIDs(results); got != "1 3 4 " {
+		t.Errorf("ids = %s", got)
+	}
+}
+
+func TestMemoryVector_PayloadModesKeepVectors(t *testing.T) {
+	cases := map[string]struct {
+		update     func(ub *UpdateBuilder)
+		hits       int             // VectorSearch hits of point 1
+		wantFields map[string]bool // payload field => present
+	}{
+		"clear": {
+			update:     func(ub *UpdateBuilder) { ub.ClearPayload() },
+			hits:       1,
+			wantFields: map[string]bool{"embedding": true, "language": false},
+		},
+		"overwrite": {
+			update:     func(ub *UpdateBuilder) { ub.OverwritePayload().Set("lang", "rust") },
+			hits:       1,
+			wantFields: map[string]bool{"embedding": true, "lang": true, "language": false},
+		},
+		"delete payload keys": {
+			update:     func(ub *UpdateBuilder) { ub.DeletePayloadKeys("embedding", "language") },
+			hits:       1,
+			wantFields: map[string]bool{"embedding": true, "language": false, "layer": true},
+		},
+		"delete vectors": {
+			update:     func(ub *UpdateBuilder) { ub.DeleteVectors("embedding", "language") },
+			hits:       0,
+			wantFields: map[string]bool{"embedding": false, "language": true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mem := NewMemoryVectorBuilder().Build()
+			memoryFixture(t, mem)
+
+			n, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+				Eq("id", 1).
+				Update(tc.update).
+				Build().
+				MemoryOfExec()
+			if err != nil || n != 1 {
+				t.Fatalf("update: n=%d err=%v", n, err)
+			}
+
+			results, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+				Eq("id", 1).
+				VectorSearch("embedding", Vector{1, 0, 0}, 10).
+				Build().
+				MemoryOfSelect()
+			if err != nil || len(results) != tc.hits {
+				t.Fatalf("search: hits=%d err=%v", len(results), err)
+			}
+
+			points, _ := Of(&CodeVectorForQdrant{}).Custom(mem).Eq("id", 1).Build().MemoryOfSelect()
+			if len(points) != 1 {
+				t.Fatalf("point 1 not found")
+			}
+			for field, want := range tc.wantFields {
+				if _, ok := points[0].Payload[field]; ok != want {
+					t.Errorf("field %s present = %v, want %v", field, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryVector_ResultsAreCopies(t *testing.T) {
+	mem := NewMemoryVectorBuilder().Build()
+	memoryFixture(t, mem)
+
+	results, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		VectorSearch("embedding", Vector{1, 0, 0}, 1).
+		Build().
+		MemoryOfSelect()
+	if err != nil || len(results) != 1 {
+		t.Fatalf("search: %v, %v", results, err)
+	}
+	results[0].Payload["language"] = "MUTATED"
+
+	mutated, _ := Of(&CodeVectorForQdrant{}).Custom(mem).Eq("language", "MUTATED").Build().MemoryOfSelect()
+	if len(mutated) != 0 {
+		t.Errorf("results share the stored payload: %v", mutated)
+	}
+
+	// An update does not change a payload handed out earlier
+	before, _ := Of(&CodeVectorForQdrant{}).Custom(mem).Eq("id", 2).Build().MemoryOfSelect()
+	Of(&CodeVectorForQdrant{}).Custom(mem).
+		Eq("id", 2).
+		Update(func(ub *UpdateBuilder) { ub.Set("layer", "api") }).
+		Build().
+		MemoryOfExec()
+	if before[0].Payload["layer"] != "repository" {
+		t.Errorf("layer = %v", before[0].Payload["layer"])
+	}
+}
+
+func TestMemoryVector_UpdateDelete(t *testing.T) {
+	mem := NewMemoryVectorBuilder().Build()
+	memoryFixture(t, mem)
+
+	n, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		Eq("language", "python").
+		Update(func(ub *UpdateBuilder) { ub.Set("layer", "api").Vector("embedding", Vector{0, 0, 1}) }).
+		Build().
+		MemoryOfExec()
+	if err != nil || n != 2 {
+		t.Fatalf("update: n=%d err=%v", n, err)
+	}
+	results, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		Eq("layer", "api").
+		VectorSearch("embedding", Vector{0, 0, 1}, 10).
+		Build().
+		MemoryOfSelect()
+	if err != nil || len(results) != 2 || results[0].Score != 1 || results[1].Score != 1 {
+		t.Fatalf("updated points not found: %+v, %v", results, err)
+	}
+
+	n, err = Of(&CodeVectorForQdrant{}).Custom(mem).In("id", 1, 5).Build().MemoryOfDelete()
+	if err != nil || n != 2 || mem.Len("code_vectors") != 3 {
+		t.Fatalf("delete: n=%d len=%d err=%v", n, mem.Len("code_vectors"), err)
+	}
+
+	if _, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		Update(func(ub *UpdateBuilder) { ub.Set("layer", "x") }).
+		Build().
+		MemoryOfExec(); err == nil {
+		t.Error("expected error for update without conditions")
+	}
+	if _, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+		VectorSearch("embedding", Vector{1, 0}, 10).
+		Build().
+		MemoryOfSelect(); err == nil {
+		t.Error("expected dimension error")
+	}
+	if _, err := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		VectorSearch("embedding", Vector{1, 0, 0}, 10).
+		Build().
+		MemoryOfSelect(); err == nil {
+		t.Error("expected error for Qdrant Custom")
+	}
+}
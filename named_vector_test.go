@@ -0,0 +1,246 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNamedVectors_SearchAndGroups(t *testing.T) {
+	named := NewQdrantBuilder().NamedVectors().Build()
+
+	js, err := Of(&CodeVectorForQdrant{}).Custom(named).
+		Eq("language", "golang").
+		VectorSearch("embedding", Vector{1, 0}, 5).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{
+		"vector": {"name": "embedding", "vector": [1, 0]},
+		"limit": 5,
+		"filter": {"must": [{"key": "language", "match": {"value": "golang"}}]},
+		"with_payload": true,
+		"params": {"hnsw_ef": 128}
+	}`)
+
+	// Without NamedVectors() the vector stays unnamed
+	js, err = Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		VectorSearch("embedding", Vector{1, 0}, 5).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	if !strings.Contains(js, `"vector": [`) {
+		t.Errorf("want an unnamed vector, got %s", js)
+	}
+
+	js, err = Of(&CodeVectorForQdrant{}).Custom(named).
+		VectorSearch("embedding", Vector{1, 0}, 5).
+		GroupBy("language").
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("groups JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{
+		"vector": {"name": "embedding", "vector": [1, 0]},
+		"group_by": "language",
+		"limit": 5,
+		"with_payload": true,
+		"params": {"hnsw_ef": 128}
+	}`)
+}
+
+func TestNamedVectors_Upsert(t *testing.T) {
+	js, err := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().NamedVectors().Build()).
+		Insert(func(ib *InsertBuilder) {
+			ib.Set("id", 7).
+				Set("embedding", Vector{1, 0}).
+				Set("colbert", MultiVector{{1, 0}, {0, 1}}).
+				Set("language", "golang")
+		}).
+		Build().
+		JsonOfInsert()
+	if err != nil {
+		t.Fatalf("JsonOfInsert failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{"points": [{
+		"id": 7,
+		"vector": {"embedding": [1, 0], "colbert": [[1, 0], [0, 1]]},
+		"payload": {"language": "golang"}
+	}]}`)
+}
+
+func TestMultiVectorSearch_Query(t *testing.T) {
+	query := MultiVector{{1, 0}, {0, 1}}
+	js, err := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().NamedVectors().Build()).
+		Eq("language", "golang").
+		MultiVectorSearch("colbert", query, 3).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{
+		"query": [[1, 0], [0, 1]],
+		"using": "colbert",
+		"filter": {"must": [{"key": "language", "match": {"value": "golang"}}]},
+		"params": {"hnsw_ef": 128},
+		"limit": 3,
+		"with_payload": true
+	}`)
+
+	js, err = Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().
+		Query(func(qb *QueryBuilder) {
+			qb.NearestMulti(query).Using("colbert").Limit(4)
+		}).
+		Build()).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("NearestMulti JsonOfSelect failed: %v", err)
+	}
+	if !strings.Contains(js, `"query": [`) || !strings.Contains(js, `"using": "colbert"`) {
+		t.Errorf("unexpected NearestMulti query: %s", js)
+	}
+
+	cases := map[string]func(){
+		"ragged rows": func() {
+			Of(&CodeVectorForQdrant{}).MultiVectorSearch("colbert", MultiVector{{1, 0}, {1}}, 3)
+		},
+		"empty NearestMulti": func() {
+			NewQdrantBuilder().Query(func(qb *QueryBuilder) { qb.NearestMulti(nil) })
+		},
+		"SqlOfVectorSearch": func() {
+			Of(&CodeVectorForQdrant{}).MultiVectorSearch("colbert", query, 3).Build().SqlOfVectorSearch()
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func TestMultiVectorSearch_Redis(t *testing.T) {
+	redis := Of(&CodeVectorForQdrant{}).Custom(NewRedisSearchBuilder().Build()).
+		MultiVectorSearch("colbert", MultiVector{{1, 0}}, 3).
+		Build()
+	if _, err := redis.JsonOfSelect(); err == nil {
+		t.Error("Redis multivector search should fail")
+	}
+}
+
+func TestQdrantCollection_MultiVector(t *testing.T) {
+	js, err := QdrantCollection("docs").
+		NamedVector("dense", 4, CosineDistance).
+		MultiVector("colbert", 2, CosineDistance).
+		CreateJSON()
+	if err != nil {
+		t.Fatalf("CreateJSON failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{"vectors": {
+		"dense": {"size": 4, "distance": "Cosine"},
+		"colbert": {"size": 2, "distance": "Cosine", "multivector_config": {"comparator": "max_sim"}}
+	}}`)
+}
+
+func TestDecodeQdrant_NamedVectors(t *testing.T) {
+	body := `{"result": [{"id": 1, "score": 0.9, "payload": {},
+		"vector": {"dense": [1, 0], "colbert": [[1, 0], [0, 1]]}}], "status": "ok"}`
+	points, err := DecodeQdrantSearch[map[string]interface{}]([]byte(body))
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(points) != 1 || len(points[0].Vectors) != 1 || points[0].Vectors["dense"][0] != 1 || points[0].Vector != nil {
+		t.Errorf("unexpected points: %+v", points)
+	}
+}
+
+func TestMultiVector_MaxSim(t *testing.T) {
+	query := MultiVector{{1, 0}, {0, 1}}
+	doc := MultiVector{{1, 0}, {0.6, 0.8}}
+	// Row 1 best match 1, row 2 best match 0.8
+	if got := query.MaxSim(doc, CosineDistance); math.Abs(float64(got)-1.8) > 1e-5 {
+		t.Errorf("cosine MaxSim = %v, want 1.8", got)
+	}
+	if got := query.MaxSim(doc, InnerProduct); math.Abs(float64(got)-1.8) > 1e-5 {
+		t.Errorf("dot MaxSim = %v, want 1.8", got)
+	}
+	// L2: row 1 exact (0), row 2 closest to {0.6, 0.8}: -sqrt(0.36+0.04)
+	if got := query.MaxSim(doc, L2Distance); math.Abs(float64(got)+math.Sqrt(0.4)) > 1e-5 {
+		t.Errorf("L2 MaxSim = %v, want %v", got, -math.Sqrt(0.4))
+	}
+	if query.Dim() != 2 || (MultiVector{}).Dim() != 0 {
+		t.Errorf("Dim() = %d", query.Dim())
+	}
+}
+
+func TestMemoryVector_MultiVectorSearch(t *testing.T) {
+	mem := NewMemoryVectorBuilder().HNSW(8, 32).Build()
+	docs := []struct {
+		id       int
+		tokens   MultiVector
+		language string
+	}{
+		{1, MultiVector{{1, 0}, {0, 1}}, "golang"},
+		{2, MultiVector{{1, 0}, {1, 0}}, "golang"},
+		{3, MultiVector{{0, 1}}, "python"},
+	}
+	for _, d := range docs {
+		_, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+			Insert(func(ib *InsertBuilder) {
+				ib.Set("id", d.id).Set("colbert", d.tokens).Set("language", d.language)
+			}).
+			Build().
+			MemoryOfExec()
+		if err != nil {
+			t.Fatalf("insert %d failed: %v", d.id, err)
+		}
+	}
+
+	results, err := MemorySearch[map[string]interface{}](Of(&CodeVectorForQdrant{}).Custom(mem).
+		MultiVectorSearch("colbert", MultiVector{{1, 0}, {0, 1}}, 3).
+		Build())
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := memoryIDs(results); got != "1 2 3 " {
+		t.Fatalf("ids = %s", got)
+	}
+	if math.Abs(float64(results[0].Score)-2) > 1e-5 {
+		t.Errorf("score = %v, want 2 (MaxSim)", results[0].Score)
+	}
+
+	results, err = MemorySearch[map[string]interface{}](Of(&CodeVectorForQdrant{}).Custom(mem).
+		Eq("language", "python").
+		MultiVectorSearch("colbert", MultiVector{{1, 0}}, 3).
+		Build())
+	if err != nil || memoryIDs(results) != "3 " {
+		t.Errorf("filtered search: %s %v", memoryIDs(results), err)
+	}
+}
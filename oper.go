@@ -78,6 +78,7 @@ func NonNull() string {
 const (
 	VECTOR_SEARCH          = "VECTOR_SEARCH"
 	VECTOR_DISTANCE_FILTER = "VECTOR_DISTANCE_FILTER"
+	HYBRID_SEARCH          = "HYBRID_SEARCH" // Keyword + vector fusion
 )
 
 // Qdrant-specific operators (v0.9.1 added)
@@ -86,9 +87,49 @@ const (
 	QDRANT_EXACT           = "QDRANT_EXACT"
 	QDRANT_SCORE_THRESHOLD = "QDRANT_SCORE_THRESHOLD"
 	QDRANT_WITH_VECTOR     = "QDRANT_WITH_VECTOR"
+	QDRANT_INDEXED_ONLY    = "QDRANT_INDEXED_ONLY"
+	QDRANT_QUANTIZATION    = "QDRANT_QUANTIZATION"
+	QDRANT_WITH_PAYLOAD    = "QDRANT_WITH_PAYLOAD"
 	QDRANT_RECOMMEND       = "QDRANT_RECOMMEND"    // Recommend API (v0.10.0)
 	QDRANT_SCROLL          = "QDRANT_SCROLL"       // Scroll API (v0.10.0)
 	QDRANT_BATCH_SEARCH    = "QDRANT_BATCH_SEARCH" // Batch Search (v0.10.1)
 	QDRANT_DISCOVER        = "QDRANT_DISCOVER"     // Discover API (v0.10.1)
+	QDRANT_QUERY           = "QDRANT_QUERY"        // Universal Query API (/points/query)
+	QDRANT_GROUP_SIZE      = "QDRANT_GROUP_SIZE"   // Search groups (/points/search/groups)
+	QDRANT_WITH_LOOKUP     = "QDRANT_WITH_LOOKUP"  // Search groups lookup collection
+	QDRANT_FACET           = "QDRANT_FACET"        // Facet counts (/facet)
 	QDRANT_XX              = "QDRANT_XX"           // User-defined Qdrant-specific parameters
 )
+
+// Qdrant payload condition operators, translated into the Qdrant filter
+const (
+	QDRANT_MATCH_EXCEPT     = "QDRANT_MATCH_EXCEPT"
+	QDRANT_GEO_RADIUS       = "QDRANT_GEO_RADIUS"
+	QDRANT_GEO_BOUNDING_BOX = "QDRANT_GEO_BOUNDING_BOX"
+	QDRANT_GEO_POLYGON      = "QDRANT_GEO_POLYGON"
+	QDRANT_DATETIME_RANGE   = "QDRANT_DATETIME_RANGE"
+	QDRANT_VALUES_COUNT     = "QDRANT_VALUES_COUNT"
+	QDRANT_IS_EMPTY         = "QDRANT_IS_EMPTY"
+	QDRANT_IS_NULL          = "QDRANT_IS_NULL"
+	QDRANT_HAS_ID           = "QDRANT_HAS_ID"
+	QDRANT_NESTED           = "QDRANT_NESTED"
+)
+
+// Qdrant update modes, set by UpdateBuilder, ignored by SQL
+const (
+	QDRANT_PAYLOAD_KEY       = "QDRANT_PAYLOAD_KEY"       // POST /points/payload with key path
+	QDRANT_PAYLOAD_OVERWRITE = "QDRANT_PAYLOAD_OVERWRITE" // PUT /points/payload
+	QDRANT_PAYLOAD_DELETE    = "QDRANT_PAYLOAD_DELETE"    // POST /points/payload/delete
+	QDRANT_PAYLOAD_CLEAR     = "QDRANT_PAYLOAD_CLEAR"     // POST /points/payload/clear
+	QDRANT_UPDATE_VECTOR     = "QDRANT_UPDATE_VECTOR"     // PUT /points/vectors
+	QDRANT_DELETE_VECTORS    = "QDRANT_DELETE_VECTORS"    // POST /points/vectors/delete
+)
+
+func isQdrantUpdateOp(op string) bool {
+	switch op {
+	case QDRANT_PAYLOAD_KEY, QDRANT_PAYLOAD_OVERWRITE, QDRANT_PAYLOAD_DELETE,
+		QDRANT_PAYLOAD_CLEAR, QDRANT_UPDATE_VECTOR, QDRANT_DELETE_VECTORS:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PointID Qdrant point id: unsigned integer or UUID
+// JSON: integers as numbers, UUIDs as strings, as Qdrant expects
+//
+// Example:
+//
+//	xb.NumPointID(42)
+//	xb.UUIDPointID("5c56c793-69f3-4fbf-87e6-c4bf54c28c26")
+//	id, err := xb.ParsePointID(v) // int, uint64, UUID string, ...
+type PointID struct {
+	num  uint64
+	uuid string
+}
+
+// NumPointID integer point id
+func NumPointID(n uint64) PointID {
+	return PointID{num: n}
+}
+
+// UUIDPointID UUID point id, panics on an invalid UUID
+// Accepts hyphenated and simple (32 hex) form, normalized to lower-case hyphenated
+func UUIDPointID(s string) PointID {
+	u, ok := normalizeUUID(s)
+	if !ok {
+		panic(fmt.Sprintf("UUIDPointID(%q) is not a valid UUID", s))
+	}
+	return PointID{uuid: u}
+}
+
+// ParsePointID converts v to PointID
+// Accepts PointID, non-negative integers, integral floats (decoded JSON numbers),
+// json.Number, UUID strings and decimal strings
+func ParsePointID(v interface{}) (PointID, error) {
+	switch id := v.(type) {
+	case PointID:
+		return id, nil
+	case *PointID:
+		if id != nil {
+			return *id, nil
+		}
+	case uint64:
+		return NumPointID(id), nil
+	case uint:
+		return NumPointID(uint64(id)), nil
+	case uint32:
+		return NumPointID(uint64(id)), nil
+	case uint16:
+		return NumPointID(uint64(id)), nil
+	case uint8:
+		return NumPointID(uint64(id)), nil
+	case int, int64, int32, int16, int8:
+		n := toInt64(id)
+		if n < 0 {
+			return PointID{}, fmt.Errorf("point id must be >= 0, got: %d", n)
+		}
+		return NumPointID(uint64(n)), nil
+	case float64:
+		if id < 0 || id != math.Trunc(id) || id >= math.MaxUint64 {
+			return PointID{}, fmt.Errorf("point id must be an unsigned integer, got: %v", id)
+		}
+		return NumPointID(uint64(id)), nil
+	case float32:
+		return ParsePointID(float64(id))
+	case json.Number:
+		return ParsePointID(string(id))
+	case string:
+		if n, err := strconv.ParseUint(id, 10, 64); err == nil {
+			return NumPointID(n), nil
+		}
+		if u, ok := normalizeUUID(id); ok {
+			return PointID{uuid: u}, nil
+		}
+		return PointID{}, fmt.Errorf("point id must be an unsigned integer or UUID, got: %q", id)
+	}
+	return PointID{}, fmt.Errorf("point id must be an unsigned integer or UUID, got: %T", v)
+}
+
+// mustPointIDs converts builder input ids, panics on invalid ids
+func mustPointIDs(method string, ids []interface{}) []PointID {
+	result := make([]PointID, 0, len(ids))
+	for _, v := range ids {
+		id, err := ParsePointID(v)
+		if err != nil {
+			panic(fmt.Sprintf("%s: %v", method, err))
+		}
+		result = append(result, id)
+	}
+	return result
+}
+
+// IsUUID the id is a UUID
+func (id PointID) IsUUID() bool {
+	return id.uuid != ""
+}
+
+// Num integer id, 0 for UUIDs
+func (id PointID) Num() uint64 {
+	return id.num
+}
+
+// UUID UUID id, "" for integer ids
+func (id PointID) UUID() string {
+	return id.uuid
+}
+
+// String integer as decimal, UUID as is
+func (id PointID) String() string {
+	if id.uuid != "" {
+		return id.uuid
+	}
+	return strconv.FormatUint(id.num, 10)
+}
+
+// MarshalJSON integer => number, UUID => string
+func (id PointID) MarshalJSON() ([]byte, error) {
+	if id.uuid != "" {
+		return json.Marshal(id.uuid)
+	}
+	return []byte(strconv.FormatUint(id.num, 10)), nil
+}
+
+// UnmarshalJSON number or UUID string, large integers keep full precision
+func (id *PointID) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if strings.HasPrefix(s, `"`) {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		s = str
+	}
+	parsed, err := ParsePointID(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// normalizeUUID 8-4-4-4-12 or 32 hex digits => lower-case 8-4-4-4-12
+func normalizeUUID(s string) (string, bool) {
+	hex := strings.ToLower(s)
+	switch len(hex) {
+	case 36:
+		if hex[8] != '-' || hex[13] != '-' || hex[18] != '-' || hex[23] != '-' {
+			return "", false
+		}
+		hex = strings.ReplaceAll(hex, "-", "")
+		if len(hex) != 32 {
+			return "", false
+		}
+	case 32:
+	default:
+		return "", false
+	}
+	for _, c := range hex {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return "", false
+		}
+	}
+	return hex[0:8] + "-" + hex[8:12] + "-" + hex[12:16] + "-" + hex[16:20] + "-" + hex[20:], true
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	case int32:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case int8:
+		return int64(n)
+	}
+	return 0
+}
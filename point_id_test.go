@@ -0,0 +1,173 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testUUID = "5c56c793-69f3-4fbf-87e6-c4bf54c28c26"
+
+func TestParsePointID(t *testing.T) {
+	cases := []struct {
+		in   interface{}
+		want string
+		uuid bool
+	}{
+		{42, "42", false},
+		{int64(7), "7", false},
+		{uint64(18446744073709551615), "18446744073709551615", false},
+		{float64(12), "12", false},
+		{json.Number("99"), "99", false},
+		{"123", "123", false},
+		{testUUID, testUUID, true},
+		{"5C56C79369F34FBF87E6C4BF54C28C26", testUUID, true},
+		{NumPointID(5), "5", false},
+	}
+	for _, c := range cases {
+		id, err := ParsePointID(c.in)
+		if err != nil {
+			t.Fatalf("ParsePointID(%v) failed: %v", c.in, err)
+		}
+		if id.String() != c.want || id.IsUUID() != c.uuid {
+			t.Errorf("ParsePointID(%v) = %s (uuid=%v), want %s", c.in, id, id.IsUUID(), c.want)
+		}
+	}
+
+	for _, bad := range []interface{}{-1, 1.5, "not-a-uuid", "5c56c793-69f3-4fbf-87e6", nil, true} {
+		if _, err := ParsePointID(bad); err == nil {
+			t.Errorf("ParsePointID(%v) expected error", bad)
+		}
+	}
+}
+
+func TestPointID_JSON(t *testing.T) {
+	ids := []PointID{NumPointID(18446744073709551615), UUIDPointID(testUUID)}
+	bytes, err := json.Marshal(ids)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(bytes) != `[18446744073709551615,"`+testUUID+`"]` {
+		t.Errorf("unexpected JSON: %s", bytes)
+	}
+
+	var decoded []PointID
+	if err := json.Unmarshal(bytes, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded[0] != ids[0] || decoded[1] != ids[1] {
+		t.Errorf("round trip lost precision: %v", decoded)
+	}
+}
+
+func TestPointID_RecommendDiscoverCRUD(t *testing.T) {
+	recommend, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) {
+			rb.PositiveExample(testUUID, uint64(7)).NegativeExample("11").Limit(5)
+		}).Build()).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("recommend failed: %v", err)
+	}
+	assertJSONEqual(t, recommend, `{
+		"positive": ["`+testUUID+`", 7],
+		"negative": [11],
+		"limit": 5,
+		"with_payload": true
+	}`)
+
+	discover, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Discover(func(db *DiscoverBuilder) {
+			db.Target(testUUID).Pair(1, "2").Limit(3)
+		}).Build()).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("discover failed: %v", err)
+	}
+	assertJSONEqual(t, discover, `{
+		"target": "`+testUUID+`",
+		"context": [{"positive": 1, "negative": 2}],
+		"limit": 3,
+		"with_payload": true
+	}`)
+
+	// In() stores SQL literals, integer ids must come out as numbers again
+	del, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		In("id", 1, 2, testUUID).
+		Build().
+		JsonOfDelete()
+	if err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	assertJSONEqual(t, del, `{"points": [1, 2, "`+testUUID+`"]}`)
+
+	upd, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Eq("id", testUUID).
+		Update(func(ub *UpdateBuilder) { ub.Set("language", "go") }).
+		Build().
+		JsonOfUpdate()
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	assertJSONEqual(t, upd, `{"points": ["`+testUUID+`"], "payload": {"language": "go"}}`)
+
+	ins, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Insert(func(ib *InsertBuilder) {
+			ib.Set("id", uint64(9)).Set("vector", []float32{0.1}).Set("language", "go")
+		}).
+		Build().
+		JsonOfInsert()
+	if err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	assertJSONEqual(t, ins, `{"points": [{"id": 9, "vector": [0.1], "payload": {"language": "go"}}]}`)
+
+	// Invalid ids are errors, not silently sent
+	_, err = Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Eq("id", "abc").
+		Build().
+		JsonOfDelete()
+	if err == nil {
+		t.Error("expected error for invalid id")
+	}
+}
+
+func TestPointID_BuilderPanics(t *testing.T) {
+	cases := map[string]func(){
+		"positive": func() { NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) { rb.Positive(-1) }) },
+		"target":   func() { NewQdrantBuilder().Discover(func(db *DiscoverBuilder) { db.Target("x") }) },
+		"has_id":   func() { Of(&CodeVectorForQdrant{}).HasId(1.5) },
+		"uuid":     func() { UUIDPointID("123") },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
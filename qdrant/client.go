@@ -0,0 +1,363 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fndome/xb"
+)
+
+// Client sends the JSON generated by xb to a Qdrant server over REST
+// and decodes the responses. Optional: xb itself only produces JSON.
+//
+// Example:
+//
+//	client := qdrant.New("http://localhost:6333",
+//	    qdrant.WithAPIKey(os.Getenv("QDRANT_API_KEY")),
+//	    qdrant.WithTimeout(5*time.Second))
+//
+//	built := xb.Of(&CodeVector{}).
+//	    Custom(xb.NewQdrantBuilder().Build()).
+//	    Eq("language", "golang").
+//	    VectorSearch("embedding", vec, 10).
+//	    Build()
+//	points, err := qdrant.Search[CodeVector](ctx, client, "code_vectors", built)
+type Client struct {
+	baseURL string
+	apiKey  string
+	timeout time.Duration
+	wait    bool
+	http    *http.Client
+}
+
+// Option Client option
+type Option func(c *Client)
+
+// WithAPIKey sends the key in the api-key header
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithTimeout timeout of each request, 0 means no timeout (default 30s)
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithTransport sets the http.RoundTripper (tracing, retries, mTLS, fakes)
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.http = &http.Client{Transport: rt}
+	}
+}
+
+// WithWait write operations wait until the change is applied (?wait=true), default true
+func WithWait(wait bool) Option {
+	return func(c *Client) {
+		c.wait = wait
+	}
+}
+
+// New creates a Client for baseURL, e.g. "http://localhost:6333"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		timeout: 30 * time.Second,
+		wait:    true,
+		http:    &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error non-2xx response or a Qdrant error status
+type Error struct {
+	StatusCode int    // HTTP status code
+	Message    string // Qdrant status.error, or the body when it is not JSON
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("qdrant: %d %s", e.StatusCode, e.Message)
+}
+
+// Do sends body to the endpoint of collection and returns the response body
+// A non-2xx response or a Qdrant error status is returned as *Error
+func (c *Client) Do(ctx context.Context, collection string, ep xb.QdrantEndpoint, body string) ([]byte, error) {
+	url := c.baseURL + ep.URL(collection)
+	if c.wait && ep.Method != http.MethodGet && isWrite(ep) {
+		url += "?wait=true"
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, ep.Method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("api-key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := responseError(resp.StatusCode, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// isWrite endpoints that modify points or payload
+func isWrite(ep xb.QdrantEndpoint) bool {
+	switch {
+	case ep.Path == "/points" && ep.Method == http.MethodPut,
+		ep.Path == "/points/delete",
+		strings.HasPrefix(ep.Path, "/points/payload"),
+		strings.HasPrefix(ep.Path, "/points/vectors"),
+		ep.Path == "/index":
+		return true
+	}
+	return false
+}
+
+// responseError decodes {"status": {"error": "..."}} of a failed request
+func responseError(code int, data []byte) error {
+	var envelope struct {
+		Status interface{} `json:"status"`
+	}
+	jsonErr := json.Unmarshal(data, &envelope)
+	if code >= 200 && code < 300 {
+		if s, ok := envelope.Status.(map[string]interface{}); ok && jsonErr == nil {
+			return &Error{StatusCode: code, Message: fmt.Sprint(s["error"])}
+		}
+		return nil
+	}
+	if s, ok := envelope.Status.(map[string]interface{}); ok && jsonErr == nil {
+		return &Error{StatusCode: code, Message: fmt.Sprint(s["error"])}
+	}
+	return &Error{StatusCode: code, Message: string(bytes.TrimSpace(data))}
+}
+
+// selectJSON JsonOfSelect() and its endpoint
+func selectJSON(built *xb.Built) (string, xb.QdrantEndpoint, error) {
+	body, err := built.JsonOfSelect()
+	if err != nil {
+		return "", xb.QdrantEndpoint{}, err
+	}
+	ep, err := built.QdrantEndpoint()
+	return body, ep, err
+}
+
+// Search runs a search, recommend, discover, query or hybrid Built
+// Scroll, groups, batch and facet Builts have their own functions
+// A Rerank() stage is applied to the decoded results
+func Search[T any](ctx context.Context, c *Client, collection string, built *xb.Built) ([]xb.ScoredPoint[T], error) {
+	body, ep, err := selectJSON(built)
+	if err != nil {
+		return nil, err
+	}
+	switch ep.Path {
+	case "/points/search", "/points/recommend", "/points/discover":
+		data, err := c.Do(ctx, collection, ep, body)
+		if err != nil {
+			return nil, err
+		}
+		results, err := xb.DecodeQdrantSearch[T](data)
+		if err != nil {
+			return nil, err
+		}
+		return xb.ApplyRerank(ctx, built, results)
+	case "/points/query":
+		data, err := c.Do(ctx, collection, ep, body)
+		if err != nil {
+			return nil, err
+		}
+		results, err := xb.DecodeQdrantQuery[T](data)
+		if err != nil {
+			return nil, err
+		}
+		return xb.ApplyRerank(ctx, built, results)
+	}
+	return nil, fmt.Errorf("qdrant: Search() can not run %s, use Scroll/Groups/Batch/Facet", ep)
+}
+
+// Scroll runs a scroll Built (QdrantBuilder.Scroll / Offset), one page per call
+//
+// Example:
+//
+//	page, err := qdrant.Scroll[CodeVector](ctx, client, "code_vectors", built)
+//	for page.HasNext() {
+//	    next := xb.Of(&CodeVector{}).
+//	        Custom(xb.NewQdrantBuilder().Offset(*page.NextPageOffset).Build()).
+//	        Build()
+//	    page, err = qdrant.Scroll[CodeVector](ctx, client, "code_vectors", next)
+//	}
+func Scroll[T any](ctx context.Context, c *Client, collection string, built *xb.Built) (*xb.QdrantScrollPage[T], error) {
+	body, ep, err := selectJSON(built)
+	if err != nil {
+		return nil, err
+	}
+	if ep.Path != "/points/scroll" {
+		return nil, fmt.Errorf("qdrant: Scroll() requires a scroll Built, got %s", ep)
+	}
+	data, err := c.Do(ctx, collection, ep, body)
+	if err != nil {
+		return nil, err
+	}
+	return xb.DecodeQdrantScroll[T](data)
+}
+
+// Groups runs a search with GroupBy()
+func Groups[T any](ctx context.Context, c *Client, collection string, built *xb.Built) ([]xb.QdrantGroup[T], error) {
+	body, ep, err := selectJSON(built)
+	if err != nil {
+		return nil, err
+	}
+	if ep.Path != "/points/search/groups" {
+		return nil, fmt.Errorf("qdrant: Groups() requires a GroupBy() search, got %s", ep)
+	}
+	data, err := c.Do(ctx, collection, ep, body)
+	if err != nil {
+		return nil, err
+	}
+	return xb.DecodeQdrantGroups[T](data)
+}
+
+// Batch runs several searches in one request, result[i] answers builts[i]
+func Batch[T any](ctx context.Context, c *Client, collection string, builts ...*xb.Built) ([][]xb.ScoredPoint[T], error) {
+	body, err := xb.QdrantBatch(builts...)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.Do(ctx, collection, (&xb.QdrantBatchSearchRequest{}).Endpoint(), body)
+	if err != nil {
+		return nil, err
+	}
+	return xb.SplitQdrantBatchResponse[T](data, len(builts))
+}
+
+// Facet runs a facet Built (QdrantBuilder.Facet)
+func (c *Client) Facet(ctx context.Context, collection string, built *xb.Built) ([]xb.QdrantFacetHit, error) {
+	body, ep, err := selectJSON(built)
+	if err != nil {
+		return nil, err
+	}
+	if ep.Path != "/facet" {
+		return nil, fmt.Errorf("qdrant: Facet() requires a facet Built, got %s", ep)
+	}
+	data, err := c.Do(ctx, collection, ep, body)
+	if err != nil {
+		return nil, err
+	}
+	return xb.DecodeQdrantFacet(data)
+}
+
+// Upsert sends JsonOfInsert() to PUT /points
+func (c *Client) Upsert(ctx context.Context, collection string, built *xb.Built) error {
+	body, err := built.JsonOfInsert()
+	if err != nil {
+		return err
+	}
+	ep, err := built.QdrantEndpoint()
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(ctx, collection, ep, body)
+	return err
+}
+
+// Update sends the payload or vector update to the endpoint of its mode, see xb.Built.JsonOfQdrantUpdate
+func (c *Client) Update(ctx context.Context, collection string, built *xb.Built) error {
+	body, ep, err := built.JsonOfQdrantUpdate()
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(ctx, collection, ep, body)
+	return err
+}
+
+// Delete sends JsonOfDelete() to POST /points/delete
+func (c *Client) Delete(ctx context.Context, collection string, built *xb.Built) error {
+	body, err := built.JsonOfDelete()
+	if err != nil {
+		return err
+	}
+	ep, err := built.QdrantEndpoint()
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(ctx, collection, ep, body)
+	return err
+}
+
+// CreateCollection creates the collection and its payload indexes
+func (c *Client) CreateCollection(ctx context.Context, b *xb.QdrantCollectionBuilder) error {
+	req, err := b.CreateRequest()
+	if err != nil {
+		return err
+	}
+	body, err := b.CreateJSON()
+	if err != nil {
+		return err
+	}
+	if _, err := c.Do(ctx, b.Name(), req.Endpoint(), body); err != nil {
+		return err
+	}
+	indexes, err := b.IndexJSON()
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if _, err := c.Do(ctx, b.Name(), (&xb.QdrantPayloadIndexRequest{}).Endpoint(), index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteCollection deletes the collection, a missing collection is not an error
+func (c *Client) DeleteCollection(ctx context.Context, collection string) error {
+	_, err := c.Do(ctx, collection, xb.QdrantEndpoint{Method: http.MethodDelete}, "")
+	return err
+}
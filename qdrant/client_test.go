@@ -0,0 +1,518 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fndome/xb"
+)
+
+type Doc struct {
+	Id        int64     `db:"id" json:"id"`
+	Language  string    `db:"language" json:"language"`
+	Layer     string    `db:"layer" json:"layer"`
+	Embedding xb.Vector `db:"embedding" json:"-"`
+}
+
+func (Doc) TableName() string {
+	return "docs"
+}
+
+var docs = []struct {
+	id       int
+	vector   []float32
+	language string
+	layer    string
+}{
+	{1, []float32{1, 0, 0}, "golang", "service"},
+	{2, []float32{0.9, 0.1, 0}, "golang", "repository"},
+	{3, []float32{0, 1, 0}, "python", "service"},
+	{4, []float32{0, 0.9, 0.1}, "python", "repository"},
+	{5, []float32{0, 0, 1}, "rust", "service"},
+}
+
+func custom() *xb.QdrantCustom {
+	return xb.NewQdrantBuilder().Build()
+}
+
+// seeded fake server with the docs in collection "docs"
+func seeded(t *testing.T) (*FakeServer, *Client) {
+	t.Helper()
+	fake := NewFakeServer()
+	t.Cleanup(fake.Close)
+	fake.CreateCollection("docs", 3, xb.CosineDistance)
+	client := fake.NewClient()
+	for _, d := range docs {
+		built := xb.Of(&Doc{}).Custom(custom()).
+			Insert(func(ib *xb.InsertBuilder) {
+				ib.Set("id", d.id).Set("vector", d.vector).
+					Set("language", d.language).Set("layer", d.layer)
+			}).
+			Build()
+		if err := client.Upsert(context.Background(), "docs", built); err != nil {
+			t.Fatalf("Upsert %d failed: %v", d.id, err)
+		}
+	}
+	return fake, client
+}
+
+func ids[T any](points []xb.ScoredPoint[T]) []string {
+	out := make([]string, 0, len(points))
+	for _, p := range points {
+		out = append(out, p.ID.(xb.PointID).String())
+	}
+	return out
+}
+
+func assertIDs(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("ids = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ids = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestClient_SearchWithFilter(t *testing.T) {
+	_, client := seeded(t)
+	ctx := context.Background()
+
+	built := xb.Of(&Doc{}).Custom(custom()).
+		Eq("layer", "service").
+		VectorSearch("embedding", xb.Vector{1, 0.2, 0}, 2).
+		Build()
+	points, err := Search[Doc](ctx, client, "docs", built)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	assertIDs(t, ids(points), "1", "3")
+	if points[0].Payload.Language != "golang" || points[0].Score <= points[1].Score {
+		t.Errorf("unexpected points: %+v", points)
+	}
+
+	// Scroll and facet are rejected by Search()
+	scroll := xb.Of(&Doc{}).Custom(xb.NewQdrantBuilder().Scroll().Build()).Build()
+	if _, err := Search[Doc](ctx, client, "docs", scroll); err == nil {
+		t.Error("expected error for a scroll Built")
+	}
+}
+
+func TestClient_SearchRerank(t *testing.T) {
+	_, client := seeded(t)
+
+	// Vector order is 1 2 3 4 5, python docs are boosted over the first candidates
+	built := xb.Of(&Doc{}).Custom(custom()).
+		VectorSearch("embedding", xb.Vector{1, 0.2, 0}, 2).
+		Rerank(func(rr *xb.RerankBuilder) {
+			rr.With(xb.LexicalOverlap("language")).Query("python").Candidates(5)
+		}).
+		Build()
+	points, err := Search[Doc](context.Background(), client, "docs", built)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	assertIDs(t, ids(points), "3", "4")
+	if points[0].Score != 1 {
+		t.Errorf("score = %v, want the reranker score", points[0].Score)
+	}
+}
+
+func TestClient_UpdateAndDelete(t *testing.T) {
+	_, client := seeded(t)
+	ctx := context.Background()
+
+	update := xb.Of(&Doc{}).Custom(custom()).
+		Eq("language", "python").
+		Update(func(ub *xb.UpdateBuilder) { ub.Set("layer", "api") }).
+		Build()
+	if err := client.Update(ctx, "docs", update); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	del := xb.Of(&Doc{}).Custom(custom()).In("id", 1, 5).Build()
+	if err := client.Delete(ctx, "docs", del); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	search := xb.Of(&Doc{}).Custom(custom()).
+		Eq("layer", "api").
+		VectorSearch("embedding", xb.Vector{0, 1, 0}, 10).
+		Build()
+	points, err := Search[Doc](ctx, client, "docs", search)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	assertIDs(t, ids(points), "3", "4")
+
+	all := xb.Of(&Doc{}).Custom(custom()).VectorSearch("embedding", xb.Vector{1, 1, 1}, 10).Build()
+	points, err = Search[Doc](ctx, client, "docs", all)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(points) != 3 {
+		t.Errorf("want 3 points after delete, got %v", ids(points))
+	}
+}
+
+func TestClient_RecommendDiscover(t *testing.T) {
+	_, client := seeded(t)
+	ctx := context.Background()
+
+	recommend := xb.Of(&Doc{}).Custom(xb.NewQdrantBuilder().Recommend(func(rb *xb.RecommendBuilder) {
+		rb.Positive(1).Negative(5).Limit(2)
+	}).Build()).Build()
+	points, err := Search[Doc](ctx, client, "docs", recommend)
+	if err != nil {
+		t.Fatalf("recommend failed: %v", err)
+	}
+	// Examples are never returned
+	assertIDs(t, ids(points), "2", "3")
+
+	discover := xb.Of(&Doc{}).Custom(xb.NewQdrantBuilder().Discover(func(db *xb.DiscoverBuilder) {
+		db.Target(3).Pair(4, 1).Limit(2)
+	}).Build()).Build()
+	points, err = Search[Doc](ctx, client, "docs", discover)
+	if err != nil {
+		t.Fatalf("discover failed: %v", err)
+	}
+	if len(points) == 0 || points[0].ID.(xb.PointID).String() != "5" {
+		t.Errorf("unexpected discover result: %v", ids(points))
+	}
+
+	missing := xb.Of(&Doc{}).Custom(xb.NewQdrantBuilder().Recommend(func(rb *xb.RecommendBuilder) {
+		rb.Positive(99).Limit(2)
+	}).Build()).Build()
+	var qErr *Error
+	if _, err := Search[Doc](ctx, client, "docs", missing); !errors.As(err, &qErr) || qErr.StatusCode != http.StatusNotFound {
+		t.Errorf("want 404 *Error, got %v", err)
+	}
+}
+
+func TestClient_ScrollPages(t *testing.T) {
+	_, client := seeded(t)
+	ctx := context.Background()
+
+	var got []string
+	qb := xb.NewQdrantBuilder().Scroll()
+	for pages := 0; pages < 10; pages++ {
+		built := xb.Of(&Doc{}).Custom(qb.Build()).
+			QdrantX(func(qx *xb.QdrantXBuilder) { qx.X("limit", 2) }).
+			Build()
+		page, err := Scroll[Doc](ctx, client, "docs", built)
+		if err != nil {
+			t.Fatalf("Scroll failed: %v", err)
+		}
+		got = append(got, ids(page.Points)...)
+		if !page.HasNext() {
+			break
+		}
+		qb = xb.NewQdrantBuilder().Offset(*page.NextPageOffset)
+	}
+	assertIDs(t, got, "1", "2", "3", "4", "5")
+
+	// scroll_id is not part of the Qdrant API
+	legacy := xb.Of(&Doc{}).Custom(xb.NewQdrantBuilder().ScrollID("3").Build()).Build()
+	var qErr *Error
+	if _, err := Scroll[Doc](ctx, client, "docs", legacy); !errors.As(err, &qErr) || qErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("want 400 for scroll_id, got %v", err)
+	}
+}
+
+func TestClient_GroupsBatchFacet(t *testing.T) {
+	_, client := seeded(t)
+	ctx := context.Background()
+
+	groupsBuilt := xb.Of(&Doc{}).Custom(custom()).
+		GroupBy("language").
+		VectorSearch("embedding", xb.Vector{1, 0, 0}, 2).
+		QdrantX(func(qx *xb.QdrantXBuilder) { qx.GroupSize(1) }).
+		Build()
+	groups, err := Groups[Doc](ctx, client, "docs", groupsBuilt)
+	if err != nil {
+		t.Fatalf("Groups failed: %v", err)
+	}
+	if len(groups) != 2 || groups[0].ID != "golang" || len(groups[0].Hits) != 1 {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+
+	batch, err := Batch[Doc](ctx, client, "docs",
+		xb.Of(&Doc{}).Custom(custom()).Eq("language", "golang").VectorSearch("embedding", xb.Vector{0, 1, 0}, 1).Build(),
+		xb.Of(&Doc{}).Custom(custom()).Eq("language", "rust").VectorSearch("embedding", xb.Vector{1, 0, 0}, 1).Build(),
+	)
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if len(batch) != 2 {
+		t.Fatalf("want 2 results, got %d", len(batch))
+	}
+	assertIDs(t, ids(batch[0]), "2")
+	assertIDs(t, ids(batch[1]), "5")
+
+	facet := xb.Of(&Doc{}).Custom(xb.NewQdrantBuilder().Facet("language", 2).Build()).Build()
+	hits, err := client.Facet(ctx, "docs", facet)
+	if err != nil {
+		t.Fatalf("Facet failed: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Value != "golang" || hits[0].Count != 2 || hits[1].Value != "python" {
+		t.Errorf("unexpected facet hits: %+v", hits)
+	}
+}
+
+func TestClient_HybridQuery(t *testing.T) {
+	_, client := seeded(t)
+
+	built := xb.Of(&Doc{}).Custom(custom()).
+		Hybrid(func(h *xb.HybridBuilder) {
+			h.Vector("embedding", xb.Vector{1, 0, 0}, 3).
+				Vector("embedding", xb.Vector{0.8, 0.2, 0}, 3).
+				Limit(2)
+		}).
+		Build()
+	points, err := Search[Doc](context.Background(), client, "docs", built)
+	if err != nil {
+		t.Fatalf("hybrid failed: %v", err)
+	}
+	assertIDs(t, ids(points), "1", "2")
+}
+
+func TestClient_CreateCollection(t *testing.T) {
+	fake := NewFakeServer()
+	defer fake.Close()
+	client := fake.NewClient()
+	ctx := context.Background()
+
+	collection := xb.QdrantCollection("images").
+		NamedVector("image", 2, xb.L2Distance).
+		NamedVector("text", 3, xb.CosineDistance).
+		KeywordIndex("language")
+	if err := client.CreateCollection(ctx, collection); err != nil {
+		t.Fatalf("CreateCollection failed: %v", err)
+	}
+
+	// An unnamed vector does not fit a collection of named vectors
+	insert := xb.Of(&Doc{}).Custom(custom()).
+		Insert(func(ib *xb.InsertBuilder) { ib.Set("id", 1).Set("vector", []float32{1, 2}) }).
+		Build()
+	var qErr *Error
+	if err := client.Upsert(ctx, "images", insert); !errors.As(err, &qErr) || qErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("want 400 *Error, got %v", err)
+	}
+
+	if err := client.DeleteCollection(ctx, "images"); err != nil {
+		t.Fatalf("DeleteCollection failed: %v", err)
+	}
+	search := xb.Of(&Doc{}).Custom(custom()).VectorSearch("embedding", xb.Vector{1, 0}, 1).Build()
+	if _, err := Search[Doc](ctx, client, "images", search); !errors.As(err, &qErr) || qErr.StatusCode != http.StatusNotFound {
+		t.Errorf("want 404 *Error, got %v", err)
+	}
+}
+
+func TestClient_NamedAndMultiVectors(t *testing.T) {
+	fake := NewFakeServer()
+	defer fake.Close()
+	client := fake.NewClient()
+	ctx := context.Background()
+
+	collection := xb.QdrantCollection("passages").
+		NamedVector("embedding", 2, xb.CosineDistance).
+		MultiVector("colbert", 2, xb.CosineDistance)
+	if err := client.CreateCollection(ctx, collection); err != nil {
+		t.Fatalf("CreateCollection failed: %v", err)
+	}
+
+	named := xb.NewQdrantBuilder().NamedVectors().Build()
+	passages := []struct {
+		id        int
+		embedding xb.Vector
+		tokens    xb.MultiVector
+	}{
+		{1, xb.Vector{1, 0}, xb.MultiVector{{0, 1}}},
+		{2, xb.Vector{0, 1}, xb.MultiVector{{1, 0}, {0, 1}}},
+		{3, xb.Vector{0.7, 0.7}, xb.MultiVector{{1, 0}}},
+	}
+	for _, p := range passages {
+		built := xb.Of(&Doc{}).Custom(named).
+			Insert(func(ib *xb.InsertBuilder) {
+				ib.Set("id", p.id).Set("embedding", p.embedding).Set("colbert", p.tokens).Set("language", "golang")
+			}).
+			Build()
+		if err := client.Upsert(ctx, "passages", built); err != nil {
+			t.Fatalf("Upsert %d failed: %v", p.id, err)
+		}
+	}
+
+	dense := xb.Of(&Doc{}).Custom(named).VectorSearch("embedding", xb.Vector{1, 0}, 2).Build()
+	points, err := Search[Doc](ctx, client, "passages", dense)
+	if err != nil {
+		t.Fatalf("named search failed: %v", err)
+	}
+	assertIDs(t, ids(points), "1", "3")
+
+	multi := xb.Of(&Doc{}).Custom(named).
+		MultiVectorSearch("colbert", xb.MultiVector{{1, 0}, {0, 1}}, 3).
+		Build()
+	points, err = Search[Doc](ctx, client, "passages", multi)
+	if err != nil {
+		t.Fatalf("multivector search failed: %v", err)
+	}
+	assertIDs(t, ids(points), "2", "1", "3")
+
+	// A dense vector can not be searched as a multivector
+	wrong := xb.Of(&Doc{}).Custom(named).
+		MultiVectorSearch("embedding", xb.MultiVector{{1, 0}}, 3).
+		Build()
+	var qErr *Error
+	if _, err := Search[Doc](ctx, client, "passages", wrong); !errors.As(err, &qErr) || qErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("want 400 *Error, got %v", err)
+	}
+}
+
+func TestClient_SparseHybrid(t *testing.T) {
+	fake := NewFakeServer()
+	defer fake.Close()
+	client := fake.NewClient()
+	ctx := context.Background()
+
+	collection := xb.QdrantCollection("articles").
+		Vectors(2, xb.CosineDistance).
+		SparseVector("text_sparse", "idf")
+	if err := client.CreateCollection(ctx, collection); err != nil {
+		t.Fatalf("CreateCollection failed: %v", err)
+	}
+
+	articles := []struct {
+		id     int
+		dense  xb.Vector
+		sparse xb.SparseVector
+	}{
+		{1, xb.Vector{1, 0}, xb.NewSparseVector(map[uint32]float32{3: 0.1})},
+		{2, xb.Vector{0.8, 0.6}, xb.NewSparseVector(map[uint32]float32{3: 2, 8: 1})},
+		{3, xb.Vector{0, 1}, xb.NewSparseVector(map[uint32]float32{8: 0.2})},
+	}
+	for _, a := range articles {
+		built := xb.Of(&Doc{}).Custom(custom()).
+			Insert(func(ib *xb.InsertBuilder) {
+				ib.Set("id", a.id).Set("vector", a.dense).Set("text_sparse", a.sparse).Set("language", "golang")
+			}).
+			Build()
+		if err := client.Upsert(ctx, "articles", built); err != nil {
+			t.Fatalf("Upsert %d failed: %v", a.id, err)
+		}
+	}
+
+	query := xb.NewSparseVector(map[uint32]float32{3: 1})
+	sparse := xb.Of(&Doc{}).Custom(custom()).SparseVectorSearch("text_sparse", query, 3).Build()
+	points, err := Search[Doc](ctx, client, "articles", sparse)
+	if err != nil {
+		t.Fatalf("sparse search failed: %v", err)
+	}
+	assertIDs(t, ids(points), "2", "1")
+
+	// Dense ranks 2, 3, 1; sparse ranks 2, 1: 1 is found by both legs, 3 by one
+	hybrid := xb.Of(&Doc{}).Custom(custom()).
+		Hybrid(func(h *xb.HybridBuilder) {
+			h.Vector("embedding", xb.Vector{0.6, 0.8}, 3).
+				Sparse("text_sparse", query, 3).
+				Limit(2)
+		}).
+		Build()
+	points, err = Search[Doc](ctx, client, "articles", hybrid)
+	if err != nil {
+		t.Fatalf("hybrid search failed: %v", err)
+	}
+	assertIDs(t, ids(points), "2", "1")
+}
+
+// recorder captures the requests sent through WithTransport
+type recorder struct {
+	next     http.RoundTripper
+	requests []*http.Request
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+	return r.next.RoundTrip(req)
+}
+
+func TestClient_APIKeyTransportAndWait(t *testing.T) {
+	fake := NewFakeServer().RequireAPIKey("secret")
+	defer fake.Close()
+	fake.CreateCollection("docs", 3, xb.CosineDistance)
+	ctx := context.Background()
+	insert := xb.Of(&Doc{}).Custom(custom()).
+		Insert(func(ib *xb.InsertBuilder) { ib.Set("id", 1).Set("vector", []float32{1, 0, 0}) }).
+		Build()
+
+	var qErr *Error
+	anonymous := New(fake.URL)
+	if err := anonymous.Upsert(ctx, "docs", insert); !errors.As(err, &qErr) || qErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("want 401 *Error, got %v", err)
+	}
+
+	rec := &recorder{next: http.DefaultTransport}
+	client := New(fake.URL, WithAPIKey("secret"), WithTransport(rec))
+	if err := client.Upsert(ctx, "docs", insert); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	search := xb.Of(&Doc{}).Custom(custom()).VectorSearch("embedding", xb.Vector{1, 0, 0}, 1).Build()
+	if _, err := Search[Doc](ctx, client, "docs", search); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	if len(rec.requests) != 2 {
+		t.Fatalf("want 2 requests, got %d", len(rec.requests))
+	}
+	upsert, query := rec.requests[0], rec.requests[1]
+	if upsert.Header.Get("api-key") != "secret" || upsert.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected headers: %v", upsert.Header)
+	}
+	if upsert.Method != http.MethodPut || upsert.URL.Path != "/collections/docs/points" || upsert.URL.RawQuery != "wait=true" {
+		t.Errorf("unexpected upsert request: %s %s", upsert.Method, upsert.URL)
+	}
+	if query.URL.Path != "/collections/docs/points/search" || query.URL.RawQuery != "" {
+		t.Errorf("unexpected search request: %s", query.URL)
+	}
+}
+
+// blocking never answers, until the request context is done
+type blocking struct{}
+
+func (blocking) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestClient_Timeout(t *testing.T) {
+	client := New("http://qdrant.invalid", WithTransport(blocking{}), WithTimeout(20*time.Millisecond))
+	search := xb.Of(&Doc{}).Custom(custom()).VectorSearch("embedding", xb.Vector{1, 0, 0}, 1).Build()
+
+	start := time.Now()
+	_, err := Search[Doc](context.Background(), client, "docs", search)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("timeout not applied: %v", time.Since(start))
+	}
+}
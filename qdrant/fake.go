@@ -0,0 +1,1525 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package qdrant
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+
+	"github.com/fndome/xb"
+)
+
+// ============================================================================
+// In-process fake Qdrant
+// ============================================================================
+//
+// FakeServer answers the REST endpoints xb generates JSON for, with a
+// brute-force index (Cosine, Dot, Euclid, Manhattan) and the filter semantics
+// of QdrantFilter.Matches. It is meant for tests, not for benchmarks:
+//   - collections, points, payload and vectors live in memory
+//   - search, batch, groups, recommend, discover, scroll, count, facet and query (nearest, rrf, dbsf)
+//   - payload indexes are accepted and ignored, every filter works without them
+
+// FakeServer in-process Qdrant for tests
+//
+// Example:
+//
+//	fake := qdrant.NewFakeServer()
+//	defer fake.Close()
+//	fake.CreateCollection("code_vectors", 768, xb.CosineDistance)
+//
+//	client := fake.NewClient()
+//	err := client.Upsert(ctx, "code_vectors", insertBuilt)
+//	points, err := qdrant.Search[CodeVector](ctx, client, "code_vectors", searchBuilt)
+type FakeServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	apiKey      string
+	collections map[string]*fakeCollection
+}
+
+type fakeCollection struct {
+	vectors map[string]xb.QdrantVectorParams // "" is the unnamed vector
+	sparse  map[string]bool                  // sparse_vectors
+	points  map[xb.PointID]*fakePoint
+}
+
+type fakePoint struct {
+	id      xb.PointID
+	vectors map[string][]float32
+	multi   map[string][][]float32 // multivectors (multivector_config)
+	sparse  map[string]xb.SparseVector
+	payload map[string]interface{}
+}
+
+type fakeError struct {
+	code int
+	msg  string
+}
+
+func (e *fakeError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &fakeError{code: http.StatusBadRequest, msg: "Wrong input: " + fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &fakeError{code: http.StatusNotFound, msg: "Not found: " + fmt.Sprintf(format, args...)}
+}
+
+// NewFakeServer starts a fake Qdrant, call Close() when done
+func NewFakeServer() *FakeServer {
+	s := &FakeServer{collections: map[string]*fakeCollection{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /collections/{collection}", s.handleCreateCollection)
+	mux.HandleFunc("DELETE /collections/{collection}", s.handleDeleteCollection)
+	s.route(mux, "GET /collections/{collection}", s.collectionInfo)
+	s.route(mux, "PUT /collections/{collection}/index", s.createIndex)
+	s.route(mux, "PUT /collections/{collection}/points", s.upsert)
+	s.route(mux, "POST /collections/{collection}/points/delete", s.deletePoints)
+	s.route(mux, "POST /collections/{collection}/points/payload", s.setPayload)
+	s.route(mux, "PUT /collections/{collection}/points/payload", s.overwritePayload)
+	s.route(mux, "POST /collections/{collection}/points/payload/delete", s.deletePayload)
+	s.route(mux, "POST /collections/{collection}/points/payload/clear", s.clearPayload)
+	s.route(mux, "PUT /collections/{collection}/points/vectors", s.updateVectors)
+	s.route(mux, "POST /collections/{collection}/points/vectors/delete", s.deleteVectors)
+	s.route(mux, "POST /collections/{collection}/points/search", s.search)
+	s.route(mux, "POST /collections/{collection}/points/search/batch", s.searchBatch)
+	s.route(mux, "POST /collections/{collection}/points/search/groups", s.searchGroups)
+	s.route(mux, "POST /collections/{collection}/points/recommend", s.recommend)
+	s.route(mux, "POST /collections/{collection}/points/discover", s.discover)
+	s.route(mux, "POST /collections/{collection}/points/scroll", s.scroll)
+	s.route(mux, "POST /collections/{collection}/points/count", s.count)
+	s.route(mux, "POST /collections/{collection}/points/query", s.query)
+	s.route(mux, "POST /collections/{collection}/facet", s.facet)
+
+	s.Server = httptest.NewServer(s.authorize(mux))
+	return s
+}
+
+// RequireAPIKey rejects requests without this api-key header (401)
+func (s *FakeServer) RequireAPIKey(key string) *FakeServer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = key
+	return s
+}
+
+// NewClient Client of this server, with the api-key of RequireAPIKey()
+func (s *FakeServer) NewClient(opts ...Option) *Client {
+	s.mu.Lock()
+	key := s.apiKey
+	s.mu.Unlock()
+	return New(s.URL, append([]Option{WithAPIKey(key), WithTransport(s.Client().Transport)}, opts...)...)
+}
+
+// CreateCollection creates a collection with one unnamed vector
+// Use Client.CreateCollection() with xb.QdrantCollection() for named vectors
+func (s *FakeServer) CreateCollection(name string, size int, metric xb.VectorDistance) {
+	distance := "Cosine"
+	switch metric {
+	case xb.L2Distance:
+		distance = "Euclid"
+	case xb.InnerProduct:
+		distance = "Dot"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections[name] = &fakeCollection{
+		vectors: map[string]xb.QdrantVectorParams{"": {Size: size, Distance: distance}},
+		sparse:  map[string]bool{},
+		points:  map[xb.PointID]*fakePoint{},
+	}
+}
+
+// authorize checks the api-key header
+func (s *FakeServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		key := s.apiKey
+		s.mu.Unlock()
+		if key != "" && r.Header.Get("api-key") != key {
+			writeError(w, &fakeError{code: http.StatusUnauthorized, msg: "Invalid api-key"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// route decodes the body, locks the server and runs fn on the collection
+func (s *FakeServer) route(mux *http.ServeMux, pattern string, fn func(c *fakeCollection, body *json.Decoder) (interface{}, error)) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		name := r.PathValue("collection")
+		c, ok := s.collections[name]
+		if !ok {
+			writeError(w, notFound("Collection `%s` doesn't exist!", name))
+			return
+		}
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		result, err := fn(c, dec)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResult(w, result)
+	})
+}
+
+func writeResult(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"result": result,
+		"status": "ok",
+		"time":   0.0001,
+	})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	if fe, ok := err.(*fakeError); ok {
+		code = fe.code
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": map[string]string{"error": err.Error()},
+		"time":   0.0001,
+	})
+}
+
+func decode(body *json.Decoder, v interface{}) error {
+	if err := body.Decode(v); err != nil {
+		return badRequest("%v", err)
+	}
+	return nil
+}
+
+var completed = map[string]interface{}{"operation_id": 0, "status": "completed"}
+
+// ============================================================================
+// Collections
+// ============================================================================
+
+func (s *FakeServer) handleCreateCollection(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Vectors       map[string]json.RawMessage `json:"vectors"`
+		SparseVectors map[string]json.RawMessage `json:"sparse_vectors"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, badRequest("%v", err))
+		return
+	}
+
+	vectors := map[string]xb.QdrantVectorParams{}
+	if _, ok := req.Vectors["size"]; ok {
+		var params xb.QdrantVectorParams
+		raw, _ := json.Marshal(req.Vectors)
+		json.Unmarshal(raw, &params)
+		vectors[""] = params
+	} else {
+		for name, raw := range req.Vectors {
+			var params xb.QdrantVectorParams
+			if err := json.Unmarshal(raw, &params); err != nil {
+				writeError(w, badRequest("vector %q: %v", name, err))
+				return
+			}
+			vectors[name] = params
+		}
+	}
+	for name, params := range vectors {
+		if params.Size <= 0 {
+			writeError(w, badRequest("vector %q: size must be positive", name))
+			return
+		}
+	}
+
+	sparse := map[string]bool{}
+	for name := range req.SparseVectors {
+		sparse[name] = true
+	}
+	if len(vectors) == 0 && len(sparse) == 0 {
+		writeError(w, badRequest("collection needs vectors or sparse_vectors"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections[r.PathValue("collection")] = &fakeCollection{vectors: vectors, sparse: sparse, points: map[xb.PointID]*fakePoint{}}
+	writeResult(w, true)
+}
+
+func (s *FakeServer) handleDeleteCollection(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.collections[r.PathValue("collection")]
+	delete(s.collections, r.PathValue("collection"))
+	writeResult(w, ok)
+}
+
+func (s *FakeServer) collectionInfo(c *fakeCollection, _ *json.Decoder) (interface{}, error) {
+	return map[string]interface{}{"status": "green", "points_count": len(c.points)}, nil
+}
+
+func (s *FakeServer) createIndex(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req xb.QdrantPayloadIndexRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	return completed, nil
+}
+
+// ============================================================================
+// Points, payload and vectors
+// ============================================================================
+
+// fakeSelector {"points": [...]} or {"filter": {...}}
+type fakeSelector struct {
+	Points []xb.PointID     `json:"points"`
+	Filter *xb.QdrantFilter `json:"filter"`
+}
+
+func (c *fakeCollection) selected(sel fakeSelector) ([]*fakePoint, error) {
+	if sel.Points == nil && sel.Filter == nil {
+		return nil, badRequest("points or filter is required")
+	}
+	var points []*fakePoint
+	if sel.Points != nil {
+		for _, id := range sel.Points {
+			if p, ok := c.points[id]; ok && sel.Filter.Matches(p.id, p.payload) {
+				points = append(points, p)
+			}
+		}
+		return points, nil
+	}
+	for _, p := range c.sorted() {
+		if sel.Filter.Matches(p.id, p.payload) {
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
+
+// vectorsOf decodes an unnamed vector (array) or named vectors (object) into
+// a point without id and payload; named vectors with a multivector_config are
+// matrices, sparse vectors are {"indices", "values"}
+func (c *fakeCollection) vectorsOf(raw json.RawMessage) (*fakePoint, error) {
+	p := &fakePoint{
+		vectors: map[string][]float32{},
+		multi:   map[string][][]float32{},
+		sparse:  map[string]xb.SparseVector{},
+	}
+	var plain []float32
+	var named map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &plain); err == nil {
+		named = map[string]json.RawMessage{"": raw}
+	} else if err := json.Unmarshal(raw, &named); err != nil {
+		return nil, badRequest("vector: %v", err)
+	}
+	for name, v := range named {
+		if c.sparse[name] {
+			var sv xb.SparseVector
+			if err := json.Unmarshal(v, &sv); err != nil {
+				return nil, badRequest("sparse vector %q: %v", name, err)
+			}
+			if err := sv.Validate(); err != nil {
+				return nil, badRequest("sparse vector %q: %v", name, err)
+			}
+			p.sparse[name] = sv
+			continue
+		}
+		params, ok := c.vectors[name]
+		if !ok {
+			if name == "" {
+				return nil, badRequest("collection has named vectors, got an unnamed vector")
+			}
+			return nil, badRequest("Not existing vector name error: %s", name)
+		}
+		if params.MultivectorConfig != nil {
+			var matrix [][]float32
+			if err := json.Unmarshal(v, &matrix); err != nil || len(matrix) == 0 {
+				return nil, badRequest("vector %q: expected a multivector", name)
+			}
+			for _, row := range matrix {
+				if len(row) != params.Size {
+					return nil, badRequest("Vector dimension error: expected dim: %d, got %d", params.Size, len(row))
+				}
+			}
+			p.multi[name] = matrix
+			continue
+		}
+		var vec []float32
+		if err := json.Unmarshal(v, &vec); err != nil {
+			return nil, badRequest("vector %q: %v", name, err)
+		}
+		if len(vec) != params.Size {
+			return nil, badRequest("Vector dimension error: expected dim: %d, got %d", params.Size, len(vec))
+		}
+		p.vectors[name] = vec
+	}
+	return p, nil
+}
+
+func (s *FakeServer) upsert(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		Points []struct {
+			ID      xb.PointID             `json:"id"`
+			Vector  json.RawMessage        `json:"vector"`
+			Payload map[string]interface{} `json:"payload"`
+		} `json:"points"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	points := make([]*fakePoint, 0, len(req.Points))
+	for _, p := range req.Points {
+		point, err := c.vectorsOf(p.Vector)
+		if err != nil {
+			return nil, err
+		}
+		if p.Payload == nil {
+			p.Payload = map[string]interface{}{}
+		}
+		point.id, point.payload = p.ID, p.Payload
+		points = append(points, point)
+	}
+	// All or nothing, like a Qdrant batch
+	for _, p := range points {
+		c.points[p.id] = p
+	}
+	return completed, nil
+}
+
+func (s *FakeServer) deletePoints(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req fakeSelector
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	points, err := c.selected(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		delete(c.points, p.id)
+	}
+	return completed, nil
+}
+
+func (s *FakeServer) setPayload(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeSelector
+		Payload map[string]interface{} `json:"payload"`
+		Key     string                 `json:"key"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	points, err := c.selected(req.fakeSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		target := p.payload
+		if req.Key != "" {
+			nested, ok := p.payload[req.Key].(map[string]interface{})
+			if !ok {
+				nested = map[string]interface{}{}
+				p.payload[req.Key] = nested
+			}
+			target = nested
+		}
+		for k, v := range req.Payload {
+			target[k] = v
+		}
+	}
+	return completed, nil
+}
+
+func (s *FakeServer) overwritePayload(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeSelector
+		Payload map[string]interface{} `json:"payload"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	points, err := c.selected(req.fakeSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		p.payload = map[string]interface{}{}
+		for k, v := range req.Payload {
+			p.payload[k] = v
+		}
+	}
+	return completed, nil
+}
+
+func (s *FakeServer) deletePayload(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeSelector
+		Keys []string `json:"keys"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	points, err := c.selected(req.fakeSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		for _, k := range req.Keys {
+			delete(p.payload, k)
+		}
+	}
+	return completed, nil
+}
+
+func (s *FakeServer) clearPayload(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req fakeSelector
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	points, err := c.selected(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		p.payload = map[string]interface{}{}
+	}
+	return completed, nil
+}
+
+func (s *FakeServer) updateVectors(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		Points []struct {
+			ID     xb.PointID      `json:"id"`
+			Vector json.RawMessage `json:"vector"`
+		} `json:"points"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	for _, u := range req.Points {
+		p, ok := c.points[u.ID]
+		if !ok {
+			return nil, notFound("No point with id %s found", u.ID)
+		}
+		update, err := c.vectorsOf(u.Vector)
+		if err != nil {
+			return nil, err
+		}
+		for name, vec := range update.vectors {
+			p.vectors[name] = vec
+		}
+		for name, matrix := range update.multi {
+			p.multi[name] = matrix
+		}
+		for name, sv := range update.sparse {
+			p.sparse[name] = sv
+		}
+	}
+	return completed, nil
+}
+
+func (s *FakeServer) deleteVectors(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeSelector
+		Vector []string `json:"vector"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	points, err := c.selected(req.fakeSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		for _, name := range req.Vector {
+			delete(p.vectors, name)
+			delete(p.multi, name)
+			delete(p.sparse, name)
+		}
+	}
+	return completed, nil
+}
+
+// ============================================================================
+// Scoring
+// ============================================================================
+
+// scored a point with its score, sim is the similarity (higher is better)
+type scored struct {
+	point *fakePoint
+	score float64
+	sim   float64
+}
+
+// similarity higher is better; Euclid and Manhattan return the negative distance
+func similarity(distance string, a, b []float32) float64 {
+	switch distance {
+	case "Euclid":
+		var sum float64
+		for i := range a {
+			d := float64(a[i]) - float64(b[i])
+			sum += d * d
+		}
+		return -math.Sqrt(sum)
+	case "Manhattan":
+		var sum float64
+		for i := range a {
+			sum += math.Abs(float64(a[i]) - float64(b[i]))
+		}
+		return -sum
+	case "Dot":
+		return dot(a, b)
+	}
+	// Cosine: Qdrant normalizes vectors, the score is the dot product of unit vectors
+	na, nb := math.Sqrt(dot(a, a)), math.Sqrt(dot(b, b))
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot(a, b) / (na * nb)
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// isDistance the score of Euclid and Manhattan is a distance (lower is better)
+func isDistance(distance string) bool {
+	return distance == "Euclid" || distance == "Manhattan"
+}
+
+// sorted points in scroll order: numeric ids ascending, then UUIDs
+func (c *fakeCollection) sorted() []*fakePoint {
+	points := make([]*fakePoint, 0, len(c.points))
+	for _, p := range c.points {
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return lessID(points[i].id, points[j].id)
+	})
+	return points
+}
+
+func lessID(a, b xb.PointID) bool {
+	if a.IsUUID() != b.IsUUID() {
+		return !a.IsUUID()
+	}
+	if !a.IsUUID() {
+		return a.Num() < b.Num()
+	}
+	return a.String() < b.String()
+}
+
+func (c *fakeCollection) params(using string) (xb.QdrantVectorParams, error) {
+	params, ok := c.vectors[using]
+	if !ok {
+		if using == "" {
+			return params, badRequest("collection has named vectors, set the vector name")
+		}
+		return params, badRequest("Not existing vector name error: %s", using)
+	}
+	return params, nil
+}
+
+// nearest scores the candidates (or every point) that match filter against query
+func (c *fakeCollection) nearest(using string, query []float32, filter *xb.QdrantFilter, candidates []*fakePoint) ([]scored, error) {
+	params, err := c.params(using)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) != params.Size {
+		return nil, badRequest("Vector dimension error: expected dim: %d, got %d", params.Size, len(query))
+	}
+	if candidates == nil {
+		candidates = c.sorted()
+	}
+	var hits []scored
+	for _, p := range candidates {
+		vec, ok := p.vectors[using]
+		if !ok || !filter.Matches(p.id, p.payload) {
+			continue
+		}
+		sim := similarity(params.Distance, query, vec)
+		score := sim
+		if isDistance(params.Distance) {
+			score = -sim
+		}
+		hits = append(hits, scored{point: p, score: score, sim: sim})
+	}
+	sortBySim(hits)
+	return hits, nil
+}
+
+// maxSim scores the multivectors of the candidates by max_sim: the sum over
+// the query rows of the best similarity to any document row
+func (c *fakeCollection) maxSim(using string, query [][]float32, filter *xb.QdrantFilter, candidates []*fakePoint) ([]scored, error) {
+	params, err := c.params(using)
+	if err != nil {
+		return nil, err
+	}
+	if params.MultivectorConfig == nil {
+		return nil, badRequest("vector %q is not a multivector", using)
+	}
+	for _, row := range query {
+		if len(row) != params.Size {
+			return nil, badRequest("Vector dimension error: expected dim: %d, got %d", params.Size, len(row))
+		}
+	}
+	if candidates == nil {
+		candidates = c.sorted()
+	}
+	var hits []scored
+	for _, p := range candidates {
+		matrix, ok := p.multi[using]
+		if !ok || !filter.Matches(p.id, p.payload) {
+			continue
+		}
+		var sim float64
+		for _, q := range query {
+			best := math.Inf(-1)
+			for _, d := range matrix {
+				best = math.Max(best, similarity(params.Distance, q, d))
+			}
+			sim += best
+		}
+		hits = append(hits, scored{point: p, score: sim, sim: sim})
+	}
+	sortBySim(hits)
+	return hits, nil
+}
+
+// sparseDot scores the sparse vectors of the candidates by dot product
+func (c *fakeCollection) sparseDot(using string, query xb.SparseVector, filter *xb.QdrantFilter, candidates []*fakePoint) ([]scored, error) {
+	if !c.sparse[using] {
+		return nil, badRequest("Not existing sparse vector name error: %s", using)
+	}
+	if err := query.Validate(); err != nil {
+		return nil, badRequest("sparse vector: %v", err)
+	}
+	if candidates == nil {
+		candidates = c.sorted()
+	}
+	var hits []scored
+	for _, p := range candidates {
+		sv, ok := p.sparse[using]
+		if !ok || !overlaps(query, sv) || !filter.Matches(p.id, p.payload) {
+			continue
+		}
+		sim := float64(query.Dot(sv))
+		hits = append(hits, scored{point: p, score: sim, sim: sim})
+	}
+	sortBySim(hits)
+	return hits, nil
+}
+
+// overlaps the sparse vectors share an index, Qdrant skips points without one
+func overlaps(a, b xb.SparseVector) bool {
+	i, j := 0, 0
+	for i < len(a.Indices) && j < len(b.Indices) {
+		switch {
+		case a.Indices[i] == b.Indices[j]:
+			return true
+		case a.Indices[i] < b.Indices[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return false
+}
+
+func sortBySim(hits []scored) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].sim != hits[j].sim {
+			return hits[i].sim > hits[j].sim
+		}
+		return lessID(hits[i].point.id, hits[j].point.id)
+	})
+}
+
+// page applies score_threshold, offset and limit
+func page(hits []scored, distance string, threshold *float32, offset, limit int) []scored {
+	if threshold != nil {
+		kept := hits[:0:0]
+		for _, h := range hits {
+			if isDistance(distance) && h.score <= float64(*threshold) ||
+				!isDistance(distance) && h.score >= float64(*threshold) {
+				kept = append(kept, h)
+			}
+		}
+		hits = kept
+	}
+	if offset >= len(hits) {
+		return nil
+	}
+	hits = hits[offset:]
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// ============================================================================
+// Search
+// ============================================================================
+
+// fakeOutput with_payload / with_vector of a request
+type fakeOutput struct {
+	WithPayload interface{} `json:"with_payload"` // true, false or []string
+	WithVector  interface{} `json:"with_vector"`  // true, false or []string
+	WithVectors interface{} `json:"with_vectors"`
+}
+
+// fakeSearch fields shared by search, recommend and discover
+type fakeSearch struct {
+	fakeOutput
+	Filter         *xb.QdrantFilter `json:"filter"`
+	Limit          int              `json:"limit"`
+	Offset         int              `json:"offset"`
+	ScoreThreshold *float32         `json:"score_threshold"`
+}
+
+func (o fakeOutput) render(p *fakePoint, score *float64) map[string]interface{} {
+	out := map[string]interface{}{"id": p.id, "version": 0}
+	if score != nil {
+		out["score"] = float32(*score)
+	}
+	switch with := o.WithPayload.(type) {
+	case bool:
+		if with {
+			out["payload"] = p.payload
+		}
+	case []interface{}:
+		payload := map[string]interface{}{}
+		for _, k := range with {
+			if v, ok := p.payload[fmt.Sprint(k)]; ok {
+				payload[fmt.Sprint(k)] = v
+			}
+		}
+		out["payload"] = payload
+	}
+
+	withVector := o.WithVector
+	if withVector == nil {
+		withVector = o.WithVectors
+	}
+	switch with := withVector.(type) {
+	case bool:
+		if !with {
+			break
+		}
+		if vec, ok := p.vectors[""]; ok && len(p.vectors) == 1 && len(p.multi) == 0 && len(p.sparse) == 0 {
+			out["vector"] = vec
+		} else {
+			vectors := map[string]interface{}{}
+			for name, vec := range p.vectors {
+				vectors[name] = vec
+			}
+			for name, matrix := range p.multi {
+				vectors[name] = matrix
+			}
+			for name, sv := range p.sparse {
+				vectors[name] = sv
+			}
+			out["vector"] = vectors
+		}
+	case []interface{}:
+		vectors := map[string]interface{}{}
+		for _, name := range with {
+			if vec, ok := p.vectors[fmt.Sprint(name)]; ok {
+				vectors[fmt.Sprint(name)] = vec
+			} else if matrix, ok := p.multi[fmt.Sprint(name)]; ok {
+				vectors[fmt.Sprint(name)] = matrix
+			} else if sv, ok := p.sparse[fmt.Sprint(name)]; ok {
+				vectors[fmt.Sprint(name)] = sv
+			}
+		}
+		out["vector"] = vectors
+	}
+	return out
+}
+
+func (o fakeOutput) renderAll(hits []scored) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(hits))
+	for i := range hits {
+		out = append(out, o.render(hits[i].point, &hits[i].score))
+	}
+	return out
+}
+
+// queryVector {"vector": [...]} or {"vector": {"name": "...", "vector": [...]}}
+func queryVector(raw json.RawMessage) (string, []float32, *xb.SparseVector, error) {
+	var plain []float32
+	if err := json.Unmarshal(raw, &plain); err == nil {
+		return "", plain, nil, nil
+	}
+	var named struct {
+		Name   string          `json:"name"`
+		Vector json.RawMessage `json:"vector"`
+	}
+	if err := json.Unmarshal(raw, &named); err != nil || named.Vector == nil {
+		return "", nil, nil, badRequest("vector must be an array or {name, vector}")
+	}
+	if err := json.Unmarshal(named.Vector, &plain); err == nil {
+		return named.Name, plain, nil, nil
+	}
+	var sv xb.SparseVector
+	if err := json.Unmarshal(named.Vector, &sv); err != nil || sv.Indices == nil {
+		return "", nil, nil, badRequest("vector must be an array or {indices, values}")
+	}
+	return named.Name, nil, &sv, nil
+}
+
+type fakeSearchRequest struct {
+	fakeSearch
+	Vector json.RawMessage `json:"vector"`
+}
+
+func (c *fakeCollection) runSearch(req fakeSearchRequest) ([]scored, error) {
+	using, vec, sv, err := queryVector(req.Vector)
+	if err != nil {
+		return nil, err
+	}
+	if sv != nil {
+		hits, err := c.sparseDot(using, *sv, req.Filter, nil)
+		if err != nil {
+			return nil, err
+		}
+		return page(hits, "Dot", req.ScoreThreshold, req.Offset, req.Limit), nil
+	}
+	hits, err := c.nearest(using, vec, req.Filter, nil)
+	if err != nil {
+		return nil, err
+	}
+	return page(hits, c.vectors[using].Distance, req.ScoreThreshold, req.Offset, req.Limit), nil
+}
+
+func (s *FakeServer) search(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req fakeSearchRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	hits, err := c.runSearch(req)
+	if err != nil {
+		return nil, err
+	}
+	return req.renderAll(hits), nil
+}
+
+func (s *FakeServer) searchBatch(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		Searches []fakeSearchRequest `json:"searches"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	result := make([][]map[string]interface{}, 0, len(req.Searches))
+	for _, search := range req.Searches {
+		hits, err := c.runSearch(search)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, search.renderAll(hits))
+	}
+	return result, nil
+}
+
+func (s *FakeServer) searchGroups(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeSearchRequest
+		GroupBy    string               `json:"group_by"`
+		GroupSize  int                  `json:"group_size"`
+		WithLookup *xb.QdrantWithLookup `json:"with_lookup"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.GroupBy == "" {
+		return nil, badRequest("group_by is required")
+	}
+	if req.GroupSize <= 0 {
+		req.GroupSize = 3
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	search := req.fakeSearchRequest
+	search.Limit, search.Offset = 0, 0
+	hits, err := c.runSearch(search)
+	if err != nil {
+		return nil, err
+	}
+
+	type group struct {
+		id   interface{}
+		hits []scored
+	}
+	var groups []*group
+	index := map[string]*group{}
+	for _, h := range hits {
+		for _, v := range xb.PayloadValues(h.point.payload, req.GroupBy) {
+			key := fmt.Sprint(v)
+			g, ok := index[key]
+			if !ok {
+				if len(groups) == req.Limit {
+					continue
+				}
+				g = &group{id: v}
+				index[key] = g
+				groups = append(groups, g)
+			}
+			if len(g.hits) < req.GroupSize {
+				g.hits = append(g.hits, h)
+			}
+		}
+	}
+
+	out := make([]map[string]interface{}, 0, len(groups))
+	for _, g := range groups {
+		entry := map[string]interface{}{"id": g.id, "hits": req.renderAll(g.hits)}
+		if req.WithLookup != nil {
+			if lookup := s.collections[req.WithLookup.Collection]; lookup != nil {
+				if id, err := xb.ParsePointID(g.id); err == nil {
+					if p, ok := lookup.points[id]; ok {
+						o := fakeOutput{WithPayload: req.WithLookup.WithPayload, WithVector: req.WithLookup.WithVectors}
+						if o.WithPayload == nil {
+							o.WithPayload = true
+						}
+						entry["lookup"] = o.render(p, nil)
+					}
+				}
+			}
+		}
+		out = append(out, entry)
+	}
+	return map[string]interface{}{"groups": out}, nil
+}
+
+// ============================================================================
+// Recommend and discover
+// ============================================================================
+
+// examples resolves ids (from this collection or lookup_from) to vectors
+func (s *FakeServer) examples(c *fakeCollection, using string, lookup *xb.QdrantLookupFrom, examples []xb.QdrantExample, exclude map[xb.PointID]bool) ([][]float32, error) {
+	source, vectorName := c, using
+	if lookup != nil {
+		source = s.collections[lookup.Collection]
+		if source == nil {
+			return nil, notFound("Collection `%s` doesn't exist!", lookup.Collection)
+		}
+		if lookup.Vector != "" {
+			vectorName = lookup.Vector
+		}
+	}
+	vectors := make([][]float32, 0, len(examples))
+	for _, e := range examples {
+		if e.IsVector() {
+			vectors = append(vectors, e.Vector)
+			continue
+		}
+		p, ok := source.points[e.ID]
+		if !ok {
+			return nil, notFound("No point with id %s found", e.ID)
+		}
+		vec, ok := p.vectors[vectorName]
+		if !ok {
+			return nil, badRequest("point %s has no vector %q", e.ID, vectorName)
+		}
+		vectors = append(vectors, vec)
+		if lookup == nil {
+			exclude[e.ID] = true
+		}
+	}
+	return vectors, nil
+}
+
+// candidates points that match filter and are not examples
+func (c *fakeCollection) candidates(using string, filter *xb.QdrantFilter, exclude map[xb.PointID]bool) []*fakePoint {
+	var points []*fakePoint
+	for _, p := range c.sorted() {
+		if _, ok := p.vectors[using]; ok && !exclude[p.id] && filter.Matches(p.id, p.payload) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+func (s *FakeServer) recommend(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeSearch
+		Positive   []xb.QdrantExample   `json:"positive"`
+		Negative   []xb.QdrantExample   `json:"negative"`
+		Strategy   string               `json:"strategy"`
+		Using      string               `json:"using"`
+		LookupFrom *xb.QdrantLookupFrom `json:"lookup_from"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	params, err := c.params(req.Using)
+	if err != nil {
+		return nil, err
+	}
+	exclude := map[xb.PointID]bool{}
+	positive, err := s.examples(c, req.Using, req.LookupFrom, req.Positive, exclude)
+	if err != nil {
+		return nil, err
+	}
+	negative, err := s.examples(c, req.Using, req.LookupFrom, req.Negative, exclude)
+	if err != nil {
+		return nil, err
+	}
+	candidates := c.candidates(req.Using, req.Filter, exclude)
+
+	var hits []scored
+	switch req.Strategy {
+	case "", string(xb.QdrantAverageVector):
+		if len(positive) == 0 {
+			return nil, badRequest("At least one positive example is required for average_vector")
+		}
+		// avg(positive) + avg(positive) - avg(negative)
+		query := average(positive)
+		if len(negative) > 0 {
+			avgNeg := average(negative)
+			for i := range query {
+				query[i] += query[i] - avgNeg[i]
+			}
+		}
+		hits, err = c.nearest(req.Using, query, nil, candidates)
+		if err != nil {
+			return nil, err
+		}
+	case string(xb.QdrantBestScore), string(xb.QdrantSumScores):
+		if len(positive) == 0 && len(negative) == 0 {
+			return nil, badRequest("At least one example is required")
+		}
+		for _, p := range candidates {
+			vec := p.vectors[req.Using]
+			var score float64
+			if req.Strategy == string(xb.QdrantSumScores) {
+				for _, e := range positive {
+					score += similarity(params.Distance, e, vec)
+				}
+				for _, e := range negative {
+					score -= similarity(params.Distance, e, vec)
+				}
+			} else {
+				score = bestScore(params.Distance, positive, negative, vec)
+			}
+			hits = append(hits, scored{point: p, score: score, sim: score})
+		}
+		sortBySim(hits)
+		// Combined scores are similarities for every metric
+		params.Distance = ""
+	default:
+		return nil, badRequest("unknown strategy %q", req.Strategy)
+	}
+	return req.renderAll(page(hits, params.Distance, req.ScoreThreshold, req.Offset, req.Limit)), nil
+}
+
+// bestScore best positive if it beats the best negative, else -(best negative)^2
+func bestScore(distance string, positive, negative [][]float32, vec []float32) float64 {
+	bestPos, bestNeg := math.Inf(-1), math.Inf(-1)
+	for _, e := range positive {
+		bestPos = math.Max(bestPos, similarity(distance, e, vec))
+	}
+	for _, e := range negative {
+		bestNeg = math.Max(bestNeg, similarity(distance, e, vec))
+	}
+	if len(positive) == 0 {
+		return -bestNeg
+	}
+	if bestPos > bestNeg {
+		return bestPos
+	}
+	return -(bestNeg * bestNeg)
+}
+
+func average(vectors [][]float32) []float32 {
+	avg := make([]float32, len(vectors[0]))
+	for _, v := range vectors {
+		for i := range avg {
+			avg[i] += v[i] / float32(len(vectors))
+		}
+	}
+	return avg
+}
+
+func (s *FakeServer) discover(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeSearch
+		Target     *xb.QdrantExample      `json:"target"`
+		Context    []xb.QdrantContextPair `json:"context"`
+		Using      string                 `json:"using"`
+		LookupFrom *xb.QdrantLookupFrom   `json:"lookup_from"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	params, err := c.params(req.Using)
+	if err != nil {
+		return nil, err
+	}
+	if req.Target == nil && len(req.Context) == 0 {
+		return nil, badRequest("target or context is required")
+	}
+
+	exclude := map[xb.PointID]bool{}
+	var target []float32
+	if req.Target != nil {
+		vectors, err := s.examples(c, req.Using, req.LookupFrom, []xb.QdrantExample{*req.Target}, exclude)
+		if err != nil {
+			return nil, err
+		}
+		target = vectors[0]
+	}
+	pairs := make([][2][]float32, 0, len(req.Context))
+	for _, pair := range req.Context {
+		vectors, err := s.examples(c, req.Using, req.LookupFrom, []xb.QdrantExample{pair.Positive, pair.Negative}, exclude)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, [2][]float32{vectors[0], vectors[1]})
+	}
+
+	var hits []scored
+	for _, p := range c.candidates(req.Using, req.Filter, exclude) {
+		vec := p.vectors[req.Using]
+		var score float64
+		for _, pair := range pairs {
+			pos := similarity(params.Distance, pair[0], vec)
+			neg := similarity(params.Distance, pair[1], vec)
+			if target == nil {
+				// Context search: how far the point is on the wrong side
+				score += math.Min(pos-neg, 0)
+			} else if pos > neg {
+				score++
+			} else {
+				score--
+			}
+		}
+		if target != nil {
+			score += 1 / (1 + math.Exp(-similarity(params.Distance, target, vec)))
+		}
+		hits = append(hits, scored{point: p, score: score, sim: score})
+	}
+	sortBySim(hits)
+	return req.renderAll(page(hits, "", req.ScoreThreshold, req.Offset, req.Limit)), nil
+}
+
+// ============================================================================
+// Scroll, count and facet
+// ============================================================================
+
+func (s *FakeServer) scroll(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeOutput
+		Offset *xb.PointID      `json:"offset"`
+		Limit  int              `json:"limit"`
+		Filter *xb.QdrantFilter `json:"filter"`
+	}
+	// Only the Qdrant wire format: a "scroll_id" is rejected, not read as the offset
+	body.DisallowUnknownFields()
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	if req.WithPayload == nil {
+		req.WithPayload = true
+	}
+
+	points := []map[string]interface{}{}
+	var next interface{}
+	for _, p := range c.sorted() {
+		if req.Offset != nil && lessID(p.id, *req.Offset) || !req.Filter.Matches(p.id, p.payload) {
+			continue
+		}
+		if len(points) == req.Limit {
+			next = p.id
+			break
+		}
+		points = append(points, req.render(p, nil))
+	}
+	return map[string]interface{}{"points": points, "next_page_offset": next}, nil
+}
+
+func (s *FakeServer) count(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		Filter *xb.QdrantFilter `json:"filter"`
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, p := range c.points {
+		if req.Filter.Matches(p.id, p.payload) {
+			n++
+		}
+	}
+	return map[string]int{"count": n}, nil
+}
+
+func (s *FakeServer) facet(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req xb.QdrantFacetRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	counts := map[string]int{}
+	values := map[string]interface{}{}
+	for _, p := range c.points {
+		if !req.Filter.Matches(p.id, p.payload) {
+			continue
+		}
+		// A point counts once per distinct value
+		seen := map[string]bool{}
+		for _, v := range xb.PayloadValues(p.payload, req.Key) {
+			key := fmt.Sprint(v)
+			if !seen[key] {
+				seen[key] = true
+				counts[key]++
+				values[key] = v
+			}
+		}
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > req.Limit {
+		keys = keys[:req.Limit]
+	}
+	hits := make([]map[string]interface{}, 0, len(keys))
+	for _, k := range keys {
+		hits = append(hits, map[string]interface{}{"value": values[k], "count": counts[k]})
+	}
+	return map[string]interface{}{"hits": hits}, nil
+}
+
+// ============================================================================
+// Query
+// ============================================================================
+
+// fakeQuery a /points/query request or one of its prefetch stages
+type fakeQuery struct {
+	Prefetch       []fakeQuery      `json:"prefetch"`
+	Query          json.RawMessage  `json:"query"`
+	Using          string           `json:"using"`
+	Filter         *xb.QdrantFilter `json:"filter"`
+	ScoreThreshold *float32         `json:"score_threshold"`
+	Limit          int              `json:"limit"`
+	Offset         int              `json:"offset"`
+}
+
+func (s *FakeServer) query(c *fakeCollection, body *json.Decoder) (interface{}, error) {
+	var req struct {
+		fakeOutput
+		fakeQuery
+	}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	hits, err := c.runQuery(req.fakeQuery)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"points": req.renderAll(hits)}, nil
+}
+
+// runQuery runs the prefetch stages, then the query over their results
+func (c *fakeCollection) runQuery(q fakeQuery) ([]scored, error) {
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
+	var stages [][]scored
+	var candidates []*fakePoint
+	if len(q.Prefetch) > 0 {
+		seen := map[xb.PointID]bool{}
+		candidates = []*fakePoint{}
+		for _, pre := range q.Prefetch {
+			hits, err := c.runQuery(pre)
+			if err != nil {
+				return nil, err
+			}
+			stages = append(stages, hits)
+			for _, h := range hits {
+				if !seen[h.point.id] {
+					seen[h.point.id] = true
+					candidates = append(candidates, h.point)
+				}
+			}
+		}
+	}
+
+	var hits []scored
+	distance := ""
+	var query struct {
+		Nearest []float32 `json:"nearest"`
+		Fusion  string    `json:"fusion"`
+	}
+	var vec []float32
+	var matrix [][]float32
+	var nearestMatrix struct {
+		Nearest [][]float32 `json:"nearest"`
+	}
+	var sparse struct {
+		xb.SparseVector
+		Nearest *xb.SparseVector `json:"nearest"`
+	}
+	switch {
+	case len(q.Query) == 0 || string(q.Query) == "null":
+		if stages != nil {
+			hits = stages[0]
+		} else {
+			for _, p := range c.sorted() {
+				if q.Filter.Matches(p.id, p.payload) {
+					hits = append(hits, scored{point: p})
+				}
+			}
+		}
+	case json.Unmarshal(q.Query, &vec) == nil:
+		query.Nearest = vec
+		fallthrough
+	case json.Unmarshal(q.Query, &query) == nil && (query.Nearest != nil || query.Fusion != ""):
+		if query.Fusion != "" {
+			if stages == nil {
+				return nil, badRequest("fusion requires prefetch")
+			}
+			fused, err := fuse(query.Fusion, stages)
+			if err != nil {
+				return nil, err
+			}
+			for _, h := range fused {
+				if q.Filter.Matches(h.point.id, h.point.payload) {
+					hits = append(hits, h)
+				}
+			}
+			break
+		}
+		var err error
+		hits, err = c.nearest(q.Using, query.Nearest, q.Filter, candidates)
+		if err != nil {
+			return nil, err
+		}
+		distance = c.vectors[q.Using].Distance
+	case json.Unmarshal(q.Query, &matrix) == nil,
+		json.Unmarshal(q.Query, &nearestMatrix) == nil && nearestMatrix.Nearest != nil:
+		if matrix == nil {
+			matrix = nearestMatrix.Nearest
+		}
+		var err error
+		hits, err = c.maxSim(q.Using, matrix, q.Filter, candidates)
+		if err != nil {
+			return nil, err
+		}
+	case json.Unmarshal(q.Query, &sparse) == nil && (sparse.Indices != nil || sparse.Nearest != nil):
+		if sparse.Nearest != nil {
+			sparse.SparseVector = *sparse.Nearest
+		}
+		var err error
+		hits, err = c.sparseDot(q.Using, sparse.SparseVector, q.Filter, candidates)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, badRequest("fake server does not support query %s", q.Query)
+	}
+	return page(hits, distance, q.ScoreThreshold, q.Offset, q.Limit), nil
+}
+
+// fuse rrf: sum of 1/(k+rank), k=2; dbsf: sum of scores normalized by mean±3σ
+func fuse(fusion string, stages [][]scored) ([]scored, error) {
+	total := map[xb.PointID]*scored{}
+	var order []xb.PointID
+	add := func(h scored, score float64) {
+		if t, ok := total[h.point.id]; ok {
+			t.score += score
+			return
+		}
+		total[h.point.id] = &scored{point: h.point, score: score}
+		order = append(order, h.point.id)
+	}
+	switch fusion {
+	case "rrf":
+		for _, hits := range stages {
+			for rank, h := range hits {
+				add(h, 1/float64(2+rank))
+			}
+		}
+	case "dbsf":
+		for _, hits := range stages {
+			if len(hits) == 0 {
+				continue
+			}
+			var mean, variance float64
+			for _, h := range hits {
+				mean += h.sim / float64(len(hits))
+			}
+			for _, h := range hits {
+				variance += (h.sim - mean) * (h.sim - mean) / float64(len(hits))
+			}
+			lo, hi := mean-3*math.Sqrt(variance), mean+3*math.Sqrt(variance)
+			for _, h := range hits {
+				norm := 0.5
+				if hi > lo {
+					norm = math.Max(0, math.Min(1, (h.sim-lo)/(hi-lo)))
+				}
+				add(h, norm)
+			}
+		}
+	default:
+		return nil, badRequest("unknown fusion %q", fusion)
+	}
+	fused := make([]scored, 0, len(order))
+	for _, id := range order {
+		t := total[id]
+		t.sim = t.score
+		fused = append(fused, *t)
+	}
+	sortBySim(fused)
+	return fused, nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ============================================================================
+// Batch search: POST /collections/{name}/points/search/batch
+// ============================================================================
+
+// QdrantBatchSearchRequest batch search body, one search request per Built
+// Documentation: https://qdrant.tech/documentation/concepts/search/#batch-search-api
+type QdrantBatchSearchRequest struct {
+	Searches []json.RawMessage `json:"searches"`
+}
+
+// QdrantBatch combines several search Builts into one batch search JSON
+// Each search keeps its own filter, limit and params
+// result[i] of the response answers builts[i], see SplitQdrantBatchResponse
+//
+// Example:
+//
+//	// One search per tenant shard
+//	searches := []*xb.Built{}
+//	for _, tenant := range tenants {
+//	    searches = append(searches, xb.Of(&CodeVector{}).
+//	        Custom(custom).
+//	        Eq("tenant", tenant).
+//	        VectorSearch("embedding", vec, 10).
+//	        Build())
+//	}
+//	body, err := xb.QdrantBatch(searches...)
+//	// POST /collections/code_vectors/points/search/batch
+func QdrantBatch(builts ...*Built) (string, error) {
+	if len(builts) == 0 {
+		return "", fmt.Errorf("QdrantBatch() requires at least one search")
+	}
+
+	req := &QdrantBatchSearchRequest{}
+	for i, built := range builts {
+		if built == nil {
+			return "", fmt.Errorf("batch search %d is nil", i)
+		}
+		if built.Custom != nil {
+			if _, ok := built.Custom.(*QdrantCustom); !ok {
+				return "", fmt.Errorf("batch search %d: Custom is %T, not Qdrant", i, built.Custom)
+			}
+		}
+		if op := qdrantAdvancedOp(ensureQdrantAdvanced(built).Conds); op != "" {
+			return "", fmt.Errorf("batch search %d: %s can not be batched as a search", i, op)
+		}
+		if len(built.GroupBys) > 0 {
+			return "", fmt.Errorf("batch search %d: grouped searches can not be batched", i)
+		}
+
+		search, err := built.toQdrantJSON()
+		if err != nil {
+			return "", fmt.Errorf("batch search %d: %w", i, err)
+		}
+		req.Searches = append(req.Searches, json.RawMessage(search))
+	}
+
+	bytes, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Qdrant batch request: %w", err)
+	}
+	return string(bytes), nil
+}
+
+// qdrantAdvancedOp returns the advanced API operator of conds, "" for a plain search
+func qdrantAdvancedOp(bbs []Bb) string {
+	for _, op := range []string{QDRANT_RECOMMEND, QDRANT_DISCOVER, QDRANT_SCROLL, QDRANT_QUERY, QDRANT_BATCH_SEARCH, HYBRID_SEARCH} {
+		if hasBbWithOp(bbs, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// Batch enables batch search mode, JsonOfSelect() outputs the batch body
+// The builder carrying the Custom is only a carrier, its own conditions are not allowed
+//
+// Example:
+//
+//	json, err := xb.Of(&CodeVector{}).
+//	    Custom(xb.NewQdrantBuilder().Batch(byTitle, byContent).Build()).
+//	    Build().
+//	    JsonOfSelect()
+func (qb *QdrantBuilder) Batch(builts ...*Built) *QdrantBuilder {
+	if len(builts) == 0 {
+		panic("Batch() requires at least one search")
+	}
+	for i, built := range builts {
+		if built == nil {
+			panic(fmt.Sprintf("Batch() search %d is nil", i))
+		}
+	}
+	qb.custom.batch = append([]*Built(nil), builts...)
+	return qb
+}
+
+// toQdrantBatchJSON internal implementation of Batch() mode
+func (built *Built) toQdrantBatchJSON() (string, error) {
+	var batch []*Built
+	for _, bb := range built.Conds {
+		switch {
+		case bb.Op == QDRANT_BATCH_SEARCH:
+			batch = bb.Value.([]*Built)
+		case isPureOperator(bb):
+		default:
+			return "", fmt.Errorf("batch mode: condition %s %s is not part of any search, add it to the batched Builts", bb.Key, bb.Op)
+		}
+	}
+	return QdrantBatch(batch...)
+}
+
+// SplitQdrantBatchResponse splits a batch search response per query
+// Points are decoded like DecodeQdrantSearch()
+// expected: number of batched searches, the response must have as many results
+//
+// Example:
+//
+//	results, err := xb.SplitQdrantBatchResponse[map[string]interface{}](body, len(searches))
+//	for i, points := range results {
+//	    // points answer searches[i]
+//	}
+func SplitQdrantBatchResponse[T any](body []byte, expected int) ([][]ScoredPoint[T], error) {
+	result, err := decodeQdrant[[][]qdrantWirePoint[T]](body, "batch")
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != expected {
+		return nil, fmt.Errorf("Qdrant batch response has %d results, expected %d", len(result), expected)
+	}
+	lists := make([][]ScoredPoint[T], 0, len(result))
+	for _, wire := range result {
+		points, err := scoredPoints(wire)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, points)
+	}
+	return lists, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"testing"
+)
+
+func batchSearches() []*Built {
+	custom := NewQdrantBuilder().HnswEf(64).Build()
+	return []*Built{
+		Of(&CodeVectorForQdrant{}).
+			Custom(custom).
+			Eq("tenant", "a").
+			VectorSearch("embedding", Vector{0.1, 0.2}, 5).
+			Build(),
+		Of(&CodeVectorForQdrant{}).
+			Custom(NewQdrantBuilder().ScoreThreshold(0.7).Build()).
+			In("tenant", "b", "c").
+			VectorSearch("embedding", Vector{0.3, 0.4}, 20).
+			QdrantX(func(qx *QdrantXBuilder) { qx.Exact(true) }).
+			Build(),
+	}
+}
+
+func TestQdrantBatch_KeepsPerSearchParams(t *testing.T) {
+	jsonStr, err := QdrantBatch(batchSearches()...)
+	if err != nil {
+		t.Fatalf("QdrantBatch failed: %v", err)
+	}
+	t.Logf("JSON:\n%s", jsonStr)
+	assertJSONEqual(t, jsonStr, `{"searches": [
+		{
+			"vector": [0.1, 0.2],
+			"limit": 5,
+			"filter": {"must": [{"key": "tenant", "match": {"value": "a"}}]},
+			"with_payload": true,
+			"params": {"hnsw_ef": 64}
+		},
+		{
+			"vector": [0.3, 0.4],
+			"limit": 20,
+			"filter": {"must": [{"key": "tenant", "match": {"any": ["b", "c"]}}]},
+			"with_payload": true,
+			"score_threshold": 0.7,
+			"params": {"hnsw_ef": 128, "exact": true}
+		}
+	]}`)
+
+	// Batch() mode on QdrantBuilder produces the same body
+	viaBuilder, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Batch(batchSearches()...).Build()).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("Batch() JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, viaBuilder, jsonStr)
+}
+
+func TestQdrantBatch_Errors(t *testing.T) {
+	if _, err := QdrantBatch(); err == nil {
+		t.Error("expected error for empty batch")
+	}
+
+	recommend := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) {
+			rb.Positive(1).Limit(5)
+		}).Build()).
+		Build()
+	if _, err := QdrantBatch(batchSearches()[0], recommend); err == nil {
+		t.Error("expected error, recommend is not a search")
+	}
+
+	noVector := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).Eq("a", 1).Build()
+	if _, err := QdrantBatch(noVector); err == nil {
+		t.Error("expected error for a Built without VectorSearch")
+	}
+
+	// Conditions on the carrier would be silently dropped
+	_, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Batch(batchSearches()...).Build()).
+		Eq("tenant", "x").
+		Build().
+		JsonOfSelect()
+	if err == nil {
+		t.Error("expected error for carrier conditions")
+	}
+}
+
+func TestSplitQdrantBatchResponse(t *testing.T) {
+	body := []byte(`{
+		"result": [
+			[{"id": 1, "score": 0.9, "payload": {"title": "a"}}, {"id": 2, "score": 0.8, "payload": {"title": "b"}}],
+			[]
+		],
+		"status": "ok",
+		"time": 0.002
+	}`)
+
+	type doc struct {
+		Title string `json:"title"`
+	}
+	results, err := SplitQdrantBatchResponse[doc](body, 2)
+	if err != nil {
+		t.Fatalf("SplitQdrantBatchResponse failed: %v", err)
+	}
+	if len(results) != 2 || len(results[0]) != 2 || len(results[1]) != 0 {
+		t.Fatalf("unexpected split: %+v", results)
+	}
+	if results[0][1].Payload.Title != "b" || results[0][0].Score != 0.9 {
+		t.Errorf("unexpected points: %+v", results[0])
+	}
+
+	if _, err := SplitQdrantBatchResponse[doc](body, 3); err == nil {
+		t.Error("expected error for result count mismatch")
+	}
+	if _, err := SplitQdrantBatchResponse[doc]([]byte(`{"status": {"error": "Not found: Collection"}}`), 1); err == nil {
+		t.Error("expected error for Qdrant error status")
+	}
+}
+
+func TestQdrantBuilder_BatchValidation(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	NewQdrantBuilder().Batch()
+}
@@ -0,0 +1,607 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ============================================================================
+// QdrantCollection: collection and payload index management
+// ============================================================================
+
+// QdrantCollection creates a collection management builder
+// Outputs request bodies, the HTTP call is up to the caller
+//
+// Example:
+//
+//	coll := xb.QdrantCollection("code_vectors_v2").
+//	    Vectors(768, xb.CosineDistance).
+//	    Hnsw(func(h *xb.QdrantHnswBuilder) {
+//	        h.M(16).EfConstruct(200)
+//	    }).
+//	    ScalarQuantization(0.99, true).
+//	    KeywordIndex("language").
+//	    TextIndex("content", xb.QdrantTokenizerWord)
+//
+//	body, err := coll.CreateJSON()   // PUT /collections/code_vectors_v2
+//	indexes, err := coll.IndexJSON() // PUT /collections/code_vectors_v2/index (one body per field)
+func QdrantCollection(name string) *QdrantCollectionBuilder {
+	if name == "" {
+		panic("QdrantCollection() requires a non-empty name")
+	}
+	return &QdrantCollectionBuilder{
+		name: name,
+		req:  &QdrantCreateCollectionRequest{},
+	}
+}
+
+// QdrantCollectionBuilder collection management builder
+type QdrantCollectionBuilder struct {
+	name    string
+	req     *QdrantCreateCollectionRequest
+	named   map[string]QdrantVectorParams
+	indexes []QdrantPayloadIndexRequest
+}
+
+// QdrantCreateCollectionRequest PUT /collections/{name}
+// Documentation: https://qdrant.tech/documentation/concepts/collections/
+type QdrantCreateCollectionRequest struct {
+	Vectors            interface{}                         `json:"vectors,omitempty"` // QdrantVectorParams or map of named QdrantVectorParams
+	SparseVectors      map[string]QdrantSparseVectorParams `json:"sparse_vectors,omitempty"`
+	HnswConfig         *QdrantHnswConfig                   `json:"hnsw_config,omitempty"`
+	OptimizersConfig   *QdrantOptimizersConfig             `json:"optimizers_config,omitempty"`
+	QuantizationConfig *QdrantQuantizationConfig           `json:"quantization_config,omitempty"`
+	OnDiskPayload      *bool                               `json:"on_disk_payload,omitempty"`
+}
+
+// QdrantVectorParams dense vector config
+type QdrantVectorParams struct {
+	Size              int                      `json:"size"`
+	Distance          string                   `json:"distance"` // Cosine, Euclid, Dot
+	MultivectorConfig *QdrantMultivectorConfig `json:"multivector_config,omitempty"`
+	Datatype          string                   `json:"datatype,omitempty"` // float32, float16, uint8
+}
+
+// QdrantMultivectorConfig {"comparator": "max_sim"}, points store one vector per token
+type QdrantMultivectorConfig struct {
+	Comparator string `json:"comparator"`
+}
+
+// QdrantSparseVectorParams sparse vector config
+type QdrantSparseVectorParams struct {
+	Modifier string `json:"modifier,omitempty"` // "idf" for BM25-like scoring
+}
+
+// QdrantHnswConfig HNSW index config
+type QdrantHnswConfig struct {
+	M                 *int  `json:"m,omitempty"`
+	EfConstruct       *int  `json:"ef_construct,omitempty"`
+	FullScanThreshold *int  `json:"full_scan_threshold,omitempty"`
+	OnDisk            *bool `json:"on_disk,omitempty"`
+	PayloadM          *int  `json:"payload_m,omitempty"`
+}
+
+// QdrantOptimizersConfig optimizer config
+type QdrantOptimizersConfig struct {
+	DeletedThreshold      *float64 `json:"deleted_threshold,omitempty"`
+	VacuumMinVectorNumber *int     `json:"vacuum_min_vector_number,omitempty"`
+	DefaultSegmentNumber  *int     `json:"default_segment_number,omitempty"`
+	IndexingThreshold     *int     `json:"indexing_threshold,omitempty"` // KB, 0 disables indexing
+	FlushIntervalSec      *int     `json:"flush_interval_sec,omitempty"`
+}
+
+// QdrantQuantizationConfig quantization config, exactly one of Scalar/Product/Binary
+type QdrantQuantizationConfig struct {
+	Scalar  *QdrantScalarQuantization  `json:"scalar,omitempty"`
+	Product *QdrantProductQuantization `json:"product,omitempty"`
+	Binary  *QdrantBinaryQuantization  `json:"binary,omitempty"`
+}
+
+// QdrantScalarQuantization int8 scalar quantization
+type QdrantScalarQuantization struct {
+	Type      string   `json:"type"` // int8
+	Quantile  *float64 `json:"quantile,omitempty"`
+	AlwaysRam bool     `json:"always_ram,omitempty"`
+}
+
+// QdrantProductQuantization product quantization
+type QdrantProductQuantization struct {
+	Compression string `json:"compression"` // x4, x8, x16, x32, x64
+	AlwaysRam   bool   `json:"always_ram,omitempty"`
+}
+
+// QdrantBinaryQuantization binary quantization
+type QdrantBinaryQuantization struct {
+	AlwaysRam bool `json:"always_ram,omitempty"`
+}
+
+// QdrantPayloadIndexRequest PUT /collections/{name}/index
+type QdrantPayloadIndexRequest struct {
+	FieldName   string      `json:"field_name"`
+	FieldSchema interface{} `json:"field_schema"` // "keyword", ... or QdrantTextIndexParams
+}
+
+// QdrantTextIndexParams full-text index params
+type QdrantTextIndexParams struct {
+	Type        string          `json:"type"` // text
+	Tokenizer   QdrantTokenizer `json:"tokenizer"`
+	MinTokenLen int             `json:"min_token_len,omitempty"`
+	MaxTokenLen int             `json:"max_token_len,omitempty"`
+}
+
+// QdrantTokenizer full-text index tokenizer
+type QdrantTokenizer string
+
+const (
+	QdrantTokenizerWord         QdrantTokenizer = "word"
+	QdrantTokenizerWhitespace   QdrantTokenizer = "whitespace"
+	QdrantTokenizerPrefix       QdrantTokenizer = "prefix"
+	QdrantTokenizerMultilingual QdrantTokenizer = "multilingual"
+)
+
+// Name collection name
+func (b *QdrantCollectionBuilder) Name() string {
+	return b.name
+}
+
+// Vectors single unnamed dense vector
+// Can not be combined with NamedVector()
+func (b *QdrantCollectionBuilder) Vectors(size int, metric VectorDistance) *QdrantCollectionBuilder {
+	if len(b.named) > 0 {
+		panic("Vectors() can not be combined with NamedVector()")
+	}
+	b.req.Vectors = QdrantVectorParams{
+		Size:     vectorSize(size),
+		Distance: QdrantDistanceMetric(metric),
+	}
+	return b
+}
+
+// NamedVector adds a named dense vector, e.g. "title" and "content" embeddings
+func (b *QdrantCollectionBuilder) NamedVector(name string, size int, metric VectorDistance) *QdrantCollectionBuilder {
+	if name == "" {
+		panic("NamedVector() name can not be empty")
+	}
+	if _, ok := b.req.Vectors.(QdrantVectorParams); ok {
+		panic("NamedVector() can not be combined with Vectors()")
+	}
+	if b.named == nil {
+		b.named = map[string]QdrantVectorParams{}
+		b.req.Vectors = b.named
+	}
+	b.named[name] = QdrantVectorParams{
+		Size:     vectorSize(size),
+		Distance: QdrantDistanceMetric(metric),
+	}
+	return b
+}
+
+// MultiVector adds a named multivector (ColBERT token vectors, max_sim comparator)
+// size: dimension of each token vector
+func (b *QdrantCollectionBuilder) MultiVector(name string, size int, metric VectorDistance) *QdrantCollectionBuilder {
+	b.NamedVector(name, size, metric)
+	params := b.named[name]
+	params.MultivectorConfig = &QdrantMultivectorConfig{Comparator: "max_sim"}
+	b.named[name] = params
+	return b
+}
+
+// Datatype sets the storage type of a dense vector, name "" is the unnamed Vectors()
+//
+//	Float32Encoding: "float32" (default)
+//	Float16Encoding: "float16", upsert Vector or HalfVector
+//	Int8Encoding:    "uint8", upsert Int8Vector (0..255), all points quantized with one range
+//
+// Binary storage is a quantization in Qdrant, see BinaryQuantization()
+//
+// Example:
+//
+//	xb.QdrantCollection("docs").Vectors(768, xb.CosineDistance).Datatype("", xb.Float16Encoding)
+func (b *QdrantCollectionBuilder) Datatype(name string, encoding VectorEncoding) *QdrantCollectionBuilder {
+	var datatype string
+	switch encoding {
+	case Float32Encoding:
+		datatype = "float32"
+	case Float16Encoding:
+		datatype = "float16"
+	case Int8Encoding:
+		datatype = "uint8"
+	default:
+		panic(fmt.Sprintf("Datatype() supports float32, float16 and int8 encodings, got: %q, use BinaryQuantization() for bits", encoding))
+	}
+
+	if name == "" {
+		params, ok := b.req.Vectors.(QdrantVectorParams)
+		if !ok {
+			panic("Datatype(\"\") requires Vectors()")
+		}
+		params.Datatype = datatype
+		b.req.Vectors = params
+		return b
+	}
+	params, ok := b.named[name]
+	if !ok {
+		panic(fmt.Sprintf("Datatype(%q) requires NamedVector(%q)", name, name))
+	}
+	params.Datatype = datatype
+	b.named[name] = params
+	return b
+}
+
+func vectorSize(size int) int {
+	if size < 1 {
+		panic(fmt.Sprintf("vector size must be >= 1, got: %d", size))
+	}
+	return size
+}
+
+// SparseVector adds a named sparse vector
+// modifier: optional, "idf" for BM25-like scoring
+func (b *QdrantCollectionBuilder) SparseVector(name string, modifier ...string) *QdrantCollectionBuilder {
+	if name == "" {
+		panic("SparseVector() name can not be empty")
+	}
+	params := QdrantSparseVectorParams{}
+	if len(modifier) > 0 {
+		if modifier[0] != "idf" && modifier[0] != "none" {
+			panic(fmt.Sprintf("SparseVector() modifier must be idf or none, got: %s", modifier[0]))
+		}
+		params.Modifier = modifier[0]
+	}
+	if b.req.SparseVectors == nil {
+		b.req.SparseVectors = map[string]QdrantSparseVectorParams{}
+	}
+	b.req.SparseVectors[name] = params
+	return b
+}
+
+// OnDiskPayload stores payload on disk instead of RAM
+func (b *QdrantCollectionBuilder) OnDiskPayload(onDisk bool) *QdrantCollectionBuilder {
+	b.req.OnDiskPayload = &onDisk
+	return b
+}
+
+// Hnsw sets the HNSW index config
+//
+// Example:
+//
+//	// Multitenancy: no global graph, per-tenant graphs on the payload index
+//	Hnsw(func(h *xb.QdrantHnswBuilder) {
+//	    h.M(0).PayloadM(16)
+//	})
+func (b *QdrantCollectionBuilder) Hnsw(fn func(h *QdrantHnswBuilder)) *QdrantCollectionBuilder {
+	h := &QdrantHnswBuilder{config: &QdrantHnswConfig{}}
+	fn(h)
+	b.req.HnswConfig = h.config
+	return b
+}
+
+// QdrantHnswBuilder HNSW config builder
+type QdrantHnswBuilder struct {
+	config *QdrantHnswConfig
+}
+
+// M edges per node, 0 disables the global graph
+func (h *QdrantHnswBuilder) M(m int) *QdrantHnswBuilder {
+	h.config.M = nonNegative("M", m)
+	return h
+}
+
+// EfConstruct neighbours considered while building the index, must be >= 4
+func (h *QdrantHnswBuilder) EfConstruct(ef int) *QdrantHnswBuilder {
+	if ef < 4 {
+		panic(fmt.Sprintf("EfConstruct must be >= 4, got: %d", ef))
+	}
+	h.config.EfConstruct = &ef
+	return h
+}
+
+// FullScanThreshold segments smaller than this (KB) are searched without the index
+func (h *QdrantHnswBuilder) FullScanThreshold(kb int) *QdrantHnswBuilder {
+	h.config.FullScanThreshold = nonNegative("FullScanThreshold", kb)
+	return h
+}
+
+// OnDisk stores the HNSW graph on disk
+func (h *QdrantHnswBuilder) OnDisk(onDisk bool) *QdrantHnswBuilder {
+	h.config.OnDisk = &onDisk
+	return h
+}
+
+// PayloadM edges per node of the payload-based graphs
+func (h *QdrantHnswBuilder) PayloadM(m int) *QdrantHnswBuilder {
+	h.config.PayloadM = nonNegative("PayloadM", m)
+	return h
+}
+
+// Optimizers sets the optimizer config
+//
+// Example:
+//
+//	// Bulk upload: disable indexing, enable it again afterwards
+//	Optimizers(func(o *xb.QdrantOptimizersBuilder) {
+//	    o.IndexingThreshold(0)
+//	})
+func (b *QdrantCollectionBuilder) Optimizers(fn func(o *QdrantOptimizersBuilder)) *QdrantCollectionBuilder {
+	o := &QdrantOptimizersBuilder{config: &QdrantOptimizersConfig{}}
+	fn(o)
+	b.req.OptimizersConfig = o.config
+	return b
+}
+
+// QdrantOptimizersBuilder optimizer config builder
+type QdrantOptimizersBuilder struct {
+	config *QdrantOptimizersConfig
+}
+
+// DeletedThreshold ratio of deleted vectors that triggers a vacuum, in [0, 1]
+func (o *QdrantOptimizersBuilder) DeletedThreshold(ratio float64) *QdrantOptimizersBuilder {
+	if ratio < 0 || ratio > 1 {
+		panic(fmt.Sprintf("DeletedThreshold must be in [0, 1], got: %f", ratio))
+	}
+	o.config.DeletedThreshold = &ratio
+	return o
+}
+
+// VacuumMinVectorNumber minimal vectors in a segment to vacuum it
+func (o *QdrantOptimizersBuilder) VacuumMinVectorNumber(n int) *QdrantOptimizersBuilder {
+	o.config.VacuumMinVectorNumber = nonNegative("VacuumMinVectorNumber", n)
+	return o
+}
+
+// DefaultSegmentNumber target number of segments, 0 picks one per CPU
+func (o *QdrantOptimizersBuilder) DefaultSegmentNumber(n int) *QdrantOptimizersBuilder {
+	o.config.DefaultSegmentNumber = nonNegative("DefaultSegmentNumber", n)
+	return o
+}
+
+// IndexingThreshold segment size (KB) above which vectors are indexed, 0 disables indexing
+func (o *QdrantOptimizersBuilder) IndexingThreshold(kb int) *QdrantOptimizersBuilder {
+	o.config.IndexingThreshold = nonNegative("IndexingThreshold", kb)
+	return o
+}
+
+// FlushIntervalSec interval between forced flushes
+func (o *QdrantOptimizersBuilder) FlushIntervalSec(sec int) *QdrantOptimizersBuilder {
+	o.config.FlushIntervalSec = nonNegative("FlushIntervalSec", sec)
+	return o
+}
+
+func nonNegative(name string, n int) *int {
+	if n < 0 {
+		panic(fmt.Sprintf("%s must be >= 0, got: %d", name, n))
+	}
+	return &n
+}
+
+// ScalarQuantization int8 scalar quantization
+// quantile: in (0.5, 1], 0 keeps the server default
+func (b *QdrantCollectionBuilder) ScalarQuantization(quantile float64, alwaysRam bool) *QdrantCollectionBuilder {
+	sq := &QdrantScalarQuantization{Type: "int8", AlwaysRam: alwaysRam}
+	if quantile != 0 {
+		if quantile <= 0.5 || quantile > 1 {
+			panic(fmt.Sprintf("ScalarQuantization quantile must be in (0.5, 1], got: %f", quantile))
+		}
+		sq.Quantile = &quantile
+	}
+	b.req.QuantizationConfig = &QdrantQuantizationConfig{Scalar: sq}
+	return b
+}
+
+// ProductQuantization product quantization
+// compression: x4, x8, x16, x32, x64
+func (b *QdrantCollectionBuilder) ProductQuantization(compression string, alwaysRam bool) *QdrantCollectionBuilder {
+	switch compression {
+	case "x4", "x8", "x16", "x32", "x64":
+	default:
+		panic(fmt.Sprintf("ProductQuantization compression must be x4, x8, x16, x32 or x64, got: %s", compression))
+	}
+	b.req.QuantizationConfig = &QdrantQuantizationConfig{
+		Product: &QdrantProductQuantization{Compression: compression, AlwaysRam: alwaysRam},
+	}
+	return b
+}
+
+// BinaryQuantization binary quantization (1 bit per dimension)
+func (b *QdrantCollectionBuilder) BinaryQuantization(alwaysRam bool) *QdrantCollectionBuilder {
+	b.req.QuantizationConfig = &QdrantQuantizationConfig{
+		Binary: &QdrantBinaryQuantization{AlwaysRam: alwaysRam},
+	}
+	return b
+}
+
+// KeywordIndex payload index for exact match (Eq, In, MatchExcept)
+func (b *QdrantCollectionBuilder) KeywordIndex(field string) *QdrantCollectionBuilder {
+	return b.index(field, "keyword")
+}
+
+// IntegerIndex payload index for integer match and range
+func (b *QdrantCollectionBuilder) IntegerIndex(field string) *QdrantCollectionBuilder {
+	return b.index(field, "integer")
+}
+
+// FloatIndex payload index for float range
+func (b *QdrantCollectionBuilder) FloatIndex(field string) *QdrantCollectionBuilder {
+	return b.index(field, "float")
+}
+
+// GeoIndex payload index for GeoRadius, GeoBoundingBox, GeoPolygon
+func (b *QdrantCollectionBuilder) GeoIndex(field string) *QdrantCollectionBuilder {
+	return b.index(field, "geo")
+}
+
+// DatetimeIndex payload index for Datetime ranges
+func (b *QdrantCollectionBuilder) DatetimeIndex(field string) *QdrantCollectionBuilder {
+	return b.index(field, "datetime")
+}
+
+// UuidIndex payload index for UUID match
+func (b *QdrantCollectionBuilder) UuidIndex(field string) *QdrantCollectionBuilder {
+	return b.index(field, "uuid")
+}
+
+// TextIndex full-text payload index, required by Like() (match.text)
+// tokenLen: optional min and max token length
+func (b *QdrantCollectionBuilder) TextIndex(field string, tokenizer QdrantTokenizer, tokenLen ...int) *QdrantCollectionBuilder {
+	if tokenizer == "" {
+		tokenizer = QdrantTokenizerWord
+	}
+	params := QdrantTextIndexParams{Type: "text", Tokenizer: tokenizer}
+	if len(tokenLen) > 0 {
+		params.MinTokenLen = *nonNegative("TextIndex min token length", tokenLen[0])
+	}
+	if len(tokenLen) > 1 {
+		params.MaxTokenLen = *nonNegative("TextIndex max token length", tokenLen[1])
+		if params.MaxTokenLen < params.MinTokenLen {
+			panic(fmt.Sprintf("TextIndex(%q) max token length < min token length", field))
+		}
+	}
+	return b.index(field, params)
+}
+
+func (b *QdrantCollectionBuilder) index(field string, schema interface{}) *QdrantCollectionBuilder {
+	if field == "" {
+		panic("payload index field can not be empty")
+	}
+	b.indexes = append(b.indexes, QdrantPayloadIndexRequest{
+		FieldName:   field,
+		FieldSchema: schema,
+	})
+	return b
+}
+
+// CreateRequest PUT /collections/{name} body
+func (b *QdrantCollectionBuilder) CreateRequest() (*QdrantCreateCollectionRequest, error) {
+	if b.req.Vectors == nil && len(b.req.SparseVectors) == 0 {
+		return nil, fmt.Errorf("collection %s: Vectors(), NamedVector() or SparseVector() is required", b.name)
+	}
+	return b.req, nil
+}
+
+// CreateJSON PUT /collections/{name} JSON
+func (b *QdrantCollectionBuilder) CreateJSON() (string, error) {
+	req, err := b.CreateRequest()
+	if err != nil {
+		return "", err
+	}
+	bytes, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Qdrant create collection request: %w", err)
+	}
+	return string(bytes), nil
+}
+
+// IndexRequests PUT /collections/{name}/index bodies, one per field
+func (b *QdrantCollectionBuilder) IndexRequests() []QdrantPayloadIndexRequest {
+	return append([]QdrantPayloadIndexRequest(nil), b.indexes...)
+}
+
+// IndexJSON PUT /collections/{name}/index JSON, one per field
+func (b *QdrantCollectionBuilder) IndexJSON() ([]string, error) {
+	var result []string
+	for _, idx := range b.indexes {
+		bytes, err := json.MarshalIndent(idx, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal Qdrant payload index %s: %w", idx.FieldName, err)
+		}
+		result = append(result, string(bytes))
+	}
+	return result, nil
+}
+
+// ============================================================================
+// Aliases
+// ============================================================================
+
+// QdrantAliasRequest POST /collections/aliases, actions are applied atomically
+type QdrantAliasRequest struct {
+	Actions []QdrantAliasAction `json:"actions"`
+}
+
+// QdrantAliasAction one alias action
+type QdrantAliasAction struct {
+	CreateAlias *QdrantAliasRef `json:"create_alias,omitempty"`
+	DeleteAlias *QdrantAliasRef `json:"delete_alias,omitempty"`
+}
+
+// QdrantAliasRef alias (and target collection for create_alias)
+type QdrantAliasRef struct {
+	CollectionName string `json:"collection_name,omitempty"`
+	AliasName      string `json:"alias_name"`
+}
+
+// QdrantAliasSwitch diffs alias => collection mappings into one atomic alias request
+// Unchanged aliases produce no action, aliases missing from desired are deleted
+//
+// Example (zero-downtime reindex):
+//
+//	// 1. create code_vectors_v2, upsert all points
+//	// 2. switch the alias the application queries
+//	body, err := xb.QdrantAliasSwitch(
+//	    map[string]string{"code_vectors": "code_vectors_v1"},
+//	    map[string]string{"code_vectors": "code_vectors_v2"},
+//	)
+//	// POST /collections/aliases
+//	// {"actions": [{"delete_alias": {"alias_name": "code_vectors"}},
+//	//              {"create_alias": {"collection_name": "code_vectors_v2", "alias_name": "code_vectors"}}]}
+func QdrantAliasSwitch(current, desired map[string]string) (string, error) {
+	req := &QdrantAliasRequest{Actions: []QdrantAliasAction{}}
+
+	aliases := make([]string, 0, len(current)+len(desired))
+	for alias := range current {
+		aliases = append(aliases, alias)
+	}
+	for alias := range desired {
+		if _, ok := current[alias]; !ok {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		if alias == "" {
+			return "", fmt.Errorf("alias name can not be empty")
+		}
+		from, hasFrom := current[alias]
+		to, hasTo := desired[alias]
+		if hasTo && to == "" {
+			return "", fmt.Errorf("alias %s: collection name can not be empty", alias)
+		}
+		if hasFrom && hasTo && from == to {
+			continue
+		}
+		if hasFrom {
+			req.Actions = append(req.Actions, QdrantAliasAction{
+				DeleteAlias: &QdrantAliasRef{AliasName: alias},
+			})
+		}
+		if hasTo {
+			req.Actions = append(req.Actions, QdrantAliasAction{
+				CreateAlias: &QdrantAliasRef{CollectionName: to, AliasName: alias},
+			})
+		}
+	}
+
+	bytes, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Qdrant alias request: %w", err)
+	}
+	return string(bytes), nil
+}
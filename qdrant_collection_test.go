@@ -0,0 +1,149 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"testing"
+)
+
+func TestQdrantCollection_Create(t *testing.T) {
+	coll := QdrantCollection("code_vectors_v2").
+		Vectors(768, CosineDistance).
+		OnDiskPayload(true).
+		Hnsw(func(h *QdrantHnswBuilder) {
+			h.M(0).PayloadM(16).EfConstruct(200)
+		}).
+		Optimizers(func(o *QdrantOptimizersBuilder) {
+			o.IndexingThreshold(0).DeletedThreshold(0.2)
+		}).
+		ScalarQuantization(0.99, true)
+
+	jsonStr, err := coll.CreateJSON()
+	if err != nil {
+		t.Fatalf("CreateJSON failed: %v", err)
+	}
+	t.Logf("JSON:\n%s", jsonStr)
+	assertJSONEqual(t, jsonStr, `{
+		"vectors": {"size": 768, "distance": "Cosine"},
+		"on_disk_payload": true,
+		"hnsw_config": {"m": 0, "payload_m": 16, "ef_construct": 200},
+		"optimizers_config": {"indexing_threshold": 0, "deleted_threshold": 0.2},
+		"quantization_config": {"scalar": {"type": "int8", "quantile": 0.99, "always_ram": true}}
+	}`)
+}
+
+func TestQdrantCollection_NamedAndSparse(t *testing.T) {
+	jsonStr, err := QdrantCollection("docs").
+		NamedVector("title", 384, InnerProduct).
+		NamedVector("content", 768, L2Distance).
+		SparseVector("bm25", "idf").
+		SparseVector("splade").
+		BinaryQuantization(false).
+		CreateJSON()
+	if err != nil {
+		t.Fatalf("CreateJSON failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{
+		"vectors": {
+			"title": {"size": 384, "distance": "Dot"},
+			"content": {"size": 768, "distance": "Euclid"}
+		},
+		"sparse_vectors": {"bm25": {"modifier": "idf"}, "splade": {}},
+		"quantization_config": {"binary": {}}
+	}`)
+
+	if _, err := QdrantCollection("empty").CreateJSON(); err == nil {
+		t.Error("expected error without vectors")
+	}
+}
+
+func TestQdrantCollection_PayloadIndexes(t *testing.T) {
+	coll := QdrantCollection("docs").
+		KeywordIndex("language").
+		IntegerIndex("stars").
+		FloatIndex("price").
+		GeoIndex("location").
+		DatetimeIndex("created_at").
+		UuidIndex("doc_id").
+		TextIndex("content", QdrantTokenizerMultilingual, 2, 20)
+
+	indexes, err := coll.IndexJSON()
+	if err != nil {
+		t.Fatalf("IndexJSON failed: %v", err)
+	}
+	want := []string{
+		`{"field_name": "language", "field_schema": "keyword"}`,
+		`{"field_name": "stars", "field_schema": "integer"}`,
+		`{"field_name": "price", "field_schema": "float"}`,
+		`{"field_name": "location", "field_schema": "geo"}`,
+		`{"field_name": "created_at", "field_schema": "datetime"}`,
+		`{"field_name": "doc_id", "field_schema": "uuid"}`,
+		`{"field_name": "content", "field_schema": {"type": "text", "tokenizer": "multilingual", "min_token_len": 2, "max_token_len": 20}}`,
+	}
+	if len(indexes) != len(want) {
+		t.Fatalf("expected %d index bodies, got %d", len(want), len(indexes))
+	}
+	for i := range want {
+		assertJSONEqual(t, indexes[i], want[i])
+	}
+}
+
+func TestQdrantAliasSwitch(t *testing.T) {
+	jsonStr, err := QdrantAliasSwitch(
+		map[string]string{"code": "code_v1", "docs": "docs_v3", "old": "legacy"},
+		map[string]string{"code": "code_v2", "docs": "docs_v3", "new": "code_v2"},
+	)
+	if err != nil {
+		t.Fatalf("QdrantAliasSwitch failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{"actions": [
+		{"delete_alias": {"alias_name": "code"}},
+		{"create_alias": {"collection_name": "code_v2", "alias_name": "code"}},
+		{"create_alias": {"collection_name": "code_v2", "alias_name": "new"}},
+		{"delete_alias": {"alias_name": "old"}}
+	]}`)
+
+	if _, err := QdrantAliasSwitch(nil, map[string]string{"code": ""}); err == nil {
+		t.Error("expected error for empty collection name")
+	}
+}
+
+func TestQdrantCollection_Validation(t *testing.T) {
+	cases := map[string]func(){
+		"empty name":      func() { QdrantCollection("") },
+		"size":            func() { QdrantCollection("c").Vectors(0, CosineDistance) },
+		"mixed vectors":   func() { QdrantCollection("c").Vectors(3, CosineDistance).NamedVector("a", 3, CosineDistance) },
+		"sparse modifier": func() { QdrantCollection("c").SparseVector("s", "bm25") },
+		"ef_construct":    func() { QdrantCollection("c").Hnsw(func(h *QdrantHnswBuilder) { h.EfConstruct(2) }) },
+		"negative m":      func() { QdrantCollection("c").Hnsw(func(h *QdrantHnswBuilder) { h.M(-1) }) },
+		"deleted ratio":   func() { QdrantCollection("c").Optimizers(func(o *QdrantOptimizersBuilder) { o.DeletedThreshold(2) }) },
+		"quantile":        func() { QdrantCollection("c").ScalarQuantization(0.3, false) },
+		"compression":     func() { QdrantCollection("c").ProductQuantization("x3", false) },
+		"index field":     func() { QdrantCollection("c").KeywordIndex("") },
+		"token len":       func() { QdrantCollection("c").TextIndex("t", QdrantTokenizerWord, 10, 2) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
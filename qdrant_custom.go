@@ -99,7 +99,26 @@ func (qb *QdrantBuilder) WithVector(withVector bool) *QdrantBuilder {
 	return qb
 }
 
+// NamedVectors uses the field names as Qdrant named vectors
+// For collections created with NamedVector() (title, body, image, ...):
+//   - VectorSearch("title", vec, k) => "vector": {"name": "title", "vector": [...]}
+//   - MultiVectorSearch / Hybrid legs => "using": field
+//   - Insert: every vector field becomes a named vector, "vector": {"title": [...], "body": [...]}
+//
+// Without it, field names are ignored and the collection's unnamed vector is used
+func (qb *QdrantBuilder) NamedVectors() *QdrantBuilder {
+	qb.custom.NamedVectors = true
+	return qb
+}
+
+// Embedder sets the Embedder of SemanticSearch() queries using this Custom
+func (qb *QdrantBuilder) Embedder(embedder Embedder) *QdrantBuilder {
+	qb.custom.embedder = embedder
+	return qb
+}
+
 // Recommend enables Qdrant Recommend API
+// Examples are point ids or raw vectors, see RecommendBuilder
 //
 // Example:
 //
@@ -119,28 +138,38 @@ func (qb *QdrantBuilder) Recommend(fn func(rb *RecommendBuilder)) *QdrantBuilder
 	fn(builder)
 
 	if len(builder.positive) == 0 {
-		panic("Recommend() requires at least one Positive() id")
+		// best_score and sum_scores can work with negative examples only
+		if builder.strategy == "" || builder.strategy == QdrantAverageVector || len(builder.negative) == 0 {
+			panic("Recommend() requires at least one Positive() example")
+		}
 	}
 	if builder.limit <= 0 {
 		panic("Recommend() requires Limit() > 0")
 	}
 
 	qb.custom.recommendConfig = &qdrantRecommendConfig{
-		positive: append([]int64(nil), builder.positive...),
-		negative: append([]int64(nil), builder.negative...),
-		limit:    builder.limit,
+		positive:   append([]QdrantExample(nil), builder.positive...),
+		negative:   append([]QdrantExample(nil), builder.negative...),
+		limit:      builder.limit,
+		strategy:   builder.strategy,
+		using:      builder.using,
+		lookupFrom: builder.lookupFrom,
 	}
 	return qb
 }
 
 // Discover enables Qdrant Discover API
+// Target is optional, without it Qdrant runs a context search
 //
 // Example:
 //
 //	xb.NewQdrantBuilder().
 //	    HnswEf(512).
 //	    Discover(func(db *xb.DiscoverBuilder) {
-//	        db.Context(101, 102, 103).Limit(20)
+//	        db.Target(100).
+//	            Pair(101, 102).
+//	            Pair(103, 104).
+//	            Limit(20)
 //	    }).
 //	    Build()
 func (qb *QdrantBuilder) Discover(fn func(db *DiscoverBuilder)) *QdrantBuilder {
@@ -153,21 +182,27 @@ func (qb *QdrantBuilder) Discover(fn func(db *DiscoverBuilder)) *QdrantBuilder {
 	fn(builder)
 
 	if len(builder.context) == 0 {
-		panic("Discover() requires Context() with at least one id")
+		panic("Discover() requires at least one Pair()")
 	}
 	if builder.limit <= 0 {
 		panic("Discover() requires Limit() > 0")
 	}
 
 	qb.custom.discoverConfig = &qdrantDiscoverConfig{
-		context: append([]int64(nil), builder.context...),
-		limit:   builder.limit,
+		target:     builder.target,
+		context:    append([]QdrantContextPair(nil), builder.context...),
+		limit:      builder.limit,
+		using:      builder.using,
+		lookupFrom: builder.lookupFrom,
 	}
 	return qb
 }
 
 // ScrollID enables Qdrant Scroll API
 //
+// Deprecated: the request carries "scroll_id", Qdrant paginates with "offset".
+// Use Scroll() for the first page and Offset() for the next ones.
+//
 // Example:
 //
 //	xb.NewQdrantBuilder().
@@ -182,6 +217,31 @@ func (qb *QdrantBuilder) ScrollID(scrollID string) *QdrantBuilder {
 	return qb
 }
 
+// Scroll enables Qdrant Scroll API from the first page (no offset)
+//
+// Example:
+//
+//	xb.NewQdrantBuilder().Scroll().Build()
+func (qb *QdrantBuilder) Scroll() *QdrantBuilder {
+	qb.custom.scroll = true
+	qb.custom.scrollOffset = nil
+	return qb
+}
+
+// Offset enables Qdrant Scroll API from the point id, the NextPageOffset of the previous page
+//
+// Example:
+//
+//	if page.HasNext() {
+//	    next := xb.NewQdrantBuilder().Offset(*page.NextPageOffset).Build()
+//	    // xb.Of(&Code{}).Custom(next)...JsonOfSelect() for the next page
+//	}
+func (qb *QdrantBuilder) Offset(id PointID) *QdrantBuilder {
+	qb.custom.scroll = true
+	qb.custom.scrollOffset = &id
+	return qb
+}
+
 // Build constructs and returns QdrantCustom configuration
 func (qb *QdrantBuilder) Build() *QdrantCustom {
 	return qb.custom
@@ -199,11 +259,19 @@ type QdrantCustom struct {
 	DefaultHnswEf         int     // Default HNSW EF parameter
 	DefaultScoreThreshold float32 // Default similarity threshold
 	DefaultWithVector     bool    // Default whether to return vectors
+	NamedVectors          bool    // VectorSearch field is the vector name, upserts send named vectors
+
+	embedder Embedder // SemanticSearch() embedder
 
-	// Advanced API configuration (Recommend / Discover / Scroll)
+	// Advanced API configuration (Recommend / Discover / Scroll / Query / Batch)
 	recommendConfig *qdrantRecommendConfig
 	discoverConfig  *qdrantDiscoverConfig
 	scrollID        string
+	scroll          bool     // Scroll() / Offset()
+	scrollOffset    *PointID // nil: first page
+	queryConfig     *QueryBuilder
+	batch           []*Built
+	facet           *qdrantFacetConfig
 }
 
 // newQdrantCustom internal function: creates Qdrant Custom (default configuration)
@@ -215,6 +283,11 @@ func newQdrantCustom() *QdrantCustom {
 	}
 }
 
+// QueryEmbedder implements EmbedderCustom
+func (c *QdrantCustom) QueryEmbedder() Embedder {
+	return c.embedder
+}
+
 // Generate implements Custom interface
 // ⭐ Returns different JSON based on operation type
 func (c *QdrantCustom) Generate(built *Built) (interface{}, error) {
@@ -236,19 +309,51 @@ func (c *QdrantCustom) Generate(built *Built) (interface{}, error) {
 	}
 
 	// ⭐ SELECT: generate Qdrant search JSON
-	switch {
-	case hasBbWithOp(built.Conds, QDRANT_RECOMMEND):
+	switch qdrantSelectOp(built) {
+	case QDRANT_BATCH_SEARCH:
+		return built.toQdrantBatchJSON()
+	case QDRANT_RECOMMEND:
 		return built.toQdrantRecommendJSON()
-	case hasBbWithOp(built.Conds, QDRANT_DISCOVER):
+	case QDRANT_DISCOVER:
 		return built.toQdrantDiscoverJSON()
-	case hasBbWithOp(built.Conds, QDRANT_SCROLL):
+	case QDRANT_SCROLL:
 		return built.toQdrantScrollJSON()
+	case QDRANT_QUERY:
+		return built.toQdrantQueryJSON()
+	case HYBRID_SEARCH:
+		return built.toQdrantHybridJSON()
+	case QDRANT_FACET:
+		return built.toQdrantFacetJSON()
+	case qdrantSearchGroups:
+		return built.toQdrantGroupsJSON()
 	default:
 		json, err := built.toQdrantJSON()
 		return json, err
 	}
 }
 
+// qdrantSearchGroups select operator of a search with GroupBy()
+const qdrantSearchGroups = "QDRANT_SEARCH_GROUPS"
+
+// qdrantSelectOp the API JsonOfSelect() generates: advanced operator,
+// qdrantSearchGroups, or "" for a plain search. The order is the priority.
+func qdrantSelectOp(built *Built) string {
+	for _, op := range []string{QDRANT_BATCH_SEARCH, QDRANT_RECOMMEND, QDRANT_DISCOVER, QDRANT_SCROLL, QDRANT_QUERY, HYBRID_SEARCH, QDRANT_FACET} {
+		if hasBbWithOp(built.Conds, op) {
+			return op
+		}
+	}
+	// Multivector (max_sim) search exists only in the query API
+	if vectorBb := findVectorSearchBb(built.Conds); vectorBb != nil &&
+		vectorBb.Value.(VectorSearchParams).QueryMultiVector != nil {
+		return QDRANT_QUERY
+	}
+	if len(built.GroupBys) > 0 {
+		return qdrantSearchGroups
+	}
+	return ""
+}
+
 // ============================================================================
 // Usage Instructions
 // ============================================================================
@@ -336,44 +441,10 @@ func (c *QdrantCustom) generateInsertJSON(built *Built) (string, error) {
 }
 
 // generateUpdateJSON generates Qdrant update payload JSON
-// POST /collections/{collection_name}/points/payload
+// POST /collections/{collection_name}/points/payload, other endpoints see JsonOfQdrantUpdate()
 func (c *QdrantCustom) generateUpdateJSON(built *Built) (string, error) {
-	updates := *built.Updates
-	if len(updates) == 0 {
-		return "", fmt.Errorf("no update data")
-	}
-
-	// Qdrant update payload request structure
-	type QdrantUpdateRequest struct {
-		Points  []interface{}          `json:"points,omitempty"` // Point ID list
-		Filter  *QdrantFilter          `json:"filter,omitempty"` // Or use filter
-		Payload map[string]interface{} `json:"payload"`          // Payload to update
-	}
-
-	// Extract payload
-	payload := make(map[string]interface{})
-	for _, bb := range updates {
-		payload[bb.Key] = bb.Value
-	}
-
-	req := QdrantUpdateRequest{
-		Payload: payload,
-	}
-
-	// Extract point IDs from conditions or build filter
-	ids, filter := c.extractIdsOrFilter(built.Conds)
-	if len(ids) > 0 {
-		req.Points = ids
-	} else if filter != nil {
-		req.Filter = filter
-	}
-
-	bytes, err := json.MarshalIndent(req, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal Qdrant update request: %w", err)
-	}
-
-	return string(bytes), nil
+	body, _, err := c.generateUpdate(built)
+	return body, err
 }
 
 // generateDeleteJSON generates Qdrant delete JSON
@@ -381,14 +452,17 @@ func (c *QdrantCustom) generateUpdateJSON(built *Built) (string, error) {
 func (c *QdrantCustom) generateDeleteJSON(built *Built) (string, error) {
 	// Qdrant delete request structure
 	type QdrantDeleteRequest struct {
-		Points []interface{} `json:"points,omitempty"` // Point ID list
+		Points []PointID     `json:"points,omitempty"` // Point ID list
 		Filter *QdrantFilter `json:"filter,omitempty"` // Or use filter
 	}
 
 	req := QdrantDeleteRequest{}
 
 	// Extract point IDs from conditions or build filter
-	ids, filter := c.extractIdsOrFilter(built.Conds)
+	ids, filter, err := c.extractIdsOrFilter(built.Conds)
+	if err != nil {
+		return "", err
+	}
 	if len(ids) > 0 {
 		req.Points = ids
 	} else if filter != nil {
@@ -416,10 +490,32 @@ func (c *QdrantCustom) extractPointFromBbs(bbs []Bb) (QdrantPoint, error) {
 		Payload: make(map[string]interface{}),
 	}
 
+	named := map[string]interface{}{}
 	for _, bb := range bbs {
+		if _, ok := bb.Value.(BinaryVector); ok {
+			return QdrantPoint{}, fmt.Errorf("binary vector %s: Qdrant stores float vectors, upsert Vector and use BinaryQuantization()", bb.Key)
+		}
+		// Sparse vectors are always named in Qdrant
+		if sv, ok := bb.Value.(SparseVector); ok && bb.Key != "id" && bb.Key != "vector" {
+			if err := sv.Validate(); err != nil {
+				return QdrantPoint{}, fmt.Errorf("sparse vector %s: %w", bb.Key, err)
+			}
+			named[bb.Key] = sv
+			continue
+		}
+		if c.NamedVectors && bb.Key != "id" {
+			if vec, ok := qdrantVectorValue(bb.Value); ok {
+				named[bb.Key] = vec
+				continue
+			}
+		}
 		switch bb.Key {
 		case "id":
-			point.ID = bb.Value
+			id, err := ParsePointID(bb.Value)
+			if err != nil {
+				return QdrantPoint{}, err
+			}
+			point.ID = id
 		case "vector":
 			point.Vector = bb.Value
 		default:
@@ -428,6 +524,13 @@ func (c *QdrantCustom) extractPointFromBbs(bbs []Bb) (QdrantPoint, error) {
 		}
 	}
 
+	if len(named) > 0 {
+		if point.Vector != nil {
+			named[""] = point.Vector // The default (unnamed) dense vector next to sparse ones
+		}
+		point.Vector = named
+	}
+
 	// Validate required fields
 	if point.ID == nil {
 		return QdrantPoint{}, fmt.Errorf("point must have 'id' field")
@@ -439,100 +542,102 @@ func (c *QdrantCustom) extractPointFromBbs(bbs []Bb) (QdrantPoint, error) {
 	return point, nil
 }
 
-// extractIdsOrFilter extracts point IDs from conditions or builds filter
-func (c *QdrantCustom) extractIdsOrFilter(conds []Bb) ([]interface{}, *QdrantFilter) {
-	ids := []interface{}{}
-
-	// Find id IN (...) condition
-	for _, bb := range conds {
-		if bb.Key == "id" {
-			if bb.Op == IN {
-				// IN condition: extract ID list
-				if arr, ok := bb.Value.(*[]string); ok {
-					for _, id := range *arr {
-						ids = append(ids, id)
-					}
-					return ids, nil
-				}
-			} else if bb.Op == EQ {
-				// Single ID
-				ids = append(ids, bb.Value)
-				return ids, nil
-			}
-		}
+// qdrantVectorValue a dense vector or a multivector of an insert value
+func qdrantVectorValue(v interface{}) (interface{}, bool) {
+	switch vec := v.(type) {
+	case Vector, []float32, []float64:
+		return vec, true
+	case HalfVector, Int8Vector: // Collection datatype float16 / uint8
+		return vec, true
+	case MultiVector, []Vector, [][]float32:
+		return vec, true
 	}
+	return nil, false
+}
 
-	// If no id condition, build filter
-	if len(conds) > 0 {
-		filter := &QdrantFilter{}
-		filter.Must = []QdrantCondition{}
-
-		for _, bb := range conds {
-			cond := QdrantCondition{
-				Key: bb.Key,
+// extractIdsOrFilter extracts point IDs from conditions or builds filter
+// The ids are returned only when the id condition is the only one,
+// with other conditions it becomes a has_id condition of the filter
+func (c *QdrantCustom) extractIdsOrFilter(conds []Bb) ([]PointID, *QdrantFilter, error) {
+	hasIDCond := false
+	rewritten := make([]Bb, 0, len(conds))
+	for _, bb := range conds {
+		if bb.Key == "id" && (bb.Op == IN || bb.Op == EQ) {
+			pointIDs, err := qdrantPointIDsOf(bb)
+			if err != nil {
+				return nil, nil, err
 			}
-
-			switch bb.Op {
-			case EQ:
-				cond.Match = &QdrantMatchCondition{Value: bb.Value}
-			case GT, GTE, LT, LTE:
-				cond.Range = &QdrantRangeCondition{}
-				switch bb.Op {
-				case GT:
-					cond.Range.Gt = toFloat64Ptr(bb.Value)
-				case GTE:
-					cond.Range.Gte = toFloat64Ptr(bb.Value)
-				case LT:
-					cond.Range.Lt = toFloat64Ptr(bb.Value)
-				case LTE:
-					cond.Range.Lte = toFloat64Ptr(bb.Value)
-				}
+			if len(pointIDs) > 0 {
+				hasIDCond = true
+				bb = Bb{Op: QDRANT_HAS_ID, Value: pointIDs}
 			}
-
-			filter.Must = append(filter.Must, cond)
 		}
-
-		return nil, filter
+		rewritten = append(rewritten, bb)
 	}
 
-	return nil, nil
+	filter, err := buildQdrantFilter(rewritten)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter = nonEmptyQdrantFilter(filter)
+	if hasIDCond && filter != nil && len(filter.Must) == 1 && filter.Must[0].HasId != nil &&
+		len(filter.Should) == 0 && len(filter.MustNot) == 0 {
+		return filter.Must[0].HasId, nil, nil
+	}
+	return nil, filter, nil
 }
 
-func toFloat64Ptr(v interface{}) *float64 {
-	switch val := v.(type) {
-	case float64:
-		return &val
-	case float32:
-		f := float64(val)
-		return &f
-	case int:
-		f := float64(val)
-		return &f
-	case int64:
-		f := float64(val)
-		return &f
+// qdrantPointIDsOf point ids of an id Eq / In condition
+func qdrantPointIDsOf(bb Bb) ([]PointID, error) {
+	if bb.Op == EQ {
+		id, err := ParsePointID(bb.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []PointID{id}, nil
+	}
+	values, err := qdrantInValues(bb)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]PointID, 0, len(values))
+	for _, v := range values {
+		id, err := ParsePointID(v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
-	return nil
+	return ids, nil
 }
 
 // qdrantRecommendConfig Recommend API configuration
 type qdrantRecommendConfig struct {
-	positive []int64
-	negative []int64
-	limit    int
+	positive   []QdrantExample
+	negative   []QdrantExample
+	limit      int
+	strategy   QdrantRecommendStrategy
+	using      string
+	lookupFrom *QdrantLookupFrom
 }
 
 // qdrantDiscoverConfig Discover API configuration
 type qdrantDiscoverConfig struct {
-	context []int64
-	limit   int
+	target     *QdrantExample
+	context    []QdrantContextPair
+	limit      int
+	using      string
+	lookupFrom *QdrantLookupFrom
 }
 
 // RecommendBuilder Recommend API builder
 type RecommendBuilder struct {
-	positive []int64
-	negative []int64
-	limit    int
+	positive   []QdrantExample
+	negative   []QdrantExample
+	limit      int
+	strategy   QdrantRecommendStrategy
+	using      string
+	lookupFrom *QdrantLookupFrom
 }
 
 // Positive sets positive sample IDs
@@ -540,7 +645,7 @@ func (rb *RecommendBuilder) Positive(ids ...int64) *RecommendBuilder {
 	if len(ids) == 0 {
 		return rb
 	}
-	rb.positive = append(rb.positive, ids...)
+	rb.positive = append(rb.positive, mustQdrantExamples("Positive()", int64Examples(ids))...)
 	return rb
 }
 
@@ -549,7 +654,76 @@ func (rb *RecommendBuilder) Negative(ids ...int64) *RecommendBuilder {
 	if len(ids) == 0 {
 		return rb
 	}
-	rb.negative = append(rb.negative, ids...)
+	rb.negative = append(rb.negative, mustQdrantExamples("Negative()", int64Examples(ids))...)
+	return rb
+}
+
+// PositiveVector adds raw vectors as positive examples
+func (rb *RecommendBuilder) PositiveVector(vectors ...Vector) *RecommendBuilder {
+	rb.positive = append(rb.positive, mustQdrantExamples("PositiveVector()", vectorExamples(vectors))...)
+	return rb
+}
+
+// NegativeVector adds raw vectors as negative examples
+func (rb *RecommendBuilder) NegativeVector(vectors ...Vector) *RecommendBuilder {
+	rb.negative = append(rb.negative, mustQdrantExamples("NegativeVector()", vectorExamples(vectors))...)
+	return rb
+}
+
+// PositiveExample adds positive examples of any kind: point ids (integer or UUID, see PointID) or raw vectors
+//
+// Example:
+//
+//	rb.PositiveExample("5c56c793-69f3-4fbf-87e6-c4bf54c28c26", uint64(7), []float32{0.1, 0.2})
+func (rb *RecommendBuilder) PositiveExample(examples ...interface{}) *RecommendBuilder {
+	rb.positive = append(rb.positive, mustQdrantExamples("PositiveExample()", examples)...)
+	return rb
+}
+
+// NegativeExample adds negative examples of any kind: point ids or raw vectors
+func (rb *RecommendBuilder) NegativeExample(examples ...interface{}) *RecommendBuilder {
+	rb.negative = append(rb.negative, mustQdrantExamples("NegativeExample()", examples)...)
+	return rb
+}
+
+func int64Examples(ids []int64) []interface{} {
+	examples := make([]interface{}, len(ids))
+	for i, id := range ids {
+		examples[i] = id
+	}
+	return examples
+}
+
+func vectorExamples(vectors []Vector) []interface{} {
+	examples := make([]interface{}, len(vectors))
+	for i, v := range vectors {
+		examples[i] = v
+	}
+	return examples
+}
+
+// Strategy sets how examples are combined
+// xb.QdrantAverageVector (default), xb.QdrantBestScore, xb.QdrantSumScores
+func (rb *RecommendBuilder) Strategy(strategy QdrantRecommendStrategy) *RecommendBuilder {
+	switch strategy {
+	case QdrantAverageVector, QdrantBestScore, QdrantSumScores:
+	default:
+		panic(fmt.Sprintf("Strategy() unknown recommend strategy: %q", strategy))
+	}
+	rb.strategy = strategy
+	return rb
+}
+
+// Using searches the named vector (collections with several vectors)
+func (rb *RecommendBuilder) Using(vectorName string) *RecommendBuilder {
+	rb.using = vectorName
+	return rb
+}
+
+// LookupFrom resolves id examples in another collection
+// vectorName: optional named vector of that collection
+func (rb *RecommendBuilder) LookupFrom(collection string, vectorName ...string) *RecommendBuilder {
+	rb.lookupFrom = lookupFrom("LookupFrom()", collection, vectorName)
 	return rb
 }
 
@@ -561,16 +735,53 @@ func (rb *RecommendBuilder) Limit(limit int) *RecommendBuilder {
 
 // DiscoverBuilder Discover API builder
 type DiscoverBuilder struct {
-	context []int64
-	limit   int
+	target     *QdrantExample
+	context    []QdrantContextPair
+	limit      int
+	using      string
+	lookupFrom *QdrantLookupFrom
 }
 
 // Context sets context IDs
+//
+// Deprecated: Qdrant takes context pairs, flat ids have no equivalent.
+// It panics, use Pair(positive, negative) and Target() instead.
 func (db *DiscoverBuilder) Context(ids ...int64) *DiscoverBuilder {
-	if len(ids) == 0 {
-		return db
-	}
-	db.context = append(db.context, ids...)
+	panic(fmt.Sprintf("Discover() Context(%v) is no longer supported, Qdrant takes context pairs: "+
+		"use Pair(positive, negative) for each pair and Target() for the target", ids))
+}
+
+// Target sets the target example: a point id or a raw vector
+func (db *DiscoverBuilder) Target(example interface{}) *DiscoverBuilder {
+	target := mustQdrantExamples("Target()", []interface{}{example})[0]
+	db.target = &target
+	return db
+}
+
+// Pair adds a context pair, results are steered towards positive and away from negative
+// positive, negative: point ids or raw vectors
+//
+// Example:
+//
+//	db.Pair(101, 102).Pair(xb.Vector{0.1, 0.2}, 103)
+func (db *DiscoverBuilder) Pair(positive, negative interface{}) *DiscoverBuilder {
+	examples := mustQdrantExamples("Pair()", []interface{}{positive, negative})
+	db.context = append(db.context, QdrantContextPair{
+		Positive: examples[0],
+		Negative: examples[1],
+	})
+	return db
+}
+
+// Using searches the named vector (collections with several vectors)
+func (db *DiscoverBuilder) Using(vectorName string) *DiscoverBuilder {
+	db.using = vectorName
+	return db
+}
+
+// LookupFrom resolves id examples in another collection
+func (db *DiscoverBuilder) LookupFrom(collection string, vectorName ...string) *DiscoverBuilder {
+	db.lookupFrom = lookupFrom("LookupFrom()", collection, vectorName)
 	return db
 }
 
@@ -580,6 +791,17 @@ func (db *DiscoverBuilder) Limit(limit int) *DiscoverBuilder {
 	return db
 }
 
+func lookupFrom(method string, collection string, vectorName []string) *QdrantLookupFrom {
+	if collection == "" {
+		panic(method + " requires a collection name")
+	}
+	lookup := &QdrantLookupFrom{Collection: collection}
+	if len(vectorName) > 0 {
+		lookup.Vector = vectorName[0]
+	}
+	return lookup
+}
+
 // ensureAdvancedConds injects advanced configuration into condition list
 func (c *QdrantCustom) ensureAdvancedConds(conds []Bb) []Bb {
 	if c == nil {
@@ -588,11 +810,22 @@ func (c *QdrantCustom) ensureAdvancedConds(conds []Bb) []Bb {
 
 	if c.recommendConfig != nil && !hasBbWithOp(conds, QDRANT_RECOMMEND) {
 		value := map[string]interface{}{
-			"positive": append([]int64(nil), c.recommendConfig.positive...),
-			"limit":    c.recommendConfig.limit,
+			"limit": c.recommendConfig.limit,
+		}
+		if len(c.recommendConfig.positive) > 0 {
+			value["positive"] = append([]QdrantExample(nil), c.recommendConfig.positive...)
 		}
 		if len(c.recommendConfig.negative) > 0 {
-			value["negative"] = append([]int64(nil), c.recommendConfig.negative...)
+			value["negative"] = append([]QdrantExample(nil), c.recommendConfig.negative...)
+		}
+		if c.recommendConfig.strategy != "" {
+			value["strategy"] = string(c.recommendConfig.strategy)
+		}
+		if c.recommendConfig.using != "" {
+			value["using"] = c.recommendConfig.using
+		}
+		if c.recommendConfig.lookupFrom != nil {
+			value["lookup_from"] = c.recommendConfig.lookupFrom
 		}
 		conds = append(conds, Bb{
 			Op:    QDRANT_RECOMMEND,
@@ -602,15 +835,53 @@ func (c *QdrantCustom) ensureAdvancedConds(conds []Bb) []Bb {
 
 	if c.discoverConfig != nil && !hasBbWithOp(conds, QDRANT_DISCOVER) {
 		value := map[string]interface{}{
-			"context": append([]int64(nil), c.discoverConfig.context...),
+			"context": append([]QdrantContextPair(nil), c.discoverConfig.context...),
 			"limit":   c.discoverConfig.limit,
 		}
+		if c.discoverConfig.target != nil {
+			value["target"] = c.discoverConfig.target
+		}
+		if c.discoverConfig.using != "" {
+			value["using"] = c.discoverConfig.using
+		}
+		if c.discoverConfig.lookupFrom != nil {
+			value["lookup_from"] = c.discoverConfig.lookupFrom
+		}
 		conds = append(conds, Bb{
 			Op:    QDRANT_DISCOVER,
 			Value: value,
 		})
 	}
 
+	if c.queryConfig != nil && !hasBbWithOp(conds, QDRANT_QUERY) {
+		conds = append(conds, Bb{
+			Op:    QDRANT_QUERY,
+			Value: c.queryConfig,
+		})
+	}
+
+	if len(c.batch) > 0 && !hasBbWithOp(conds, QDRANT_BATCH_SEARCH) {
+		conds = append(conds, Bb{
+			Op:    QDRANT_BATCH_SEARCH,
+			Value: c.batch,
+		})
+	}
+
+	if c.facet != nil && !hasBbWithOp(conds, QDRANT_FACET) {
+		conds = append(conds, Bb{
+			Op:    QDRANT_FACET,
+			Key:   c.facet.key,
+			Value: c.facet.limit,
+		})
+	}
+
+	if c.scroll && !hasBbWithOp(conds, QDRANT_SCROLL) {
+		conds = append(conds, Bb{
+			Op:    QDRANT_SCROLL,
+			Value: c.scrollOffset,
+		})
+	}
+
 	if c.scrollID != "" && !hasBbWithOp(conds, QDRANT_SCROLL) {
 		conds = append(conds, Bb{
 			Op:    QDRANT_SCROLL,
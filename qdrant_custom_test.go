@@ -19,6 +19,8 @@ package xb
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -261,11 +263,24 @@ func TestJsonOfSelect_WithRecommendConfig(t *testing.T) {
 }
 
 func TestJsonOfSelect_WithDiscoverConfig(t *testing.T) {
+	// Context() ids have no Qdrant equivalent, the builder points to Pair() / Target()
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), "Pair(positive, negative)") {
+			t.Fatalf("Context() should panic with a migration message, got %v", r)
+		}
+	}()
+	NewQdrantBuilder().Discover(func(db *DiscoverBuilder) {
+		db.Context(101, 102, 103).Limit(8)
+	})
+}
+
+func TestJsonOfSelect_WithDiscoverTargetAndPairs(t *testing.T) {
 	queryVector := Vector{0.1, 0.2, 0.3}
 
 	jsonStr, err := Of(&CodeVector{}).
 		Custom(NewQdrantBuilder().Discover(func(db *DiscoverBuilder) {
-			db.Context(101, 102, 103).Limit(8)
+			db.Target(100).Pair(101, 102).Pair(103, 104).Limit(8)
 		}).Build()).
 		VectorSearch("embedding", queryVector, 8).
 		Build().
@@ -283,7 +298,10 @@ func TestJsonOfSelect_WithDiscoverConfig(t *testing.T) {
 		t.Fatalf("discover limit want 8, got %v", limit)
 	}
 	if _, ok := payload["context"]; !ok {
-		t.Fatalf("discover payload missing context ids")
+		t.Fatalf("discover payload missing context pairs")
+	}
+	if target := payload["target"]; target != float64(100) {
+		t.Fatalf("discover target want 100, got %v", target)
 	}
 }
 
@@ -308,3 +326,113 @@ func TestJsonOfSelect_WithScrollConfig(t *testing.T) {
 		t.Fatalf("scroll_id want %s, got %v", scrollID, got)
 	}
 }
+
+func TestJsonOfSelect_RecommendStrategyAndVectors(t *testing.T) {
+	jsonStr, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) {
+			ids := []int64{11}
+			rb.Positive(ids...).
+				PositiveVector(Vector{0.1, 0.2}).
+				NegativeExample([]float64{0.5, 0.5}).
+				Strategy(QdrantBestScore).
+				Using("code").
+				LookupFrom("snippets", "body").
+				Limit(5)
+		}).Build()).
+		Eq("language", "golang").
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{
+		"positive": [11, [0.1, 0.2]],
+		"negative": [[0.5, 0.5]],
+		"strategy": "best_score",
+		"using": "code",
+		"lookup_from": {"collection": "snippets", "vector": "body"},
+		"limit": 5,
+		"filter": {"must": [{"key": "language", "match": {"value": "golang"}}]},
+		"with_payload": true
+	}`)
+
+	// best_score / sum_scores accept negative examples only
+	negativeOnly, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) {
+			rb.Negative(7).Strategy(QdrantSumScores).Limit(3)
+		}).Build()).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, negativeOnly, `{
+		"negative": [7],
+		"strategy": "sum_scores",
+		"limit": 3,
+		"with_payload": true
+	}`)
+}
+
+func TestJsonOfSelect_DiscoverContextSearch(t *testing.T) {
+	jsonStr, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Discover(func(db *DiscoverBuilder) {
+			db.Pair(Vector{0.1, 0.2}, 3).Using("code").LookupFrom("snippets").Limit(10)
+		}).Build()).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{
+		"context": [{"positive": [0.1, 0.2], "negative": 3}],
+		"using": "code",
+		"lookup_from": {"collection": "snippets"},
+		"limit": 10,
+		"with_payload": true
+	}`)
+
+	var req QdrantDiscoverRequest
+	if err := json.Unmarshal([]byte(jsonStr), &req); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !req.Context[0].Positive.IsVector() || req.Context[0].Negative.ID != NumPointID(3) {
+		t.Errorf("unexpected context pair: %+v", req.Context[0])
+	}
+}
+
+func TestRecommendDiscover_Validation(t *testing.T) {
+	cases := map[string]func(){
+		"negative only, average": func() {
+			NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) { rb.Negative(1).Limit(1) })
+		},
+		"unknown strategy": func() {
+			NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) { rb.Strategy("max") })
+		},
+		"empty vector": func() {
+			NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) { rb.PositiveVector(Vector{}) })
+		},
+		"unknown example": func() {
+			NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) { rb.NegativeExample(struct{}{}) })
+		},
+		"lookup collection": func() {
+			NewQdrantBuilder().Recommend(func(rb *RecommendBuilder) { rb.LookupFrom("") })
+		},
+		"no pair": func() {
+			NewQdrantBuilder().Discover(func(db *DiscoverBuilder) { db.Target(1).Limit(1) })
+		},
+		"pair nil": func() {
+			NewQdrantBuilder().Discover(func(db *DiscoverBuilder) { db.Pair(1, nil) })
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
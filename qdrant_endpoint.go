@@ -0,0 +1,160 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ============================================================================
+// Qdrant REST endpoints
+// ============================================================================
+
+// QdrantEndpoint HTTP method and path of a Qdrant request
+// Path is relative to the collection, see URL()
+type QdrantEndpoint struct {
+	Method string // http.MethodPost, http.MethodPut, ...
+	Path   string // e.g. "/points/payload"
+}
+
+// URL full path of the endpoint for collection
+//
+// Example:
+//
+//	ep.URL("code_vectors") // "/collections/code_vectors/points/payload"
+func (e QdrantEndpoint) URL(collection string) string {
+	return "/collections/" + url.PathEscape(collection) + e.Path
+}
+
+// String "POST /points/payload"
+func (e QdrantEndpoint) String() string {
+	return e.Method + " " + e.Path
+}
+
+var (
+	qdrantUpsertEndpoint = QdrantEndpoint{Method: http.MethodPut, Path: "/points"}
+	qdrantDeleteEndpoint = QdrantEndpoint{Method: http.MethodPost, Path: "/points/delete"}
+
+	// qdrantSelectEndpoints endpoint per qdrantSelectOp()
+	qdrantSelectEndpoints = map[string]QdrantEndpoint{
+		"":                  {Method: http.MethodPost, Path: "/points/search"},
+		qdrantSearchGroups:  {Method: http.MethodPost, Path: "/points/search/groups"},
+		QDRANT_BATCH_SEARCH: {Method: http.MethodPost, Path: "/points/search/batch"},
+		QDRANT_RECOMMEND:    {Method: http.MethodPost, Path: "/points/recommend"},
+		QDRANT_DISCOVER:     {Method: http.MethodPost, Path: "/points/discover"},
+		QDRANT_SCROLL:       {Method: http.MethodPost, Path: "/points/scroll"},
+		QDRANT_QUERY:        {Method: http.MethodPost, Path: "/points/query"},
+		HYBRID_SEARCH:       {Method: http.MethodPost, Path: "/points/query"},
+		QDRANT_FACET:        {Method: http.MethodPost, Path: "/facet"},
+	}
+)
+
+// QdrantEndpoint endpoint of the JSON this Built generates
+// Insert => PUT /points, Update => see JsonOfQdrantUpdate(), Delete => POST /points/delete,
+// otherwise the API JsonOfSelect() routes to
+// Delete is detected by built.Delete, which JsonOfDelete() sets
+//
+// Example:
+//
+//	body, _ := built.JsonOfSelect()
+//	ep, _ := built.QdrantEndpoint()
+//	req, _ := http.NewRequest(ep.Method, baseURL+ep.URL("code_vectors"), strings.NewReader(body))
+func (built *Built) QdrantEndpoint() (QdrantEndpoint, error) {
+	custom, ok := built.Custom.(*QdrantCustom)
+	if !ok {
+		return QdrantEndpoint{}, fmt.Errorf("QdrantEndpoint() requires Qdrant Custom, got: %T", built.Custom)
+	}
+	built = custom.applyAdvancedConfig(built)
+
+	switch {
+	case built.Inserts != nil && len(*built.Inserts) > 0:
+		return qdrantUpsertEndpoint, nil
+	case built.Updates != nil && len(*built.Updates) > 0:
+		_, ep, err := custom.generateUpdate(built)
+		return ep, err
+	case built.Delete:
+		return qdrantDeleteEndpoint, nil
+	}
+	return qdrantSelectEndpoints[qdrantSelectOp(built)], nil
+}
+
+// Endpoint POST /points/search
+func (r *QdrantSearchRequest) Endpoint() QdrantEndpoint {
+	return qdrantSelectEndpoints[""]
+}
+
+// Endpoint POST /points/search/groups
+func (r *QdrantSearchGroupsRequest) Endpoint() QdrantEndpoint {
+	return qdrantSelectEndpoints[qdrantSearchGroups]
+}
+
+// Endpoint POST /points/search/batch
+func (r *QdrantBatchSearchRequest) Endpoint() QdrantEndpoint {
+	return qdrantSelectEndpoints[QDRANT_BATCH_SEARCH]
+}
+
+// Endpoint POST /points/recommend
+func (r *QdrantRecommendRequest) Endpoint() QdrantEndpoint {
+	return qdrantSelectEndpoints[QDRANT_RECOMMEND]
+}
+
+// Endpoint POST /points/discover
+func (r *QdrantDiscoverRequest) Endpoint() QdrantEndpoint {
+	return qdrantSelectEndpoints[QDRANT_DISCOVER]
+}
+
+// Endpoint POST /points/scroll
+func (r *QdrantScrollRequest) Endpoint() QdrantEndpoint {
+	return qdrantSelectEndpoints[QDRANT_SCROLL]
+}
+
+// Endpoint POST /points/query
+func (r *QdrantQueryRequest) Endpoint() QdrantEndpoint {
+	return qdrantSelectEndpoints[QDRANT_QUERY]
+}
+
+// Endpoint POST /facet
+func (r *QdrantFacetRequest) Endpoint() QdrantEndpoint {
+	return qdrantSelectEndpoints[QDRANT_FACET]
+}
+
+// Endpoint POST /points/payload/delete
+func (r *QdrantDeletePayloadRequest) Endpoint() QdrantEndpoint {
+	return QdrantEndpoint{Method: http.MethodPost, Path: "/points/payload/delete"}
+}
+
+// Endpoint PUT /points/vectors
+func (r *QdrantUpdateVectorsRequest) Endpoint() QdrantEndpoint {
+	return QdrantEndpoint{Method: http.MethodPut, Path: "/points/vectors"}
+}
+
+// Endpoint POST /points/vectors/delete
+func (r *QdrantDeleteVectorsRequest) Endpoint() QdrantEndpoint {
+	return QdrantEndpoint{Method: http.MethodPost, Path: "/points/vectors/delete"}
+}
+
+// Endpoint PUT /collections/{name}, Path is ""
+func (r *QdrantCreateCollectionRequest) Endpoint() QdrantEndpoint {
+	return QdrantEndpoint{Method: http.MethodPut}
+}
+
+// Endpoint PUT /index
+func (r *QdrantPayloadIndexRequest) Endpoint() QdrantEndpoint {
+	return QdrantEndpoint{Method: http.MethodPut, Path: "/index"}
+}
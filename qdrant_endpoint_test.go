@@ -0,0 +1,88 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBuilt_QdrantEndpoint(t *testing.T) {
+	custom := func() *QdrantCustom { return NewQdrantBuilder().Build() }
+	cases := map[string]struct {
+		built *Built
+		want  string
+	}{
+		"search": {Of(&CodeVectorForQdrant{}).Custom(custom()).
+			VectorSearch("embedding", Vector{0.1}, 5).Build(), "POST /points/search"},
+		"groups": {Of(&CodeVectorForQdrant{}).Custom(custom()).GroupBy("doc_id").
+			VectorSearch("embedding", Vector{0.1}, 5).Build(), "POST /points/search/groups"},
+		"recommend": {Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().
+			Recommend(func(rb *RecommendBuilder) { rb.Positive(1).Limit(5) }).Build()).Build(), "POST /points/recommend"},
+		"discover": {Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().
+			Discover(func(db *DiscoverBuilder) { db.Pair(1, 2).Limit(5) }).Build()).Build(), "POST /points/discover"},
+		"scroll": {Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().ScrollID("7").Build()).Build(), "POST /points/scroll"},
+		"facet":  {Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Facet("language", 5).Build()).Build(), "POST /facet"},
+		"insert": {Of(&CodeVectorForQdrant{}).Custom(custom()).
+			Insert(func(ib *InsertBuilder) { ib.Set("id", 1).Set("vector", []float32{0.1}) }).Build(), "PUT /points"},
+		"update": {Of(&CodeVectorForQdrant{}).Custom(custom()).Eq("id", 1).
+			Update(func(ub *UpdateBuilder) { ub.ClearPayload() }).Build(), "POST /points/payload/clear"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ep, err := c.built.QdrantEndpoint()
+			if err != nil {
+				t.Fatalf("QdrantEndpoint failed: %v", err)
+			}
+			if ep.String() != c.want {
+				t.Errorf("want %s, got %s", c.want, ep)
+			}
+		})
+	}
+
+	del := Of(&CodeVectorForQdrant{}).Custom(custom()).Eq("id", 1).Build()
+	if _, err := del.JsonOfDelete(); err != nil {
+		t.Fatalf("JsonOfDelete failed: %v", err)
+	}
+	if ep, _ := del.QdrantEndpoint(); ep.String() != "POST /points/delete" {
+		t.Errorf("unexpected delete endpoint: %s", ep)
+	}
+
+	if _, err := Of(&CodeVectorForQdrant{}).Build().QdrantEndpoint(); err == nil {
+		t.Error("expected error without Qdrant Custom")
+	}
+}
+
+func TestQdrantRequest_Endpoint(t *testing.T) {
+	req, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		VectorSearch("embedding", Vector{0.1}, 5).
+		Build().
+		ToQdrantRequest()
+	if err != nil {
+		t.Fatalf("ToQdrantRequest failed: %v", err)
+	}
+	ep := req.Endpoint()
+	if ep.Method != http.MethodPost || ep.URL("code_vectors") != "/collections/code_vectors/points/search" {
+		t.Errorf("unexpected endpoint: %s", ep)
+	}
+	if (&QdrantRecommendRequest{}).Endpoint().Path != "/points/recommend" ||
+		(&QdrantPayloadIndexRequest{}).Endpoint().String() != "PUT /index" ||
+		(&QdrantCreateCollectionRequest{}).Endpoint().URL("c") != "/collections/c" {
+		t.Error("unexpected request endpoints")
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Qdrant filter evaluation
+// ============================================================================
+//
+// Matches evaluates a QdrantFilter against one point the way Qdrant does,
+// for in-memory backends and fakes. No payload index is needed:
+//   - keys are paths: "city.name", "tags[]", "diet[].food"; arrays match if any element matches
+//   - match.text is a case-insensitive match of every word of the text
+//   - datetime ranges compare RFC3339 / "2006-01-02T15:04:05" (UTC) values
+
+// Matches reports whether the point (id, payload) passes the filter
+// A nil or empty filter matches every point
+//
+// Example:
+//
+//	req, _ := built.ToQdrantRequest()
+//	if req.Filter.Matches(xb.NumPointID(1), map[string]interface{}{"language": "go"}) { ... }
+func (f *QdrantFilter) Matches(id PointID, payload map[string]interface{}) bool {
+	if f == nil {
+		return true
+	}
+	for i := range f.Must {
+		if !f.Must[i].Matches(id, payload) {
+			return false
+		}
+	}
+	for i := range f.MustNot {
+		if f.MustNot[i].Matches(id, payload) {
+			return false
+		}
+	}
+	if len(f.Should) == 0 {
+		return true
+	}
+	for i := range f.Should {
+		if f.Should[i].Matches(id, payload) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the point (id, payload) passes the condition
+func (c *QdrantCondition) Matches(id PointID, payload map[string]interface{}) bool {
+	switch {
+	case c.QdrantFilter != nil:
+		return c.QdrantFilter.Matches(id, payload)
+	case c.HasId != nil:
+		for _, hasID := range c.HasId {
+			if hasID == id {
+				return true
+			}
+		}
+		return false
+	case c.IsEmpty != nil:
+		return len(payloadValues(payload, c.IsEmpty.Key)) == 0
+	case c.IsNull != nil:
+		return payloadIsNull(payload, c.IsNull.Key)
+	case c.Nested != nil:
+		for _, elem := range payloadValues(payload, c.Nested.Key) {
+			if m, ok := payloadMap(elem); ok && c.Nested.Filter.Matches(id, m) {
+				return true
+			}
+		}
+		return false
+	}
+
+	values := payloadValues(payload, c.Key)
+	switch {
+	case c.Match != nil:
+		return c.Match.match(values)
+	case c.Range != nil:
+		return anyValue(values, c.Range.match)
+	case c.GeoRadius != nil:
+		return anyValue(values, func(v interface{}) bool {
+			p, ok := geoPointOf(v)
+			return ok && haversineMeters(p, c.GeoRadius.Center) <= c.GeoRadius.Radius
+		})
+	case c.GeoBoundingBox != nil:
+		return anyValue(values, func(v interface{}) bool {
+			p, ok := geoPointOf(v)
+			box := c.GeoBoundingBox
+			return ok && p.Lat <= box.TopLeft.Lat && p.Lat >= box.BottomRight.Lat &&
+				p.Lon >= box.TopLeft.Lon && p.Lon <= box.BottomRight.Lon
+		})
+	case c.GeoPolygon != nil:
+		return anyValue(values, func(v interface{}) bool {
+			p, ok := geoPointOf(v)
+			if !ok || !inRing(p, c.GeoPolygon.Exterior.Points) {
+				return false
+			}
+			for _, hole := range c.GeoPolygon.Interiors {
+				if inRing(p, hole.Points) {
+					return false
+				}
+			}
+			return true
+		})
+	case c.ValuesCount != nil:
+		return c.ValuesCount.match(len(values))
+	}
+	return false
+}
+
+func (m *QdrantMatchCondition) match(values []interface{}) bool {
+	switch {
+	case m.Text != "":
+		words := strings.Fields(strings.ToLower(m.Text))
+		return anyValue(values, func(v interface{}) bool {
+			s, ok := v.(string)
+			if !ok {
+				return false
+			}
+			s = strings.ToLower(s)
+			for _, w := range words {
+				if !strings.Contains(s, w) {
+					return false
+				}
+			}
+			return true
+		})
+	case m.Any != nil:
+		return anyValue(values, func(v interface{}) bool { return containsValue(m.Any, v) })
+	case m.Except != nil:
+		return anyValue(values, func(v interface{}) bool { return !containsValue(m.Except, v) })
+	}
+	return anyValue(values, func(v interface{}) bool { return payloadEqual(v, m.Value) })
+}
+
+func (r *QdrantRangeCondition) match(v interface{}) bool {
+	if r.IsDatetime() {
+		t, ok := payloadTime(v)
+		if !ok {
+			return false
+		}
+		bounds := []struct {
+			s  string
+			ok func(c int) bool
+		}{
+			{r.GtTime, func(c int) bool { return c > 0 }},
+			{r.GteTime, func(c int) bool { return c >= 0 }},
+			{r.LtTime, func(c int) bool { return c < 0 }},
+			{r.LteTime, func(c int) bool { return c <= 0 }},
+		}
+		for _, b := range bounds {
+			if b.s == "" {
+				continue
+			}
+			bound, ok := payloadTime(b.s)
+			if !ok || !b.ok(t.Compare(bound)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	f, ok := payloadNumber(v)
+	if !ok {
+		return false
+	}
+	return (r.Gt == nil || f > *r.Gt) && (r.Gte == nil || f >= *r.Gte) &&
+		(r.Lt == nil || f < *r.Lt) && (r.Lte == nil || f <= *r.Lte)
+}
+
+func (vc *QdrantValuesCount) match(n int) bool {
+	return (vc.Gt == nil || n > *vc.Gt) && (vc.Gte == nil || n >= *vc.Gte) &&
+		(vc.Lt == nil || n < *vc.Lt) && (vc.Lte == nil || n <= *vc.Lte)
+}
+
+// PayloadValues values of a payload key ("a.b", "a[].b") with the rules of QdrantFilter.Matches:
+// arrays are flattened, null is no value, structs and typed maps are read by json tags
+// Used by fakes and tools that must agree with the filter matching
+func PayloadValues(payload map[string]interface{}, key string) []interface{} {
+	return payloadValues(payload, key)
+}
+
+// payloadValues values at key path, arrays are flattened, null is no value
+func payloadValues(payload map[string]interface{}, key string) []interface{} {
+	current := []interface{}{payload}
+	for _, part := range strings.Split(key, ".") {
+		part = strings.TrimSuffix(part, "[]")
+		var next []interface{}
+		for _, v := range current {
+			m, ok := payloadMap(v)
+			if !ok {
+				continue
+			}
+			if child, ok := m[part]; ok {
+				next = appendFlattened(next, child)
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// payloadIsNull the field exists with null value
+func payloadIsNull(payload map[string]interface{}, key string) bool {
+	i := strings.LastIndex(key, ".")
+	parents := []interface{}{payload}
+	if i >= 0 {
+		parents = payloadValues(payload, key[:i])
+	}
+	field := strings.TrimSuffix(key[i+1:], "[]")
+	for _, p := range parents {
+		if m, ok := payloadMap(p); ok {
+			if v, exists := m[field]; exists && isNilValue(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func appendFlattened(values []interface{}, v interface{}) []interface{} {
+	if isNilValue(v) {
+		return values
+	}
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < rv.Len(); i++ {
+			values = appendFlattened(values, rv.Index(i).Interface())
+		}
+		return values
+	}
+	return append(values, v)
+}
+
+func isNilValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// payloadMap object value as map, maps with string keys and structs (json tags)
+func payloadMap(v interface{}) (map[string]interface{}, bool) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		m := make(map[string]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			m[k.String()] = rv.MapIndex(k).Interface()
+		}
+		return m, true
+	}
+	if rv.Kind() == reflect.Struct || (rv.Kind() == reflect.Ptr && rv.Elem().Kind() == reflect.Struct) {
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		var m map[string]interface{}
+		if json.Unmarshal(bytes, &m) != nil {
+			return nil, false
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+func anyValue(values []interface{}, fn func(v interface{}) bool) bool {
+	for _, v := range values {
+		if fn(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(set []interface{}, v interface{}) bool {
+	for _, s := range set {
+		if payloadEqual(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// payloadEqual numbers by value (int64(1) == float64(1)), others by ==
+func payloadEqual(a, b interface{}) bool {
+	if fa, ok := payloadNumber(a); ok {
+		fb, ok := payloadNumber(b)
+		return ok && fa == fb
+	}
+	if _, ok := payloadNumber(b); ok {
+		return false
+	}
+	if a == nil || b == nil || !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+func payloadNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		f, err := toFloat64(n)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func payloadTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func geoPointOf(v interface{}) (QdrantGeoPoint, bool) {
+	if p, ok := v.(QdrantGeoPoint); ok {
+		return p, true
+	}
+	m, ok := payloadMap(v)
+	if !ok {
+		return QdrantGeoPoint{}, false
+	}
+	lon, okLon := payloadNumber(m["lon"])
+	lat, okLat := payloadNumber(m["lat"])
+	return QdrantGeoPoint{Lon: lon, Lat: lat}, okLon && okLat
+}
+
+// haversineMeters great-circle distance on the earth
+func haversineMeters(a, b QdrantGeoPoint) float64 {
+	const earthRadius = 6371008.8
+	rad := math.Pi / 180
+	dLat := (b.Lat - a.Lat) * rad
+	dLon := (b.Lon - a.Lon) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// inRing ray casting, lon as x and lat as y
+func inRing(p QdrantGeoPoint, ring []QdrantGeoPoint) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// matchFilter builds the filter, sends it through JSON like a server would, and evaluates it
+func matchFilter(t *testing.T, fn func(x *BuilderX), id PointID, payloadJSON string) bool {
+	t.Helper()
+	var filter *QdrantFilter
+	if raw := qdrantFilterJSON(t, fn); raw != "" {
+		filter = &QdrantFilter{}
+		if err := json.Unmarshal([]byte(raw), filter); err != nil {
+			t.Fatalf("Invalid filter JSON %s: %v", raw, err)
+		}
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(payloadJSON), &payload); err != nil {
+		t.Fatalf("Invalid payload JSON: %v", err)
+	}
+	return filter.Matches(id, payload)
+}
+
+func TestQdrantFilter_Matches(t *testing.T) {
+	doc := `{
+		"language": "golang", "stars": 120, "tags": ["db", "sql"],
+		"content": "A fluent SQL Builder for Go",
+		"created_at": "2025-03-01T10:00:00Z",
+		"location": {"lon": 13.40, "lat": 52.52},
+		"reviewer": null,
+		"diet": [{"food": "meat", "likes": false}, {"food": "fish", "likes": true}]
+	}`
+	cases := []struct {
+		name string
+		fn   func(x *BuilderX)
+		want bool
+	}{
+		{"no conditions", func(x *BuilderX) {}, true},
+		{"eq", func(x *BuilderX) { x.Eq("language", "golang") }, true},
+		{"eq miss", func(x *BuilderX) { x.Eq("language", "java") }, false},
+		{"ne", func(x *BuilderX) { x.Ne("language", "java") }, true},
+		{"in array", func(x *BuilderX) { x.In("tags", "sql", "nosql") }, true},
+		{"nin array", func(x *BuilderX) { x.Nin("tags", "db") }, false},
+		{"range", func(x *BuilderX) { x.Gte("stars", 100).Lt("stars", 200) }, true},
+		{"range miss", func(x *BuilderX) { x.Gt("stars", 120) }, false},
+		{"text", func(x *BuilderX) { x.Like("content", "sql builder") }, true},
+		{"or", func(x *BuilderX) { x.Eq("language", "java").OR().Gt("stars", 100) }, true},
+		{"or miss", func(x *BuilderX) { x.Eq("language", "java").OR().Gt("stars", 500) }, false},
+		{"except", func(x *BuilderX) { x.MatchExcept("tags", "db") }, true},
+		{"except all", func(x *BuilderX) { x.MatchExcept("tags", "db", "sql") }, false},
+		{"datetime", func(x *BuilderX) {
+			x.Datetime("created_at", Gte, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		}, true},
+		{"datetime miss", func(x *BuilderX) {
+			x.Datetime("created_at", Gt, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+		}, false},
+		{"geo radius", func(x *BuilderX) { x.GeoRadius("location", 52.52, 13.41, 1000) }, true},
+		{"geo radius miss", func(x *BuilderX) { x.GeoRadius("location", 48.85, 2.35, 1000) }, false},
+		{"geo box", func(x *BuilderX) {
+			x.GeoBoundingBox("location", QdrantGeoPoint{Lat: 53, Lon: 13}, QdrantGeoPoint{Lat: 52, Lon: 14})
+		}, true},
+		{"geo polygon hole", func(x *BuilderX) {
+			x.GeoPolygon("location",
+				[]QdrantGeoPoint{{Lat: 52, Lon: 13}, {Lat: 53, Lon: 13}, {Lat: 53, Lon: 14}, {Lat: 52, Lon: 14}},
+				[]QdrantGeoPoint{{Lat: 52.5, Lon: 13.3}, {Lat: 52.6, Lon: 13.3}, {Lat: 52.6, Lon: 13.5}, {Lat: 52.5, Lon: 13.5}})
+		}, false},
+		{"values count", func(x *BuilderX) { x.ValuesCount("tags", Eq, 2) }, true},
+		{"is empty", func(x *BuilderX) { x.IsEmpty("missing") }, true},
+		{"is null", func(x *BuilderX) { x.IsNullValue("reviewer") }, true},
+		{"is null missing", func(x *BuilderX) { x.IsNullValue("missing") }, false},
+		{"has id", func(x *BuilderX) { x.HasId(1, 7) }, true},
+		{"nested", func(x *BuilderX) {
+			x.Nested("diet", func(cb *CondBuilder) { cb.Eq("food", "fish").Eq("likes", true) })
+		}, true},
+		{"nested same element", func(x *BuilderX) {
+			x.Nested("diet", func(cb *CondBuilder) { cb.Eq("food", "meat").Eq("likes", true) })
+		}, false},
+		{"sub builder", func(x *BuilderX) {
+			x.Eq("language", "golang").Or(func(cb *CondBuilder) {
+				cb.Eq("stars", 1).OR().In("tags", "db")
+			})
+		}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := matchFilter(t, c.fn, NumPointID(7), doc); got != c.want {
+				t.Errorf("want %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestQdrantFilter_MatchesGoPayload(t *testing.T) {
+	filter := &QdrantFilter{Must: []QdrantCondition{
+		{Key: "tags", Match: &QdrantMatchCondition{Value: "db"}},
+		{Key: "meta.stars", Range: &QdrantRangeCondition{Gte: func() *float64 { f := 10.0; return &f }()}},
+	}}
+	payload := map[string]interface{}{
+		"tags": []string{"db"},
+		"meta": map[string]int{"stars": 12},
+	}
+	if !filter.Matches(NumPointID(1), payload) {
+		t.Error("expected Go typed payload to match")
+	}
+	if (*QdrantFilter)(nil).Matches(NumPointID(1), nil) != true {
+		t.Error("nil filter must match")
+	}
+}
+
+func TestPayloadValues(t *testing.T) {
+	payload := map[string]interface{}{
+		"tags":    []string{"db", "go"},
+		"meta":    map[string]int{"stars": 12},
+		"authors": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": nil}},
+	}
+	if got := PayloadValues(payload, "tags"); len(got) != 2 || got[1] != "go" {
+		t.Errorf("tags = %v", got)
+	}
+	if got := PayloadValues(payload, "meta.stars"); len(got) != 1 || got[0] != 12 {
+		t.Errorf("meta.stars = %v", got)
+	}
+	if got := PayloadValues(payload, "authors[].name"); len(got) != 1 || got[0] != "a" {
+		t.Errorf("authors[].name = %v", got)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ============================================================================
+// Search groups: POST /collections/{name}/points/search/groups
+// ============================================================================
+
+// QdrantSearchGroupsRequest search request grouped by a payload field
+// limit is the number of groups, group_size the points per group
+// Documentation: https://qdrant.tech/documentation/concepts/search/#search-groups
+//
+// Generated by GroupBy() + VectorSearch() with a QdrantCustom:
+//
+//	xb.Of(&Chunk{}).
+//	    Custom(xb.NewQdrantBuilder().Build()).
+//	    GroupBy("doc_id").
+//	    VectorSearch("embedding", vec, 5). // 5 documents
+//	    QdrantX(func(qx *xb.QdrantXBuilder) {
+//	        qx.GroupSize(2).                  // at most 2 chunks each
+//	            WithLookup("documents", "title", "url")
+//	    }).
+//	    Build().
+//	    JsonOfSelect()
+type QdrantSearchGroupsRequest struct {
+	*QdrantSearchRequest
+	GroupBy    string            `json:"group_by"`
+	GroupSize  int               `json:"group_size,omitempty"`
+	WithLookup *QdrantWithLookup `json:"with_lookup,omitempty"`
+}
+
+// MarshalJSON merges the search fields with the group fields
+// (the embedded QdrantSearchRequest has its own MarshalJSON, which would hide them)
+func (r QdrantSearchGroupsRequest) MarshalJSON() ([]byte, error) {
+	fields := map[string]json.RawMessage{}
+	if r.QdrantSearchRequest != nil {
+		search, err := json.Marshal(r.QdrantSearchRequest)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(search, &fields); err != nil {
+			return nil, err
+		}
+	}
+	groups, err := json.Marshal(struct {
+		GroupBy    string            `json:"group_by"`
+		GroupSize  int               `json:"group_size,omitempty"`
+		WithLookup *QdrantWithLookup `json:"with_lookup,omitempty"`
+	}{r.GroupBy, r.GroupSize, r.WithLookup})
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(groups, &fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// QdrantWithLookup looks up the group id in another collection
+type QdrantWithLookup struct {
+	Collection  string      `json:"collection"`
+	WithPayload interface{} `json:"with_payload,omitempty"` // true or []string
+	WithVectors bool        `json:"with_vectors,omitempty"`
+}
+
+// toQdrantGroupsJSON internal implementation
+func (built *Built) toQdrantGroupsJSON() (string, error) {
+	built = ensureQdrantAdvanced(built)
+	if len(built.GroupBys) != 1 {
+		return "", fmt.Errorf("Qdrant search groups supports exactly one GroupBy(), got: %v", built.GroupBys)
+	}
+
+	search, err := built.ToQdrantRequest()
+	if err != nil {
+		return "", err
+	}
+	if search.Offset > 0 {
+		return "", fmt.Errorf("Qdrant search groups does not support offset, page through groups with a filter instead")
+	}
+
+	req := &QdrantSearchGroupsRequest{
+		QdrantSearchRequest: search,
+		GroupBy:             built.GroupBys[0],
+	}
+	for _, bb := range built.Conds {
+		switch bb.Op {
+		case QDRANT_GROUP_SIZE:
+			req.GroupSize = bb.Value.(int)
+		case QDRANT_WITH_LOOKUP:
+			req.WithLookup = bb.Value.(*QdrantWithLookup)
+		}
+	}
+
+	return mergeAndSerialize(req, built.Conds)
+}
+
+// ============================================================================
+// Facet: POST /collections/{name}/facet
+// ============================================================================
+
+// QdrantFacetRequest counts points per value of a payload field
+// Requires a keyword/integer/uuid payload index on the key
+// Documentation: https://qdrant.tech/documentation/concepts/payload/#facet-counts
+type QdrantFacetRequest struct {
+	Key    string        `json:"key"`
+	Limit  int           `json:"limit,omitempty"`
+	Filter *QdrantFilter `json:"filter,omitempty"`
+	Exact  bool          `json:"exact,omitempty"`
+}
+
+// qdrantFacetConfig Facet mode configuration
+type qdrantFacetConfig struct {
+	key   string
+	limit int
+}
+
+// Facet enables facet mode: value counts of key under the builder's filter
+// limit: number of values, 0 keeps the server default
+// VectorSearch is ignored in facet mode, so a search builder can be reused for the sidebar
+// QdrantX Exact(true) requests exact counts
+//
+// Example:
+//
+//	xb.Of(&CodeVector{}).
+//	    Custom(xb.NewQdrantBuilder().Facet("language", 10).Build()).
+//	    Eq("layer", "service").
+//	    Build().
+//	    JsonOfSelect()
+func (qb *QdrantBuilder) Facet(key string, limit int) *QdrantBuilder {
+	if key == "" {
+		panic("Facet() requires a non-empty key")
+	}
+	if limit < 0 {
+		panic(fmt.Sprintf("Facet() limit must be >= 0, got: %d", limit))
+	}
+	qb.custom.facet = &qdrantFacetConfig{key: key, limit: limit}
+	return qb
+}
+
+// toQdrantFacetJSON internal implementation
+func (built *Built) toQdrantFacetJSON() (string, error) {
+	built = ensureQdrantAdvanced(built)
+
+	req := &QdrantFacetRequest{}
+	for _, bb := range built.Conds {
+		switch bb.Op {
+		case QDRANT_FACET:
+			req.Key = bb.Key
+			req.Limit = bb.Value.(int)
+		case QDRANT_EXACT:
+			req.Exact = bb.Value.(bool)
+		}
+	}
+
+	filter, err := buildQdrantFilter(built.Conds)
+	if err != nil {
+		return "", err
+	}
+	req.Filter = nonEmptyQdrantFilter(filter)
+
+	return mergeAndSerialize(req, built.Conds)
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"testing"
+)
+
+func TestQdrantSearchGroups(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().HnswEf(64).Build()).
+		Eq("language", "golang").
+		GroupBy("doc_id").
+		VectorSearch("embedding", Vector{0.1, 0.2}, 5).
+		QdrantX(func(qx *QdrantXBuilder) {
+			qx.GroupSize(2).WithLookup("documents", "title", "url")
+		}).
+		Build()
+
+	jsonStr, err := built.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	t.Logf("JSON:\n%s", jsonStr)
+	assertJSONEqual(t, jsonStr, `{
+		"vector": [0.1, 0.2],
+		"limit": 5,
+		"filter": {"must": [{"key": "language", "match": {"value": "golang"}}]},
+		"with_payload": true,
+		"params": {"hnsw_ef": 64},
+		"group_by": "doc_id",
+		"group_size": 2,
+		"with_lookup": {"collection": "documents", "with_payload": ["title", "url"]}
+	}`)
+
+	// SQL path ignores the Qdrant group parameters
+	sql, _ := built.SqlOfVectorSearch()
+	if sql != "SELECT *, embedding <-> ? AS distance FROM code_vectors WHERE language = ? ORDER BY distance LIMIT 5" {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+}
+
+func TestQdrantSearchGroups_Errors(t *testing.T) {
+	cases := map[string]*Built{
+		"two group keys": Of(&CodeVectorForQdrant{}).
+			Custom(NewQdrantBuilder().Build()).
+			GroupBy("doc_id").GroupBy("lang").
+			VectorSearch("embedding", Vector{0.1}, 5).
+			Build(),
+		"offset": Of(&CodeVectorForQdrant{}).
+			Custom(NewQdrantBuilder().Build()).
+			GroupBy("doc_id").
+			VectorSearch("embedding", Vector{0.1}, 5).
+			Paged(func(pb *PageBuilder) { pb.Page(2).Rows(5) }).
+			Build(),
+		"no vector": Of(&CodeVectorForQdrant{}).
+			Custom(NewQdrantBuilder().Build()).
+			GroupBy("doc_id").
+			Build(),
+	}
+	for name, built := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := built.JsonOfSelect(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	grouped := Of(&CodeVectorForQdrant{}).GroupBy("doc_id").VectorSearch("embedding", Vector{0.1}, 5).Build()
+	if _, err := QdrantBatch(grouped); err == nil {
+		t.Error("expected error, groups can not be batched")
+	}
+}
+
+func TestQdrantFacet(t *testing.T) {
+	jsonStr, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Facet("language", 10).Build()).
+		Eq("layer", "service").
+		Ne("language", "").
+		VectorSearch("embedding", Vector{0.1}, 5). // ignored
+		QdrantX(func(qx *QdrantXBuilder) { qx.Exact(true) }).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{
+		"key": "language",
+		"limit": 10,
+		"filter": {"must": [{"key": "layer", "match": {"value": "service"}}]},
+		"exact": true
+	}`)
+}
+
+func TestQdrantGroupsFacet_Validation(t *testing.T) {
+	cases := map[string]func(){
+		"group size":  func() { Of(&CodeVectorForQdrant{}).QdrantX(func(qx *QdrantXBuilder) { qx.GroupSize(0) }) },
+		"lookup":      func() { Of(&CodeVectorForQdrant{}).QdrantX(func(qx *QdrantXBuilder) { qx.WithLookup("") }) },
+		"facet key":   func() { NewQdrantBuilder().Facet("", 1) },
+		"facet limit": func() { NewQdrantBuilder().Facet("k", -1) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ============================================================================
+// Qdrant payload and vector updates
+// ============================================================================
+
+// QdrantPointsSelector selects points by id list or by filter
+type QdrantPointsSelector struct {
+	Points []PointID     `json:"points,omitempty"`
+	Filter *QdrantFilter `json:"filter,omitempty"`
+}
+
+// QdrantSetPayloadRequest set / overwrite payload request
+// POST (set) or PUT (overwrite) /collections/{name}/points/payload
+type QdrantSetPayloadRequest struct {
+	Payload map[string]interface{} `json:"payload"`
+	QdrantPointsSelector
+	Key string `json:"key,omitempty"` // Nested key path, set under this key
+}
+
+// QdrantDeletePayloadRequest delete payload keys request
+// POST /collections/{name}/points/payload/delete
+type QdrantDeletePayloadRequest struct {
+	Keys []string `json:"keys"`
+	QdrantPointsSelector
+}
+
+// QdrantUpdateVectorsRequest update vectors request
+// PUT /collections/{name}/points/vectors
+type QdrantUpdateVectorsRequest struct {
+	Points []QdrantPointVectors `json:"points"`
+}
+
+// QdrantPointVectors vectors of one point
+// Vector: Vector for the default vector, map[string]Vector for named vectors
+type QdrantPointVectors struct {
+	ID     PointID     `json:"id"`
+	Vector interface{} `json:"vector"`
+}
+
+// QdrantDeleteVectorsRequest delete named vectors request
+// POST /collections/{name}/points/vectors/delete
+type QdrantDeleteVectorsRequest struct {
+	QdrantPointsSelector
+	Vector []string `json:"vector"`
+}
+
+// JsonOfQdrantUpdate generates update JSON and the endpoint to send it to
+// The endpoint depends on the UpdateBuilder mode:
+//
+//	(default), PayloadKey()  POST /points/payload
+//	OverwritePayload()       PUT  /points/payload
+//	DeletePayloadKeys()      POST /points/payload/delete
+//	ClearPayload()           POST /points/payload/clear
+//	Vector()                 PUT  /points/vectors
+//	DeleteVectors()          POST /points/vectors/delete
+//
+// Example:
+//
+//	json, ep, err := xb.Of(&CodeVector{}).
+//	    Custom(xb.NewQdrantBuilder().Build()).
+//	    In("id", 1, 2).
+//	    Update(func(ub *xb.UpdateBuilder) { ub.DeletePayloadKeys("draft") }).
+//	    Build().
+//	    JsonOfQdrantUpdate()
+//	// ep.Method: POST, ep.URL("code_vectors"): /collections/code_vectors/points/payload/delete
+func (built *Built) JsonOfQdrantUpdate() (string, QdrantEndpoint, error) {
+	custom, ok := built.Custom.(*QdrantCustom)
+	if !ok {
+		return "", QdrantEndpoint{}, fmt.Errorf("JsonOfQdrantUpdate() requires Qdrant Custom, got: %T", built.Custom)
+	}
+	if built.Updates == nil || len(*built.Updates) == 0 {
+		return "", QdrantEndpoint{}, fmt.Errorf("no update data")
+	}
+	return custom.generateUpdate(custom.applyAdvancedConfig(built))
+}
+
+// generateUpdate builds the update request of the UpdateBuilder mode
+func (c *QdrantCustom) generateUpdate(built *Built) (string, QdrantEndpoint, error) {
+	mode := ""
+	key := ""
+	payload := make(map[string]interface{})
+	var deleteKeys, deleteVectors []string
+	vectors := make(map[string]Vector)
+	for _, bb := range *built.Updates {
+		if !isQdrantUpdateOp(bb.Op) {
+			payload[bb.Key] = bb.Value
+			continue
+		}
+		switch bb.Op {
+		case QDRANT_PAYLOAD_KEY:
+			key = bb.Value.(string)
+			continue
+		case QDRANT_PAYLOAD_DELETE:
+			deleteKeys = append(deleteKeys, bb.Value.([]string)...)
+		case QDRANT_DELETE_VECTORS:
+			deleteVectors = append(deleteVectors, bb.Value.([]string)...)
+		case QDRANT_UPDATE_VECTOR:
+			vectors[bb.Key] = bb.Value.(Vector)
+		}
+		if mode != "" && mode != bb.Op {
+			return "", QdrantEndpoint{}, fmt.Errorf("update modes %s and %s can not be combined", mode, bb.Op)
+		}
+		mode = bb.Op
+	}
+
+	if key != "" && mode != "" && mode != QDRANT_PAYLOAD_OVERWRITE {
+		return "", QdrantEndpoint{}, fmt.Errorf("PayloadKey() can not be combined with %s", mode)
+	}
+	if len(payload) > 0 && mode != "" && mode != QDRANT_PAYLOAD_OVERWRITE {
+		return "", QdrantEndpoint{}, fmt.Errorf("Set() values can not be combined with %s", mode)
+	}
+
+	ids, filter, err := c.extractIdsOrFilter(built.Conds)
+	if err != nil {
+		return "", QdrantEndpoint{}, err
+	}
+	selector := QdrantPointsSelector{Points: ids}
+	if len(ids) == 0 {
+		selector.Filter = filter
+	}
+	// The default set mode keeps accepting an update without selector
+	if mode != "" && selector.Points == nil && selector.Filter == nil {
+		return "", QdrantEndpoint{}, fmt.Errorf("%s requires points selected by id or by filter", mode)
+	}
+
+	var req interface{}
+	var ep QdrantEndpoint
+	switch mode {
+	case "", QDRANT_PAYLOAD_OVERWRITE:
+		if len(payload) == 0 {
+			return "", QdrantEndpoint{}, fmt.Errorf("no payload values, use Set()")
+		}
+		req = &QdrantSetPayloadRequest{Payload: payload, QdrantPointsSelector: selector, Key: key}
+		ep = QdrantEndpoint{Method: http.MethodPost, Path: "/points/payload"}
+		if mode == QDRANT_PAYLOAD_OVERWRITE {
+			ep.Method = http.MethodPut
+		}
+	case QDRANT_PAYLOAD_DELETE:
+		r := &QdrantDeletePayloadRequest{Keys: deleteKeys, QdrantPointsSelector: selector}
+		req, ep = r, r.Endpoint()
+	case QDRANT_PAYLOAD_CLEAR:
+		req = &selector
+		ep = QdrantEndpoint{Method: http.MethodPost, Path: "/points/payload/clear"}
+	case QDRANT_UPDATE_VECTOR:
+		if len(ids) == 0 {
+			return "", QdrantEndpoint{}, fmt.Errorf("vector update requires points selected by id only, Qdrant does not support a filter")
+		}
+		var vector interface{} = vectors
+		if v, ok := vectors[""]; ok && len(vectors) == 1 {
+			vector = v
+		}
+		points := make([]QdrantPointVectors, 0, len(ids))
+		for _, id := range ids {
+			points = append(points, QdrantPointVectors{ID: id, Vector: vector})
+		}
+		r := &QdrantUpdateVectorsRequest{Points: points}
+		req, ep = r, r.Endpoint()
+	case QDRANT_DELETE_VECTORS:
+		r := &QdrantDeleteVectorsRequest{QdrantPointsSelector: selector, Vector: deleteVectors}
+		req, ep = r, r.Endpoint()
+	}
+
+	bytes, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return "", QdrantEndpoint{}, fmt.Errorf("failed to marshal Qdrant update request: %w", err)
+	}
+	return string(bytes), ep, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func qdrantUpdate(t *testing.T, x *BuilderX) (string, QdrantEndpoint) {
+	t.Helper()
+	body, ep, err := x.Build().JsonOfQdrantUpdate()
+	if err != nil {
+		t.Fatalf("JsonOfQdrantUpdate failed: %v", err)
+	}
+	return body, ep
+}
+
+func TestQdrantUpdate_PayloadModes(t *testing.T) {
+	newX := func() *BuilderX {
+		return Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build())
+	}
+
+	body, ep := qdrantUpdate(t, newX().
+		In("id", 1, 2).
+		Update(func(ub *UpdateBuilder) { ub.Set("city", "Berlin").PayloadKey("address") }))
+	assertJSONEqual(t, body, `{"payload": {"city": "Berlin"}, "points": [1, 2], "key": "address"}`)
+	if ep != (QdrantEndpoint{Method: http.MethodPost, Path: "/points/payload"}) {
+		t.Errorf("unexpected endpoint: %s", ep)
+	}
+
+	body, ep = qdrantUpdate(t, newX().
+		Eq("language", "go").
+		Update(func(ub *UpdateBuilder) { ub.Set("layer", "repo").OverwritePayload() }))
+	assertJSONEqual(t, body, `{
+		"payload": {"layer": "repo"},
+		"filter": {"must": [{"key": "language", "match": {"value": "go"}}]}
+	}`)
+	if ep.Method != http.MethodPut || ep.Path != "/points/payload" {
+		t.Errorf("unexpected endpoint: %s", ep)
+	}
+
+	body, ep = qdrantUpdate(t, newX().
+		Eq("id", 7).
+		Update(func(ub *UpdateBuilder) { ub.DeletePayloadKeys("draft", "reviewer") }))
+	assertJSONEqual(t, body, `{"keys": ["draft", "reviewer"], "points": [7]}`)
+	if ep.String() != "POST /points/payload/delete" {
+		t.Errorf("unexpected endpoint: %s", ep)
+	}
+
+	body, ep = qdrantUpdate(t, newX().
+		Eq("language", "go").
+		Update(func(ub *UpdateBuilder) { ub.ClearPayload() }))
+	assertJSONEqual(t, body, `{"filter": {"must": [{"key": "language", "match": {"value": "go"}}]}}`)
+	if ep.URL("code vectors") != "/collections/code%20vectors/points/payload/clear" {
+		t.Errorf("unexpected URL: %s", ep.URL("code vectors"))
+	}
+
+	// Default mode is unchanged
+	body, err := newX().
+		Eq("id", 7).
+		Update(func(ub *UpdateBuilder) { ub.Set("city", "Paris") }).
+		Build().
+		JsonOfUpdate()
+	if err != nil {
+		t.Fatalf("JsonOfUpdate failed: %v", err)
+	}
+	assertJSONEqual(t, body, `{"payload": {"city": "Paris"}, "points": [7]}`)
+}
+
+func TestQdrantUpdate_Vectors(t *testing.T) {
+	newX := func() *BuilderX {
+		return Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build())
+	}
+
+	body, ep := qdrantUpdate(t, newX().
+		In("id", 1, 2).
+		Update(func(ub *UpdateBuilder) { ub.Vector("", Vector{0.1, 0.2}) }))
+	assertJSONEqual(t, body, `{"points": [
+		{"id": 1, "vector": [0.1, 0.2]},
+		{"id": 2, "vector": [0.1, 0.2]}
+	]}`)
+	if ep.String() != "PUT /points/vectors" {
+		t.Errorf("unexpected endpoint: %s", ep)
+	}
+
+	body, _ = qdrantUpdate(t, newX().
+		Eq("id", 1).
+		Update(func(ub *UpdateBuilder) { ub.Vector("image", Vector{0.5}).Vector("text", Vector{0.7}) }))
+	assertJSONEqual(t, body, `{"points": [{"id": 1, "vector": {"image": [0.5], "text": [0.7]}}]}`)
+
+	body, ep = qdrantUpdate(t, newX().
+		Eq("language", "go").
+		Update(func(ub *UpdateBuilder) { ub.DeleteVectors("image") }))
+	assertJSONEqual(t, body, `{
+		"filter": {"must": [{"key": "language", "match": {"value": "go"}}]},
+		"vector": ["image"]
+	}`)
+	if ep.String() != "POST /points/vectors/delete" {
+		t.Errorf("unexpected endpoint: %s", ep)
+	}
+}
+
+func TestQdrantUpdate_IdKeepsOtherConditions(t *testing.T) {
+	newX := func() *BuilderX {
+		return Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).Eq("tenant_id", 7)
+	}
+	tenantFilter := func(ids string) string {
+		return `{"must": [
+			{"key": "tenant_id", "match": {"value": 7}},
+			{"has_id": ` + ids + `}
+		]}`
+	}
+
+	body, err := newX().In("id", 1, 2).Build().JsonOfDelete()
+	if err != nil {
+		t.Fatalf("JsonOfDelete failed: %v", err)
+	}
+	assertJSONEqual(t, body, `{"filter": `+tenantFilter("[1, 2]")+`}`)
+
+	body, _ = qdrantUpdate(t, newX().Eq("id", 1).Update(func(ub *UpdateBuilder) { ub.ClearPayload() }))
+	assertJSONEqual(t, body, `{"filter": `+tenantFilter("[1]")+`}`)
+
+	body, _ = qdrantUpdate(t, newX().Eq("id", 1).Update(func(ub *UpdateBuilder) { ub.Set("a", 1).OverwritePayload() }))
+	assertJSONEqual(t, body, `{"payload": {"a": 1}, "filter": `+tenantFilter("[1]")+`}`)
+
+	body, _ = qdrantUpdate(t, newX().Eq("id", 1).Update(func(ub *UpdateBuilder) { ub.DeletePayloadKeys("a") }))
+	assertJSONEqual(t, body, `{"keys": ["a"], "filter": `+tenantFilter("[1]")+`}`)
+
+	body, _ = qdrantUpdate(t, newX().Eq("id", 1).Update(func(ub *UpdateBuilder) { ub.DeleteVectors("image") }))
+	assertJSONEqual(t, body, `{"vector": ["image"], "filter": `+tenantFilter("[1]")+`}`)
+
+	// Vectors are updated by id only, the tenant guard can not be sent
+	if _, _, err := newX().Eq("id", 1).
+		Update(func(ub *UpdateBuilder) { ub.Vector("", Vector{0.1}) }).
+		Build().JsonOfQdrantUpdate(); err == nil {
+		t.Error("expected error for a vector update with a tenant filter")
+	}
+}
+
+func TestQdrantUpdate_Errors(t *testing.T) {
+	cases := map[string]*BuilderX{
+		"mixed modes": Of(&CodeVectorForQdrant{}).Eq("id", 1).
+			Update(func(ub *UpdateBuilder) { ub.ClearPayload().DeletePayloadKeys("a") }),
+		"set with delete": Of(&CodeVectorForQdrant{}).Eq("id", 1).
+			Update(func(ub *UpdateBuilder) { ub.Set("a", "b").DeletePayloadKeys("c") }),
+		"key with clear": Of(&CodeVectorForQdrant{}).Eq("id", 1).
+			Update(func(ub *UpdateBuilder) { ub.PayloadKey("a").ClearPayload() }),
+		"no selector": Of(&CodeVectorForQdrant{}).
+			Update(func(ub *UpdateBuilder) { ub.ClearPayload() }),
+		"vector by filter": Of(&CodeVectorForQdrant{}).Eq("language", "go").
+			Update(func(ub *UpdateBuilder) { ub.Vector("", Vector{0.1}) }),
+		"key without values": Of(&CodeVectorForQdrant{}).Eq("id", 1).
+			Update(func(ub *UpdateBuilder) { ub.PayloadKey("a") }),
+	}
+	for name, x := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := x.Custom(NewQdrantBuilder().Build()).Build().JsonOfQdrantUpdate()
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if _, _, err := Of(&CodeVectorForQdrant{}).Eq("id", 1).
+		Update(func(ub *UpdateBuilder) { ub.ClearPayload() }).
+		Build().JsonOfQdrantUpdate(); err == nil {
+		t.Error("expected error without Qdrant Custom")
+	}
+}
+
+func TestQdrantUpdate_SqlIgnoresModes(t *testing.T) {
+	sql, args := Of(&CodeVectorForQdrant{}).
+		Eq("id", 1).
+		Update(func(ub *UpdateBuilder) { ub.Set("language", "go").OverwritePayload() }).
+		Build().
+		SqlOfUpdate()
+	if strings.Contains(sql, "QDRANT") || len(args) != 2 {
+		t.Errorf("unexpected SQL: %s %v", sql, args)
+	}
+}
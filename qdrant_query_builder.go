@@ -0,0 +1,251 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// QueryBuilder: Qdrant Universal Query API (/points/query)
+// ============================================================================
+
+// Query enables Qdrant Universal Query API (/points/query)
+//
+// The top level and every Prefetch() stage use the same QueryBuilder:
+// candidates of the nested stages are re-scored by the outer query.
+// The top-level filter comes from the main builder (Eq/In/...) plus Filter().
+//
+// Example:
+//
+//	xb.NewQdrantBuilder().
+//	    Query(func(qb *xb.QueryBuilder) {
+//	        qb.Prefetch(func(pb *xb.QueryBuilder) {
+//	            pb.Nearest(denseVec).Using("dense").Limit(100)
+//	        }).
+//	        Prefetch(func(pb *xb.QueryBuilder) {
+//	            pb.Nearest(titleVec).Using("title").Limit(100).
+//	                Filter(func(cb *xb.CondBuilder) {
+//	                    cb.Eq("lang", "en")
+//	                })
+//	        }).
+//	        Fusion(xb.FusionRRF).
+//	        Limit(10)
+//	    }).
+//	    Build()
+func (qb *QdrantBuilder) Query(fn func(qb *QueryBuilder)) *QdrantBuilder {
+	if fn == nil {
+		qb.custom.queryConfig = nil
+		return qb
+	}
+
+	builder := newQueryBuilder()
+	fn(builder)
+	builder.validate("Query()")
+
+	qb.custom.queryConfig = builder
+	return qb
+}
+
+// QueryBuilder one stage of a Qdrant universal query (top level or prefetch)
+type QueryBuilder struct {
+	prefetch       []*QueryBuilder
+	query          interface{} // Vector / MultiVector / SparseVector / QdrantFusionQuery / QdrantOrderByQuery / QdrantFormulaQuery
+	queryKind      string
+	using          string
+	conds          []Bb
+	limit          int
+	scoreThreshold *float32
+}
+
+func newQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// Prefetch adds a nested stage, its results are the candidates of this stage
+func (qb *QueryBuilder) Prefetch(fn func(pb *QueryBuilder)) *QueryBuilder {
+	if fn == nil {
+		return qb
+	}
+	pb := newQueryBuilder()
+	fn(pb)
+	pb.validate("Prefetch()")
+	qb.prefetch = append(qb.prefetch, pb)
+	return qb
+}
+
+// Nearest searches by vector (query: [0.1, 0.2, ...])
+func (qb *QueryBuilder) Nearest(queryVector Vector) *QueryBuilder {
+	if len(queryVector) == 0 {
+		panic("Nearest() requires a non-empty vector")
+	}
+	return qb.setQuery("Nearest()", queryVector)
+}
+
+// NearestMulti searches by a multivector (query: [[...], [...]]), scored by the vector's comparator (max_sim)
+// Typical as a ColBERT re-ranking stage over dense prefetch candidates
+//
+// Example:
+//
+//	qb.Prefetch(func(pb *xb.QueryBuilder) {
+//	    pb.Nearest(denseVec).Using("dense").Limit(100)
+//	}).
+//	NearestMulti(tokenVectors).Using("colbert").Limit(10)
+func (qb *QueryBuilder) NearestMulti(query MultiVector) *QueryBuilder {
+	if len(query) == 0 {
+		panic("NearestMulti() requires a non-empty multivector")
+	}
+	for _, row := range query {
+		if len(row) == 0 || len(row) != len(query[0]) {
+			panic("NearestMulti() rows must be non-empty and of the same dimension")
+		}
+	}
+	return qb.setQuery("Nearest()", query)
+}
+
+// NearestSparse searches by a sparse vector (query: {"indices": [...], "values": [...]}), set Using()
+//
+// Example:
+//
+//	qb.Prefetch(func(pb *xb.QueryBuilder) {
+//	    pb.NearestSparse(sparseVec).Using("text_sparse").Limit(100)
+//	})
+func (qb *QueryBuilder) NearestSparse(query SparseVector) *QueryBuilder {
+	if query.Len() == 0 {
+		panic("NearestSparse() requires a non-empty sparse vector")
+	}
+	if err := query.Validate(); err != nil {
+		panic(fmt.Sprintf("NearestSparse(): %v", err))
+	}
+	return qb.setQuery("Nearest()", query)
+}
+
+// Fusion fuses the prefetch results (FusionRRF or FusionDBSF)
+func (qb *QueryBuilder) Fusion(fusion HybridFusion) *QueryBuilder {
+	if fusion != FusionRRF && fusion != FusionDBSF {
+		panic(fmt.Sprintf("Fusion() supports rrf and dbsf, got: %s", fusion))
+	}
+	return qb.setQuery("Fusion()", QdrantFusionQuery{Fusion: string(fusion)})
+}
+
+// OrderBy orders by a payload field (requires a range payload index)
+func (qb *QueryBuilder) OrderBy(key string, direction Direction) *QueryBuilder {
+	if key == "" {
+		panic("OrderBy() requires a non-empty key")
+	}
+	orderBy := QdrantOrderBy{Key: key}
+	if direction != nil {
+		orderBy.Direction = strings.ToLower(direction())
+	}
+	return qb.setQuery("OrderBy()", QdrantOrderByQuery{OrderBy: orderBy})
+}
+
+// Formula re-scores the prefetch results with an expression
+// defaults: values of payload variables missing on a point (optional)
+//
+// Example:
+//
+//	qb.Formula(xb.QdrantSum(
+//	    xb.QdrantScore(),
+//	    xb.QdrantMult(0.5, xb.QdrantCondition{Key: "tag", Match: &xb.QdrantMatchCondition{Any: []interface{}{"h1"}}}),
+//	), nil)
+func (qb *QueryBuilder) Formula(expr interface{}, defaults map[string]interface{}) *QueryBuilder {
+	if expr == nil {
+		panic("Formula() requires an expression")
+	}
+	return qb.setQuery("Formula()", QdrantFormulaQuery{Formula: expr, Defaults: defaults})
+}
+
+// Using sets the named vector searched by Nearest()
+func (qb *QueryBuilder) Using(name string) *QueryBuilder {
+	if name == "" {
+		panic("Using() requires a non-empty vector name")
+	}
+	qb.using = name
+	return qb
+}
+
+// Filter sets the filter of this stage (AND with the main builder's conditions at the top level)
+func (qb *QueryBuilder) Filter(fn func(cb *CondBuilder)) *QueryBuilder {
+	if fn == nil {
+		return qb
+	}
+	cb := subCondBuilder()
+	fn(cb)
+	qb.conds = append(qb.conds, cb.bbs...)
+	return qb
+}
+
+// Limit sets the number of results of this stage
+func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
+	if limit < 1 {
+		panic(fmt.Sprintf("Limit must be >= 1, got: %d", limit))
+	}
+	qb.limit = limit
+	return qb
+}
+
+// ScoreThreshold sets the minimum score of this stage
+func (qb *QueryBuilder) ScoreThreshold(threshold float32) *QueryBuilder {
+	qb.scoreThreshold = &threshold
+	return qb
+}
+
+func (qb *QueryBuilder) setQuery(kind string, query interface{}) *QueryBuilder {
+	if qb.queryKind != "" && qb.queryKind != kind {
+		panic(fmt.Sprintf("%s conflicts with %s, a stage has only one query", kind, qb.queryKind))
+	}
+	qb.queryKind = kind
+	qb.query = query
+	return qb
+}
+
+// validate panics on stages Qdrant would reject
+func (qb *QueryBuilder) validate(name string) {
+	switch qb.queryKind {
+	case "Fusion()", "Formula()":
+		if len(qb.prefetch) == 0 {
+			panic(fmt.Sprintf("%s: %s requires at least one Prefetch()", name, qb.queryKind))
+		}
+	}
+	if qb.using != "" && qb.queryKind != "Nearest()" {
+		panic(fmt.Sprintf("%s: Using() requires Nearest()", name))
+	}
+}
+
+// ============================================================================
+// Formula expressions
+// ============================================================================
+
+// QdrantScore "$score" (score of the prefetch) or "$score[i]" (score of the i-th prefetch)
+func QdrantScore(prefetchIndex ...int) string {
+	if len(prefetchIndex) > 0 {
+		return fmt.Sprintf("$score[%d]", prefetchIndex[0])
+	}
+	return "$score"
+}
+
+// QdrantSum {"sum": [exprs...]}
+func QdrantSum(exprs ...interface{}) map[string]interface{} {
+	return map[string]interface{}{"sum": exprs}
+}
+
+// QdrantMult {"mult": [exprs...]}
+func QdrantMult(exprs ...interface{}) map[string]interface{} {
+	return map[string]interface{}{"mult": exprs}
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// assertJSONEqual compares two JSON documents ignoring formatting and key order
+func assertJSONEqual(t *testing.T, got, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal([]byte(got), &g); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid expected JSON: %v\n%s", err, want)
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	if string(gb) != string(wb) {
+		t.Errorf("JSON mismatch\n got: %s\nwant: %s", gb, wb)
+	}
+}
+
+func TestQdrantQuery_NestedPrefetchFusion(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().
+			HnswEf(64).
+			Query(func(qb *QueryBuilder) {
+				qb.Prefetch(func(pb *QueryBuilder) {
+					pb.Prefetch(func(inner *QueryBuilder) {
+						inner.Nearest(Vector{0.1, 0.2}).Using("dense").Limit(200)
+					}).
+						Nearest(Vector{0.3, 0.4}).Using("rerank").Limit(50)
+				}).
+					Prefetch(func(pb *QueryBuilder) {
+						pb.Nearest(Vector{0.5, 0.6}).Using("title").Limit(50).
+							Filter(func(cb *CondBuilder) {
+								cb.Eq("layer", "service")
+							})
+					}).
+					Fusion(FusionRRF).
+					Limit(5)
+			}).
+			Build()).
+		Eq("language", "golang").
+		Build()
+
+	jsonStr, err := built.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	t.Logf("JSON:\n%s", jsonStr)
+
+	assertJSONEqual(t, jsonStr, `{
+		"prefetch": [
+			{
+				"prefetch": [
+					{"query": [0.1, 0.2], "using": "dense", "params": {"hnsw_ef": 64}, "limit": 200}
+				],
+				"query": [0.3, 0.4], "using": "rerank", "params": {"hnsw_ef": 64}, "limit": 50
+			},
+			{
+				"query": [0.5, 0.6], "using": "title", "params": {"hnsw_ef": 64}, "limit": 50,
+				"filter": {"must": [{"key": "layer", "match": {"value": "service"}}]}
+			}
+		],
+		"query": {"fusion": "rrf"},
+		"filter": {"must": [{"key": "language", "match": {"value": "golang"}}]},
+		"limit": 5,
+		"with_payload": true
+	}`)
+}
+
+func TestQdrantQuery_OrderByAndFormula(t *testing.T) {
+	orderBy := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().
+			Query(func(qb *QueryBuilder) {
+				qb.OrderBy("created_at", DESC).
+					Filter(func(cb *CondBuilder) {
+						cb.Gte("stars", 10)
+					})
+			}).
+			Build()).
+		Paged(func(pb *PageBuilder) {
+			pb.Page(3).Rows(20)
+		}).
+		Build()
+
+	jsonStr, err := orderBy.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{
+		"query": {"order_by": {"key": "created_at", "direction": "desc"}},
+		"filter": {"must": [{"key": "stars", "range": {"gte": 10}}]},
+		"limit": 20,
+		"offset": 40,
+		"with_payload": true
+	}`)
+
+	formula := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().
+			Query(func(qb *QueryBuilder) {
+				qb.Prefetch(func(pb *QueryBuilder) {
+					pb.Nearest(Vector{1, 0}).Limit(100)
+				}).
+					Formula(QdrantSum(
+						QdrantScore(),
+						QdrantMult(0.5, QdrantCondition{Key: "tag", Match: &QdrantMatchCondition{Any: []interface{}{"h1"}}}),
+					), map[string]interface{}{"tag": "none"})
+			}).
+			Build()).
+		Build()
+
+	jsonStr, err = formula.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{
+		"prefetch": [{"query": [1, 0], "params": {"hnsw_ef": 128}, "limit": 100}],
+		"query": {
+			"formula": {"sum": ["$score", {"mult": [0.5, {"key": "tag", "match": {"any": ["h1"]}}]}]},
+			"defaults": {"tag": "none"}
+		},
+		"limit": 10,
+		"with_payload": true
+	}`)
+}
+
+func TestQdrantQuery_Validation(t *testing.T) {
+	cases := map[string]func(){
+		"fusion without prefetch": func() {
+			NewQdrantBuilder().Query(func(qb *QueryBuilder) { qb.Fusion(FusionRRF) })
+		},
+		"weighted fusion": func() {
+			NewQdrantBuilder().Query(func(qb *QueryBuilder) { qb.Fusion(FusionWeighted) })
+		},
+		"two queries": func() {
+			NewQdrantBuilder().Query(func(qb *QueryBuilder) { qb.Nearest(Vector{1}).OrderBy("ts", ASC) })
+		},
+		"using without nearest": func() {
+			NewQdrantBuilder().Query(func(qb *QueryBuilder) {
+				qb.Prefetch(func(pb *QueryBuilder) { pb.Using("dense") })
+			})
+		},
+		"zero limit": func() {
+			NewQdrantBuilder().Query(func(qb *QueryBuilder) { qb.Limit(0) })
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ============================================================================
+// Qdrant response decoding
+// ============================================================================
+//
+// Decoders turn Qdrant REST responses into ScoredPoint[T]:
+//   - ID is a PointID (integer or UUID, no float64 precision loss)
+//   - payload is unmarshalled into T by json tags (T can be map[string]interface{})
+//   - vector is decoded into Vector, named dense vectors into Vectors
+//   - a non-"ok" status is returned as error
+
+// qdrantResponse common envelope: {"result": ..., "status": "ok", "time": 0.001}
+type qdrantResponse[R any] struct {
+	Result R           `json:"result"`
+	Status interface{} `json:"status"`
+	Time   float64     `json:"time"`
+}
+
+// qdrantWirePoint point as Qdrant sends it
+type qdrantWirePoint[T any] struct {
+	ID      PointID         `json:"id"`
+	Score   float32         `json:"score"`
+	Payload T               `json:"payload"`
+	Vector  json.RawMessage `json:"vector"`
+}
+
+func (w *qdrantWirePoint[T]) scored() (ScoredPoint[T], error) {
+	p := ScoredPoint[T]{ID: w.ID, Score: w.Score, Payload: w.Payload}
+	raw := bytes.TrimSpace(w.Vector)
+	if len(raw) == 0 {
+		return p, nil
+	}
+	switch raw[0] {
+	case '[':
+		if err := json.Unmarshal(raw, &p.Vector); err != nil {
+			return p, fmt.Errorf("point %s: %w", w.ID, err)
+		}
+	case '{':
+		// Named vectors {"title": [...], "colbert": [[...]]}, only dense ones are kept
+		var named map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &named); err != nil {
+			return p, fmt.Errorf("point %s: %w", w.ID, err)
+		}
+		for name, v := range named {
+			var vec Vector
+			if json.Unmarshal(v, &vec) == nil {
+				if p.Vectors == nil {
+					p.Vectors = map[string]Vector{}
+				}
+				p.Vectors[name] = vec
+			}
+		}
+	}
+	return p, nil
+}
+
+func scoredPoints[T any](wire []qdrantWirePoint[T]) ([]ScoredPoint[T], error) {
+	points := make([]ScoredPoint[T], 0, len(wire))
+	for i := range wire {
+		p, err := wire[i].scored()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// decodeQdrant unmarshals the envelope and checks the status
+func decodeQdrant[R any](body []byte, api string) (R, error) {
+	var resp qdrantResponse[R]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return resp.Result, fmt.Errorf("failed to unmarshal Qdrant %s response: %w", api, err)
+	}
+	if err := qdrantStatusError(resp.Status); err != nil {
+		return resp.Result, err
+	}
+	return resp.Result, nil
+}
+
+// qdrantStatusError status "ok" or {"error": "..."}
+func qdrantStatusError(status interface{}) error {
+	switch s := status.(type) {
+	case nil, string:
+		if s == nil || s == "ok" {
+			return nil
+		}
+		return fmt.Errorf("Qdrant status: %v", s)
+	case map[string]interface{}:
+		if msg, ok := s["error"]; ok {
+			return fmt.Errorf("Qdrant error: %v", msg)
+		}
+	}
+	return fmt.Errorf("Qdrant status: %v", status)
+}
+
+// DecodeQdrantSearch decodes a /points/search response
+// Also fits /points/recommend and /points/discover, they answer the same shape
+//
+// Example:
+//
+//	type Code struct {
+//	    Language string `json:"language"`
+//	    Content  string `json:"content"`
+//	}
+//	points, err := xb.DecodeQdrantSearch[Code](body)
+//	for _, p := range points {
+//	    fmt.Println(p.ID, p.Score, p.Payload.Language)
+//	}
+func DecodeQdrantSearch[T any](body []byte) ([]ScoredPoint[T], error) {
+	wire, err := decodeQdrant[[]qdrantWirePoint[T]](body, "search")
+	if err != nil {
+		return nil, err
+	}
+	return scoredPoints(wire)
+}
+
+// DecodeQdrantRecommend decodes a /points/recommend or /points/discover response
+func DecodeQdrantRecommend[T any](body []byte) ([]ScoredPoint[T], error) {
+	return DecodeQdrantSearch[T](body)
+}
+
+// DecodeQdrantQuery decodes a /points/query response (Query() and Hybrid())
+func DecodeQdrantQuery[T any](body []byte) ([]ScoredPoint[T], error) {
+	result, err := decodeQdrant[struct {
+		Points []qdrantWirePoint[T] `json:"points"`
+	}](body, "query")
+	if err != nil {
+		return nil, err
+	}
+	return scoredPoints(result.Points)
+}
+
+// QdrantScrollPage one page of a scroll
+type QdrantScrollPage[T any] struct {
+	Points         []ScoredPoint[T] // Score is always 0
+	NextPageOffset *PointID         // nil on the last page, QdrantBuilder.Offset() of the next page
+}
+
+// HasNext more pages follow
+func (p *QdrantScrollPage[T]) HasNext() bool {
+	return p.NextPageOffset != nil
+}
+
+// DecodeQdrantScroll decodes a /points/scroll response
+func DecodeQdrantScroll[T any](body []byte) (*QdrantScrollPage[T], error) {
+	result, err := decodeQdrant[struct {
+		Points         []qdrantWirePoint[T] `json:"points"`
+		NextPageOffset *PointID             `json:"next_page_offset"`
+	}](body, "scroll")
+	if err != nil {
+		return nil, err
+	}
+	points, err := scoredPoints(result.Points)
+	if err != nil {
+		return nil, err
+	}
+	return &QdrantScrollPage[T]{Points: points, NextPageOffset: result.NextPageOffset}, nil
+}
+
+// QdrantGroup one group of a /points/search/groups response
+type QdrantGroup[T any] struct {
+	ID     interface{}      // Value of the group_by field: string or json.Number
+	Hits   []ScoredPoint[T] // Best hits of the group, at most group_size
+	Lookup *ScoredPoint[T]  // Point of the with_lookup collection, nil without lookup
+}
+
+// DecodeQdrantGroups decodes a /points/search/groups response
+func DecodeQdrantGroups[T any](body []byte) ([]QdrantGroup[T], error) {
+	result, err := decodeQdrant[struct {
+		Groups []struct {
+			ID     json.RawMessage      `json:"id"`
+			Hits   []qdrantWirePoint[T] `json:"hits"`
+			Lookup *qdrantWirePoint[T]  `json:"lookup"`
+		} `json:"groups"`
+	}](body, "groups")
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]QdrantGroup[T], 0, len(result.Groups))
+	for _, g := range result.Groups {
+		group := QdrantGroup[T]{}
+		if group.ID, err = jsonNumberValue(g.ID); err != nil {
+			return nil, fmt.Errorf("failed to decode Qdrant group id: %w", err)
+		}
+		if group.Hits, err = scoredPoints(g.Hits); err != nil {
+			return nil, err
+		}
+		if g.Lookup != nil {
+			lookup, err := g.Lookup.scored()
+			if err != nil {
+				return nil, err
+			}
+			group.Lookup = &lookup
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// DecodeQdrantCount decodes a /points/count response
+func DecodeQdrantCount(body []byte) (uint64, error) {
+	result, err := decodeQdrant[struct {
+		Count uint64 `json:"count"`
+	}](body, "count")
+	return result.Count, err
+}
+
+// QdrantFacetHit one value of a /facet response
+type QdrantFacetHit struct {
+	Value interface{} `json:"value"` // string, json.Number or bool
+	Count uint64      `json:"count"`
+}
+
+// DecodeQdrantFacet decodes a /facet response, hits are ordered by count
+func DecodeQdrantFacet(body []byte) ([]QdrantFacetHit, error) {
+	result, err := decodeQdrant[struct {
+		Hits []struct {
+			Value json.RawMessage `json:"value"`
+			Count uint64          `json:"count"`
+		} `json:"hits"`
+	}](body, "facet")
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]QdrantFacetHit, 0, len(result.Hits))
+	for _, h := range result.Hits {
+		value, err := jsonNumberValue(h.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Qdrant facet value: %w", err)
+		}
+		hits = append(hits, QdrantFacetHit{Value: value, Count: h.Count})
+	}
+	return hits, nil
+}
+
+// jsonNumberValue decodes a JSON scalar, numbers as json.Number
+func jsonNumberValue(raw json.RawMessage) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	err := dec.Decode(&v)
+	return v, err
+}
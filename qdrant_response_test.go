@@ -0,0 +1,166 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type responseDoc struct {
+	Language string   `json:"language"`
+	Tags     []string `json:"tags"`
+}
+
+func TestDecodeQdrantSearch(t *testing.T) {
+	body := []byte(`{
+		"result": [
+			{"id": 18446744073709551000, "version": 3, "score": 0.91,
+			 "payload": {"language": "go", "tags": ["db"]}, "vector": [0.1, 0.2]},
+			{"id": "5c56c793-69f3-4fbf-87e6-c4bf54c28c26", "score": 0.5,
+			 "payload": {"language": "rust"}, "vector": {"image": [0.3]}}
+		],
+		"status": "ok",
+		"time": 0.001
+	}`)
+	points, err := DecodeQdrantSearch[responseDoc](body)
+	if err != nil {
+		t.Fatalf("DecodeQdrantSearch failed: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("want 2 points, got %d", len(points))
+	}
+	if points[0].ID != NumPointID(18446744073709551000) || points[0].Score != 0.91 {
+		t.Errorf("unexpected first point: %+v", points[0])
+	}
+	if points[0].Payload.Language != "go" || points[0].Payload.Tags[0] != "db" {
+		t.Errorf("unexpected payload: %+v", points[0].Payload)
+	}
+	if len(points[0].Vector) != 2 || points[0].Vector[1] != 0.2 {
+		t.Errorf("unexpected vector: %v", points[0].Vector)
+	}
+	if points[1].ID != UUIDPointID("5c56c793-69f3-4fbf-87e6-c4bf54c28c26") || points[1].Vector != nil {
+		t.Errorf("unexpected second point: %+v", points[1])
+	}
+
+	// map payloads and recommend share the decoder
+	maps, err := DecodeQdrantRecommend[map[string]interface{}](body)
+	if err != nil || maps[1].Payload["language"] != "rust" {
+		t.Errorf("unexpected map payload: %v %v", maps, err)
+	}
+
+	if _, err := DecodeQdrantSearch[responseDoc]([]byte(`{"status": {"error": "Wrong input: Vector dimension error"}}`)); err == nil {
+		t.Error("expected error for Qdrant error status")
+	}
+	if _, err := DecodeQdrantSearch[responseDoc]([]byte(`{"result": [{"id": -1}]}`)); err == nil {
+		t.Error("expected error for invalid id")
+	}
+}
+
+func TestDecodeQdrantScroll(t *testing.T) {
+	body := []byte(`{
+		"result": {
+			"points": [{"id": 1, "payload": {"language": "go"}}, {"id": 2, "payload": {"language": "java"}}],
+			"next_page_offset": 3
+		},
+		"status": "ok"
+	}`)
+	page, err := DecodeQdrantScroll[responseDoc](body)
+	if err != nil {
+		t.Fatalf("DecodeQdrantScroll failed: %v", err)
+	}
+	if len(page.Points) != 2 || page.Points[1].Payload.Language != "java" {
+		t.Errorf("unexpected points: %+v", page.Points)
+	}
+	if !page.HasNext() || *page.NextPageOffset != NumPointID(3) {
+		t.Errorf("unexpected next page: %v", page.NextPageOffset)
+	}
+
+	// The offset chains back into the next scroll request, keeping its type
+	next, err := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Offset(*page.NextPageOffset).Build()).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, next, `{"offset": 3, "limit": 100, "with_payload": true}`)
+
+	uuid := UUIDPointID("5c56c793-69f3-4fbf-87e6-c4bf54c28c26")
+	next, _ = Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Offset(uuid).Build()).
+		Build().
+		JsonOfSelect()
+	assertJSONEqual(t, next, `{"offset": "5c56c793-69f3-4fbf-87e6-c4bf54c28c26", "limit": 100, "with_payload": true}`)
+
+	// First page: no offset
+	first, _ := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Scroll().Build()).
+		Eq("language", "golang").
+		Build().
+		JsonOfSelect()
+	assertJSONEqual(t, first, `{"limit": 100, "with_payload": true, "filter": {"must": [{"key": "language", "match": {"value": "golang"}}]}}`)
+
+	last, err := DecodeQdrantScroll[responseDoc]([]byte(`{"result": {"points": [], "next_page_offset": null}, "status": "ok"}`))
+	if err != nil {
+		t.Fatalf("DecodeQdrantScroll failed: %v", err)
+	}
+	if last.HasNext() || last.NextPageOffset != nil {
+		t.Errorf("last page must not have a next page")
+	}
+}
+
+func TestDecodeQdrantGroups(t *testing.T) {
+	body := []byte(`{
+		"result": {"groups": [
+			{"id": "doc-1", "hits": [{"id": 1, "score": 0.9, "payload": {"language": "go"}}],
+			 "lookup": {"id": 10, "payload": {"language": "title"}}},
+			{"id": 42, "hits": [{"id": 2, "score": 0.7}, {"id": 3, "score": 0.6}]}
+		]},
+		"status": "ok"
+	}`)
+	groups, err := DecodeQdrantGroups[responseDoc](body)
+	if err != nil {
+		t.Fatalf("DecodeQdrantGroups failed: %v", err)
+	}
+	if len(groups) != 2 || groups[0].ID != "doc-1" || groups[1].ID != json.Number("42") {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	if groups[0].Lookup == nil || groups[0].Lookup.ID != NumPointID(10) || groups[1].Lookup != nil {
+		t.Errorf("unexpected lookup: %+v", groups)
+	}
+	if len(groups[1].Hits) != 2 || groups[1].Hits[1].ID != NumPointID(3) {
+		t.Errorf("unexpected hits: %+v", groups[1].Hits)
+	}
+}
+
+func TestDecodeQdrantCountFacetQuery(t *testing.T) {
+	count, err := DecodeQdrantCount([]byte(`{"result": {"count": 1234}, "status": "ok"}`))
+	if err != nil || count != 1234 {
+		t.Errorf("unexpected count: %d %v", count, err)
+	}
+
+	hits, err := DecodeQdrantFacet([]byte(`{"result": {"hits": [{"value": "go", "count": 10}, {"value": 7, "count": 2}]}, "status": "ok"}`))
+	if err != nil || len(hits) != 2 || hits[0].Value != "go" || hits[1].Value != json.Number("7") || hits[1].Count != 2 {
+		t.Errorf("unexpected facet hits: %+v %v", hits, err)
+	}
+
+	points, err := DecodeQdrantQuery[responseDoc]([]byte(`{"result": {"points": [{"id": 5, "score": 0.3}]}, "status": "ok"}`))
+	if err != nil || len(points) != 1 || points[0].ID != NumPointID(5) {
+		t.Errorf("unexpected query points: %+v %v", points, err)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import "fmt"
+
+// ============================================================================
+// QdrantXBuilder: per-query Qdrant tuning
+// ============================================================================
+
+// QdrantX per-query Qdrant parameters (override QdrantBuilder defaults for this query only)
+// Only read by Qdrant JSON generation, SqlOfVectorSearch() skips them
+//
+// Example:
+//
+//	xb.Of(&CodeVector{}).
+//	    Custom(xb.NewQdrantBuilder().HnswEf(128).Build()).
+//	    VectorSearch("embedding", vec, 10).
+//	    QdrantX(func(qx *xb.QdrantXBuilder) {
+//	        qx.HnswEf(512).
+//	            Quantization(true, 2.0, false).
+//	            WithPayload("title", "lang")
+//	    }).
+//	    Build()
+func (x *BuilderX) QdrantX(fn func(qx *QdrantXBuilder)) *BuilderX {
+	if fn == nil {
+		return x
+	}
+	qx := &QdrantXBuilder{}
+	fn(qx)
+	x.bbs = append(x.bbs, qx.bbs...)
+	return x
+}
+
+// QdrantXBuilder per-query Qdrant parameters builder
+type QdrantXBuilder struct {
+	bbs []Bb
+}
+
+// QdrantQuantizationParams params.quantization of a search
+type QdrantQuantizationParams struct {
+	Ignore       bool    `json:"ignore"`                 // Skip quantized vectors
+	Rescore      bool    `json:"rescore"`                // Re-score top candidates with original vectors
+	Oversampling float64 `json:"oversampling,omitempty"` // Fetch limit * oversampling candidates before rescoring
+}
+
+// HnswEf sets the ef parameter for HNSW algorithm
+// Recommended value: 64-256
+func (qx *QdrantXBuilder) HnswEf(ef int) *QdrantXBuilder {
+	if ef < 1 {
+		panic(fmt.Sprintf("HnswEf must be >= 1, got: %d", ef))
+	}
+	return qx.add(QDRANT_HNSW_EF, "", ef)
+}
+
+// Exact exact (brute-force) search, bypasses the HNSW index
+func (qx *QdrantXBuilder) Exact(exact bool) *QdrantXBuilder {
+	return qx.add(QDRANT_EXACT, "", exact)
+}
+
+// IndexedOnly only searches segments with a built index (faster during indexing)
+func (qx *QdrantXBuilder) IndexedOnly(indexedOnly bool) *QdrantXBuilder {
+	return qx.add(QDRANT_INDEXED_ONLY, "", indexedOnly)
+}
+
+// Quantization sets how quantized vectors are used
+// oversampling must be >= 1 (0 keeps the collection default)
+func (qx *QdrantXBuilder) Quantization(rescore bool, oversampling float64, ignore bool) *QdrantXBuilder {
+	if oversampling != 0 && oversampling < 1 {
+		panic(fmt.Sprintf("Quantization oversampling must be >= 1, got: %f", oversampling))
+	}
+	return qx.add(QDRANT_QUANTIZATION, "", &QdrantQuantizationParams{
+		Ignore:       ignore,
+		Rescore:      rescore,
+		Oversampling: oversampling,
+	})
+}
+
+// ScoreThreshold sets the minimum similarity threshold
+// Only returns results with similarity >= threshold
+func (qx *QdrantXBuilder) ScoreThreshold(threshold float32) *QdrantXBuilder {
+	if threshold < 0 || threshold > 1 {
+		panic(fmt.Sprintf("ScoreThreshold must be in [0, 1], got: %f", threshold))
+	}
+	return qx.add(QDRANT_SCORE_THRESHOLD, "", threshold)
+}
+
+// WithPayload returns only the given payload fields, all fields if none given
+func (qx *QdrantXBuilder) WithPayload(fields ...string) *QdrantXBuilder {
+	if len(fields) == 0 {
+		return qx.add(QDRANT_WITH_PAYLOAD, "", true)
+	}
+	for _, f := range fields {
+		if f == "" {
+			panic("WithPayload() field can not be empty")
+		}
+	}
+	return qx.add(QDRANT_WITH_PAYLOAD, "", append([]string(nil), fields...))
+}
+
+// WithVector returns the given named vectors, all vectors if none given
+func (qx *QdrantXBuilder) WithVector(names ...string) *QdrantXBuilder {
+	if len(names) == 0 {
+		return qx.add(QDRANT_WITH_VECTOR, "", true)
+	}
+	for _, n := range names {
+		if n == "" {
+			panic("WithVector() name can not be empty")
+		}
+	}
+	// with_vector: ["dense", ...] is not a bool, written as a raw top-level field
+	return qx.add(QDRANT_XX, "with_vector", append([]string(nil), names...))
+}
+
+// GroupSize max points per group, used with GroupBy() (/points/search/groups)
+func (qx *QdrantXBuilder) GroupSize(size int) *QdrantXBuilder {
+	if size < 1 {
+		panic(fmt.Sprintf("GroupSize must be >= 1, got: %d", size))
+	}
+	return qx.add(QDRANT_GROUP_SIZE, "", size)
+}
+
+// WithLookup fetches each group id from another collection, used with GroupBy()
+// e.g. chunks grouped by doc_id, documents looked up in "documents"
+// payloadFields: fields of the looked up point, all if none given
+func (qx *QdrantXBuilder) WithLookup(collection string, payloadFields ...string) *QdrantXBuilder {
+	if collection == "" {
+		panic("WithLookup() collection can not be empty")
+	}
+	lookup := &QdrantWithLookup{Collection: collection, WithPayload: true}
+	if len(payloadFields) > 0 {
+		lookup.WithPayload = append([]string(nil), payloadFields...)
+	}
+	return qx.add(QDRANT_WITH_LOOKUP, "", lookup)
+}
+
+// X sets a raw top-level request field, for parameters xb does not model yet
+func (qx *QdrantXBuilder) X(key string, value interface{}) *QdrantXBuilder {
+	if key == "" {
+		panic("QdrantX X() key can not be empty")
+	}
+	return qx.add(QDRANT_XX, key, value)
+}
+
+func (qx *QdrantXBuilder) add(op string, key string, value interface{}) *QdrantXBuilder {
+	qx.bbs = append(qx.bbs, Bb{
+		Op:    op,
+		Key:   key,
+		Value: value,
+	})
+	return qx
+}
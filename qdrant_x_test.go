@@ -0,0 +1,135 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQdrantX_OverridesCustomDefaults(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().HnswEf(128).ScoreThreshold(0.5).Build()).
+		Eq("language", "golang").
+		VectorSearch("embedding", Vector{0.1, 0.2}, 10).
+		QdrantX(func(qx *QdrantXBuilder) {
+			qx.HnswEf(512).
+				Exact(true).
+				IndexedOnly(true).
+				Quantization(true, 2, false).
+				ScoreThreshold(0.8).
+				WithPayload("title", "lang").
+				WithVector("dense").
+				X("shard_key", "eu")
+		}).
+		Build()
+
+	jsonStr, err := built.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	t.Logf("JSON:\n%s", jsonStr)
+
+	assertJSONEqual(t, jsonStr, `{
+		"vector": [0.1, 0.2],
+		"limit": 10,
+		"filter": {"must": [{"key": "language", "match": {"value": "golang"}}]},
+		"with_payload": ["title", "lang"],
+		"with_vector": ["dense"],
+		"score_threshold": 0.8,
+		"params": {
+			"hnsw_ef": 512,
+			"exact": true,
+			"indexed_only": true,
+			"quantization": {"ignore": false, "rescore": true, "oversampling": 2}
+		},
+		"shard_key": "eu"
+	}`)
+
+	// SQL skips Qdrant parameters
+	sql, args := built.SqlOfVectorSearch()
+	if strings.Contains(sql, "QDRANT") || len(args) != 2 {
+		t.Errorf("SqlOfVectorSearch should ignore QdrantX: %s %v", sql, args)
+	}
+}
+
+func TestQdrantX_AdvancedAPIs(t *testing.T) {
+	recommend := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().
+			Recommend(func(rb *RecommendBuilder) {
+				rb.Positive(1, 2).Limit(5)
+			}).
+			Build()).
+		QdrantX(func(qx *QdrantXBuilder) {
+			qx.HnswEf(64).WithPayload().WithVector()
+		}).
+		Build()
+
+	jsonStr, err := recommend.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{
+		"positive": [1, 2],
+		"limit": 5,
+		"with_payload": true,
+		"with_vector": true,
+		"params": {"hnsw_ef": 64}
+	}`)
+
+	hybrid := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Hybrid(func(h *HybridBuilder) {
+			h.Vector("embedding", Vector{1, 0}).Limit(3)
+		}).
+		QdrantX(func(qx *QdrantXBuilder) {
+			qx.Exact(true).WithPayload("title")
+		}).
+		Build()
+
+	jsonStr, err = hybrid.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{
+		"prefetch": [{"query": [1, 0], "params": {"hnsw_ef": 128, "exact": true}, "limit": 15}],
+		"query": {"fusion": "rrf"},
+		"limit": 3,
+		"with_payload": ["title"]
+	}`)
+}
+
+func TestQdrantX_Validation(t *testing.T) {
+	cases := map[string]func(qx *QdrantXBuilder){
+		"hnsw_ef":         func(qx *QdrantXBuilder) { qx.HnswEf(0) },
+		"score threshold": func(qx *QdrantXBuilder) { qx.ScoreThreshold(1.5) },
+		"oversampling":    func(qx *QdrantXBuilder) { qx.Quantization(true, 0.5, false) },
+		"payload field":   func(qx *QdrantXBuilder) { qx.WithPayload("title", "") },
+		"vector name":     func(qx *QdrantXBuilder) { qx.WithVector("") },
+		"x key":           func(qx *QdrantXBuilder) { qx.X("", 1) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			Of(&CodeVectorForQdrant{}).QdrantX(fn)
+		})
+	}
+}
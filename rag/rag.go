@@ -0,0 +1,383 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rag
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fndome/xb"
+)
+
+// Chunk one piece of a document: doc_id, chunk_index and text columns (payload fields)
+// Decode search results into xb.ScoredPoint[Chunk]; ID, Vector and Metadata are written, not decoded
+type Chunk struct {
+	ID         interface{}            `json:"-"` // nil: ChunkID(DocID, ChunkIndex)
+	DocID      string                 `json:"doc_id"`
+	ChunkIndex int                    `json:"chunk_index"`
+	Text       string                 `json:"text"`
+	Vector     xb.Vector              `json:"-"`
+	Metadata   map[string]interface{} `json:"-"` // Extra columns / payload fields (tenant_id, title, ...)
+}
+
+// ChunkID deterministic UUID of a chunk, re-upserting a document overwrites its chunks
+func ChunkID(docID string, chunkIndex int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s#%d", docID, chunkIndex)))
+	sum[6] = sum[6]&0x0f | 0x50 // Version 5
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// Store chunk table (SQL, pgvector) or collection (Qdrant)
+//
+// Queries are *xb.BuilderX, add tenant filters or Rerank() before Build():
+//
+//	store := rag.NewStore("chunks", xb.NewQdrantBuilder().Build())
+//
+//	body, ep, err := store.UpsertJSON(chunks)            // one PUT /points
+//	groups, err := qdrant.Groups[rag.Chunk](ctx, client, "chunks",
+//	    store.Search(queryVec, 5, 2).Eq("tenant_id", tenant).Build())
+//	hits := rag.FromGroups(groups)
+//
+//	page, err := qdrant.Scroll[rag.Chunk](ctx, client, "chunks",
+//	    store.Neighbours(rag.Chunks(hits), 1).Build())   // chunk_index ± 1
+//	passages := rag.Passages(rag.Payloads(page.Points))
+type Store struct {
+	Table       string
+	VectorField string    // Vector column, default "embedding"; the Qdrant unnamed vector unless NamedVectors()
+	Custom      xb.Custom // nil: SQL
+	Overfetch   int       // SQL Search(): chunks fetched per returned chunk, default 4
+}
+
+// NewStore creates a Store, custom is nil for SQL or a QdrantCustom / MemoryVectorCustom
+func NewStore(table string, custom xb.Custom) *Store {
+	if table == "" {
+		panic("rag.NewStore() requires a table or collection name")
+	}
+	return &Store{Table: table, VectorField: "embedding", Custom: custom, Overfetch: 4}
+}
+
+func (s *Store) qdrant() (*xb.QdrantCustom, bool) {
+	c, ok := s.Custom.(*xb.QdrantCustom)
+	return c, ok
+}
+
+func (s *Store) of() *xb.BuilderX {
+	x := xb.Of(s.Table)
+	if s.Custom != nil {
+		x.Custom(s.Custom)
+	}
+	return x
+}
+
+// Insert one chunk with InsertBuilder
+func (s *Store) Insert(c Chunk) *xb.Built {
+	if c.DocID == "" {
+		panic("rag: Chunk.DocID is required")
+	}
+	id := c.ID
+	if id == nil {
+		id = ChunkID(c.DocID, c.ChunkIndex)
+	}
+	vectorKey := s.VectorField
+	if q, ok := s.qdrant(); ok && !q.NamedVectors {
+		vectorKey = "vector"
+	}
+	index := c.ChunkIndex // Pointer: Set() skips a plain 0
+
+	return s.of().Insert(func(ib *xb.InsertBuilder) {
+		ib.Set("id", id).
+			Set("doc_id", c.DocID).
+			Set("chunk_index", &index).
+			Set("text", c.Text)
+		if c.Vector != nil {
+			ib.Set(vectorKey, c.Vector)
+		}
+		keys := make([]string, 0, len(c.Metadata))
+		for k := range c.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ib.Set(k, c.Metadata[k])
+		}
+	}).Build()
+}
+
+// InsertAll one insert Built per chunk
+func (s *Store) InsertAll(chunks []Chunk) []*xb.Built {
+	builts := make([]*xb.Built, len(chunks))
+	for i, c := range chunks {
+		builts[i] = s.Insert(c)
+	}
+	return builts
+}
+
+// UpsertJSON one Qdrant upsert body for all chunks, and its endpoint (PUT /points)
+func (s *Store) UpsertJSON(chunks []Chunk) (string, xb.QdrantEndpoint, error) {
+	if _, ok := s.qdrant(); !ok {
+		return "", xb.QdrantEndpoint{}, fmt.Errorf("rag: UpsertJSON() requires a QdrantCustom, got %T", s.Custom)
+	}
+	if len(chunks) == 0 {
+		return "", xb.QdrantEndpoint{}, fmt.Errorf("rag: no chunks to upsert")
+	}
+
+	var points []json.RawMessage
+	var ep xb.QdrantEndpoint
+	for i, built := range s.InsertAll(chunks) {
+		body, err := built.JsonOfInsert()
+		if err != nil {
+			return "", xb.QdrantEndpoint{}, fmt.Errorf("rag: chunk %d: %w", i, err)
+		}
+		var req struct {
+			Points []json.RawMessage `json:"points"`
+		}
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			return "", xb.QdrantEndpoint{}, fmt.Errorf("rag: chunk %d: %w", i, err)
+		}
+		points = append(points, req.Points...)
+		if ep, err = built.QdrantEndpoint(); err != nil {
+			return "", xb.QdrantEndpoint{}, err
+		}
+	}
+
+	bytes, err := json.Marshal(map[string]interface{}{"points": points})
+	if err != nil {
+		return "", xb.QdrantEndpoint{}, fmt.Errorf("rag: failed to marshal upsert request: %w", err)
+	}
+	return string(bytes), ep, nil
+}
+
+// InsertSQL one multi-row INSERT for all chunks
+// The chunks must write the same columns: same Metadata keys, all or no vectors
+func (s *Store) InsertSQL(chunks []Chunk) (string, []interface{}, error) {
+	if len(chunks) == 0 {
+		return "", nil, fmt.Errorf("rag: no chunks to insert")
+	}
+
+	var head, row string
+	var args []interface{}
+	for i, built := range s.InsertAll(chunks) {
+		sql, vs := built.SqlOfInsert()
+		idx := strings.Index(sql, " VALUES ")
+		if idx < 0 {
+			return "", nil, fmt.Errorf("rag: chunk %d: unexpected insert %q", i, sql)
+		}
+		if i == 0 {
+			head, row = sql[:idx], sql[idx+len(" VALUES "):]
+		} else if sql[:idx] != head {
+			return "", nil, fmt.Errorf("rag: chunk %d writes %s, chunk 0 writes %s", i, sql[:idx], head)
+		}
+		args = append(args, vs...)
+	}
+
+	rows := make([]string, len(chunks))
+	for i := range rows {
+		rows[i] = row
+	}
+	return head + " VALUES " + strings.Join(rows, ", "), args, nil
+}
+
+// Search retrieves the best docs chunks of at most chunksPerDoc each
+//   - Qdrant: GroupBy("doc_id") search groups, decode with qdrant.Groups / xb.DecodeQdrantGroups, then FromGroups()
+//   - SQL / memory: fetches docs * chunksPerDoc * Overfetch chunks, group them with GroupByDoc()
+func (s *Store) Search(query xb.Vector, docs, chunksPerDoc int) *xb.BuilderX {
+	if docs < 1 || chunksPerDoc < 1 {
+		panic(fmt.Sprintf("rag: Search() docs and chunksPerDoc must be >= 1, got: %d, %d", docs, chunksPerDoc))
+	}
+	if _, ok := s.qdrant(); ok {
+		return s.of().
+			GroupBy("doc_id").
+			VectorSearch(s.VectorField, query, docs).
+			QdrantX(func(qx *xb.QdrantXBuilder) {
+				qx.GroupSize(chunksPerDoc)
+			})
+	}
+	overfetch := max(s.Overfetch, 1)
+	return s.of().VectorSearch(s.VectorField, query, docs*chunksPerDoc*overfetch)
+}
+
+// DocHits the retrieved chunks of one document, best first
+type DocHits struct {
+	DocID string
+	Hits  []xb.ScoredPoint[Chunk]
+}
+
+// GroupByDoc groups hits (best first) by doc_id: at most docs documents, chunksPerDoc chunks each
+func GroupByDoc(hits []xb.ScoredPoint[Chunk], docs, chunksPerDoc int) []DocHits {
+	var out []DocHits
+	index := map[string]int{}
+	for _, h := range hits {
+		i, ok := index[h.Payload.DocID]
+		if !ok {
+			if len(out) == docs {
+				continue
+			}
+			i = len(out)
+			index[h.Payload.DocID] = i
+			out = append(out, DocHits{DocID: h.Payload.DocID})
+		}
+		if len(out[i].Hits) < chunksPerDoc {
+			out[i].Hits = append(out[i].Hits, h)
+		}
+	}
+	return out
+}
+
+// FromGroups converts Qdrant search groups
+func FromGroups(groups []xb.QdrantGroup[Chunk]) []DocHits {
+	out := make([]DocHits, len(groups))
+	for i, g := range groups {
+		out[i] = DocHits{DocID: fmt.Sprint(g.ID), Hits: g.Hits}
+	}
+	return out
+}
+
+// Chunks the hit chunks of all documents
+func Chunks(docs []DocHits) []Chunk {
+	var out []Chunk
+	for _, d := range docs {
+		out = append(out, Payloads(d.Hits)...)
+	}
+	return out
+}
+
+// Payloads the chunks of decoded points
+func Payloads(points []xb.ScoredPoint[Chunk]) []Chunk {
+	out := make([]Chunk, len(points))
+	for i, p := range points {
+		out[i] = p.Payload
+	}
+	return out
+}
+
+// Neighbours fetches the chunks around hits: chunk_index ± n of the same doc_id
+// SQL is sorted by doc_id, chunk_index; Qdrant is a scroll, Passages() sorts
+func (s *Store) Neighbours(hits []Chunk, n int) *xb.BuilderX {
+	if n < 0 {
+		panic(fmt.Sprintf("rag: Neighbours() n must be >= 0, got: %d", n))
+	}
+	if len(hits) == 0 {
+		panic("rag: Neighbours() requires at least one hit")
+	}
+
+	var docs []string
+	windows := map[string]map[int]bool{}
+	for _, h := range hits {
+		w, ok := windows[h.DocID]
+		if !ok {
+			w = map[int]bool{}
+			windows[h.DocID] = w
+			docs = append(docs, h.DocID)
+		}
+		for i := max(h.ChunkIndex-n, 0); i <= h.ChunkIndex+n; i++ {
+			w[i] = true
+		}
+	}
+
+	total := 0
+	match := func(cb *xb.CondBuilder, doc string) {
+		indexes := make([]int, 0, len(windows[doc]))
+		for i := range windows[doc] {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		total += len(indexes)
+		values := make([]interface{}, len(indexes))
+		for i := range indexes {
+			values[i] = &indexes[i] // Pointers: In() skips a plain 0
+		}
+		cb.Eq("doc_id", doc).In("chunk_index", values...)
+	}
+
+	var x *xb.BuilderX
+	if q, ok := s.qdrant(); ok {
+		scroll := xb.NewQdrantBuilder().Scroll()
+		if q.NamedVectors {
+			scroll.NamedVectors()
+		}
+		x = xb.Of(s.Table).Custom(scroll.Build())
+	} else {
+		x = s.of().Sort("doc_id", xb.ASC).Sort("chunk_index", xb.ASC)
+	}
+
+	if len(docs) == 1 {
+		match(&x.CondBuilder, docs[0])
+	} else {
+		x.Or(func(cb *xb.CondBuilder) {
+			for i, doc := range docs {
+				if i > 0 {
+					cb.OR()
+				}
+				cb.And(func(and *xb.CondBuilder) { match(and, doc) })
+			}
+		})
+	}
+
+	if _, ok := s.qdrant(); ok {
+		x.QdrantX(func(qx *xb.QdrantXBuilder) { qx.X("limit", total) })
+	}
+	return x
+}
+
+// Passage consecutive chunks of one document, joined into one context window
+type Passage struct {
+	DocID string
+	First int // First chunk_index
+	Last  int // Last chunk_index
+	Text  string
+}
+
+// Passages sorts chunks by doc_id, chunk_index and joins consecutive ones with "\n"
+// Documents keep the order of their first chunk; duplicates are dropped
+func Passages(chunks []Chunk) []Passage {
+	var docs []string
+	byDoc := map[string][]Chunk{}
+	for _, c := range chunks {
+		if _, ok := byDoc[c.DocID]; !ok {
+			docs = append(docs, c.DocID)
+		}
+		byDoc[c.DocID] = append(byDoc[c.DocID], c)
+	}
+
+	var out []Passage
+	for _, doc := range docs {
+		cs := byDoc[doc]
+		sort.SliceStable(cs, func(i, j int) bool { return cs[i].ChunkIndex < cs[j].ChunkIndex })
+		var texts []string
+		for i, c := range cs {
+			switch {
+			case i > 0 && c.ChunkIndex == cs[i-1].ChunkIndex:
+				continue
+			case i > 0 && c.ChunkIndex == cs[i-1].ChunkIndex+1:
+				texts = append(texts, c.Text)
+				out[len(out)-1].Last = c.ChunkIndex
+			default:
+				if i > 0 {
+					out[len(out)-1].Text = strings.Join(texts, "\n")
+				}
+				texts = []string{c.Text}
+				out = append(out, Passage{DocID: doc, First: c.ChunkIndex, Last: c.ChunkIndex})
+			}
+		}
+		out[len(out)-1].Text = strings.Join(texts, "\n")
+	}
+	return out
+}
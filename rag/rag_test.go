@@ -0,0 +1,187 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fndome/xb"
+	"github.com/fndome/xb/qdrant"
+)
+
+// corpus: doc a is about retries, doc b about bread, doc c mixes both
+func corpus() []Chunk {
+	return []Chunk{
+		{DocID: "a", ChunkIndex: 0, Text: "a0", Vector: xb.Vector{1, 0, 0}},
+		{DocID: "a", ChunkIndex: 1, Text: "a1", Vector: xb.Vector{0.9, 0.1, 0}},
+		{DocID: "a", ChunkIndex: 2, Text: "a2", Vector: xb.Vector{0.8, 0.2, 0}},
+		{DocID: "a", ChunkIndex: 3, Text: "a3", Vector: xb.Vector{0, 0, 1}},
+		{DocID: "b", ChunkIndex: 0, Text: "b0", Vector: xb.Vector{0, 1, 0}},
+		{DocID: "b", ChunkIndex: 1, Text: "b1", Vector: xb.Vector{0, 0.9, 0.1}},
+		{DocID: "c", ChunkIndex: 0, Text: "c0", Vector: xb.Vector{0.1, 0.9, 0}},
+		{DocID: "c", ChunkIndex: 1, Text: "c1", Vector: xb.Vector{0.7, 0.3, 0}},
+	}
+}
+
+func texts(chunks []Chunk) string {
+	var out []string
+	for _, c := range chunks {
+		out = append(out, c.Text)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestChunkID(t *testing.T) {
+	id := ChunkID("a", 0)
+	if _, err := xb.ParsePointID(id); err != nil {
+		t.Fatalf("ChunkID %s is not a point id: %v", id, err)
+	}
+	if id != ChunkID("a", 0) || id == ChunkID("a", 1) {
+		t.Errorf("ChunkID must be deterministic and unique")
+	}
+}
+
+func TestStore_SQL(t *testing.T) {
+	store := NewStore("chunks", nil)
+	chunks := []Chunk{
+		{ID: 1, DocID: "a", ChunkIndex: 0, Text: "t0", Vector: xb.Vector{1, 0}, Metadata: map[string]interface{}{"tenant_id": 7}},
+		{ID: 2, DocID: "a", ChunkIndex: 1, Text: "t1", Vector: xb.Vector{0, 1}, Metadata: map[string]interface{}{"tenant_id": 7}},
+	}
+	sql, args, err := store.InsertSQL(chunks)
+	if err != nil {
+		t.Fatalf("InsertSQL failed: %v", err)
+	}
+	want := "INSERT INTO chunks (id, doc_id, chunk_index, text, embedding, tenant_id) VALUES ( ?,  ?,  ?,  ?,  ?,  ?), ( ?,  ?,  ?,  ?,  ?,  ?)"
+	if sql != want || len(args) != 12 || args[2] != 0 || args[8] != 1 {
+		t.Errorf("sql = %s\nargs = %v", sql, args)
+	}
+
+	chunks[1].Metadata = nil
+	if _, _, err := store.InsertSQL(chunks); err == nil {
+		t.Error("different columns should fail")
+	}
+
+	sql, _ = store.Search(xb.Vector{1, 0}, 3, 2).Build().SqlOfVectorSearch()
+	if !strings.HasSuffix(sql, "ORDER BY distance LIMIT 24") {
+		t.Errorf("search sql = %s", sql)
+	}
+
+	sql, args, _ = store.Neighbours([]Chunk{{DocID: "a", ChunkIndex: 0}, {DocID: "b", ChunkIndex: 5}}, 1).Build().SqlOfSelect()
+	want = "SELECT * FROM chunks WHERE ((doc_id = ? AND chunk_index IN (0, 1)) OR (doc_id = ? AND chunk_index IN (4, 5, 6))) ORDER BY doc_id ASC, chunk_index ASC"
+	if sql != want || len(args) != 2 {
+		t.Errorf("sql = %s\nargs = %v", sql, args)
+	}
+}
+
+func TestStore_Memory(t *testing.T) {
+	mem := xb.NewMemoryVectorBuilder().Build()
+	store := NewStore("chunks", mem)
+	for _, built := range store.InsertAll(corpus()) {
+		if _, err := built.MemoryOfExec(); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	hits, err := xb.MemorySearch[Chunk](store.Search(xb.Vector{1, 0, 0}, 2, 2).Build())
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	docs := GroupByDoc(hits, 2, 2)
+	if len(docs) != 2 || docs[0].DocID != "a" || docs[1].DocID != "c" || texts(Chunks(docs)) != "a0 a1 c1 c0" {
+		t.Fatalf("docs = %+v", docs)
+	}
+
+	around, err := xb.MemorySearch[Chunk](store.Neighbours(Chunks(docs), 1).Build())
+	if err != nil {
+		t.Fatalf("neighbours failed: %v", err)
+	}
+	if got := texts(Payloads(around)); got != "a0 a1 a2 c0 c1" {
+		t.Errorf("neighbours = %s", got)
+	}
+	passages := Passages(Payloads(around))
+	if len(passages) != 2 || passages[0].Text != "a0\na1\na2" || passages[0].Last != 2 || passages[1].DocID != "c" {
+		t.Errorf("passages = %+v", passages)
+	}
+}
+
+func TestStore_Qdrant(t *testing.T) {
+	fake := qdrant.NewFakeServer()
+	t.Cleanup(fake.Close)
+	fake.CreateCollection("chunks", 3, xb.CosineDistance)
+	client := fake.NewClient()
+	ctx := context.Background()
+
+	store := NewStore("chunks", xb.NewQdrantBuilder().Build())
+	body, ep, err := store.UpsertJSON(corpus())
+	if err != nil {
+		t.Fatalf("UpsertJSON failed: %v", err)
+	}
+	if ep.Path != "/points" || strings.Count(body, `"doc_id"`) != 8 {
+		t.Fatalf("%s %s", ep, body)
+	}
+	if _, err := client.Do(ctx, "chunks", ep, body); err != nil {
+		t.Fatalf("upsert failed: %v", err)
+	}
+
+	groups, err := qdrant.Groups[Chunk](ctx, client, "chunks", store.Search(xb.Vector{1, 0, 0}, 2, 2).Build())
+	if err != nil {
+		t.Fatalf("Groups failed: %v", err)
+	}
+	docs := FromGroups(groups)
+	if len(docs) != 2 || docs[0].DocID != "a" || texts(Chunks(docs)) != "a0 a1 c1 c0" {
+		t.Fatalf("docs = %+v", docs)
+	}
+
+	page, err := qdrant.Scroll[Chunk](ctx, client, "chunks", store.Neighbours(Chunks(docs)[:1], 1).Build())
+	if err != nil {
+		t.Fatalf("Scroll failed: %v", err)
+	}
+	passages := Passages(Payloads(page.Points))
+	if len(passages) != 1 || passages[0].Text != "a0\na1" {
+		t.Errorf("passages = %+v", passages)
+	}
+
+	if _, _, err := NewStore("chunks", nil).UpsertJSON(corpus()); err == nil {
+		t.Error("UpsertJSON without QdrantCustom should fail")
+	}
+}
+
+func TestPassages(t *testing.T) {
+	got := Passages([]Chunk{
+		{DocID: "b", ChunkIndex: 4, Text: "b4"},
+		{DocID: "a", ChunkIndex: 2, Text: "a2"},
+		{DocID: "b", ChunkIndex: 2, Text: "b2"},
+		{DocID: "b", ChunkIndex: 3, Text: "b3"},
+		{DocID: "b", ChunkIndex: 3, Text: "b3"},
+		{DocID: "a", ChunkIndex: 5, Text: "a5"},
+	})
+	want := []Passage{
+		{DocID: "b", First: 2, Last: 4, Text: "b2\nb3\nb4"},
+		{DocID: "a", First: 2, Last: 2, Text: "a2"},
+		{DocID: "a", First: 5, Last: 5, Text: "a5"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("passages = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
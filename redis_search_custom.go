@@ -0,0 +1,587 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// RedisSearchBuilder: Builder Pattern Configuration Builder
+// ============================================================================
+
+// RedisSearchBuilder RediSearch (Redis Stack) configuration builder
+// Uses Builder pattern to construct RedisSearchCustom configuration
+type RedisSearchBuilder struct {
+	custom *RedisSearchCustom
+}
+
+// NewRedisSearchBuilder creates a RediSearch configuration builder
+//
+// Example:
+//
+//	args, err := xb.Of("idx:products").
+//	    Custom(
+//	        xb.NewRedisSearchBuilder().
+//	            TextFields("title").
+//	            Build(),
+//	    ).
+//	    In("brand", "apple", "sony").
+//	    Gte("price", 10).
+//	    Lte("price", 20).
+//	    VectorSearch("vec", queryVector, 10).
+//	    Build().
+//	    RedisArgsOfSelect()
+//
+//	// FT.SEARCH idx:products "(@brand:{apple|sony} @price:[10 20])=>[KNN 10 @vec $BLOB AS score]"
+//	//     PARAMS 2 BLOB <bytes> SORTBY score ASC LIMIT 0 10 DIALECT 2
+//	rdb.Do(ctx, args...)
+func NewRedisSearchBuilder() *RedisSearchBuilder {
+	return &RedisSearchBuilder{
+		custom: newRedisSearchCustom(),
+	}
+}
+
+// Index sets the index name (defaults to the table name passed to Of())
+func (rb *RedisSearchBuilder) Index(index string) *RedisSearchBuilder {
+	rb.custom.Index = index
+	return rb
+}
+
+// TextFields declares TEXT fields
+// Strings on other fields are treated as TAG values: @field:{value}
+func (rb *RedisSearchBuilder) TextFields(fields ...string) *RedisSearchBuilder {
+	for _, f := range fields {
+		if f != "" {
+			rb.custom.TextFields[f] = true
+		}
+	}
+	return rb
+}
+
+// ScoreField sets the alias of the KNN distance (default "score")
+func (rb *RedisSearchBuilder) ScoreField(name string) *RedisSearchBuilder {
+	if name == "" {
+		panic("ScoreField() requires a non-empty name")
+	}
+	rb.custom.ScoreField = name
+	return rb
+}
+
+// Dialect sets the query dialect (must be >= 2, KNN requires DIALECT 2)
+func (rb *RedisSearchBuilder) Dialect(dialect int) *RedisSearchBuilder {
+	if dialect < 2 {
+		panic(fmt.Sprintf("Dialect must be >= 2, got: %d", dialect))
+	}
+	rb.custom.Dialect = dialect
+	return rb
+}
+
+// Aggregate switches the output from FT.SEARCH to FT.AGGREGATE
+func (rb *RedisSearchBuilder) Aggregate() *RedisSearchBuilder {
+	rb.custom.UseAggregate = true
+	return rb
+}
+
+// Build constructs and returns RedisSearchCustom configuration
+func (rb *RedisSearchBuilder) Build() *RedisSearchCustom {
+	return rb.custom
+}
+
+// ============================================================================
+// RedisSearchCustom: RediSearch-Specific Configuration
+// ============================================================================
+
+// RedisSearchCustom RediSearch configuration implementation
+//
+// Implements Custom interface, Generate() returns the argument list ([]interface{})
+// of FT.SEARCH / FT.AGGREGATE, ready for redis clients' Do(ctx, args...)
+type RedisSearchCustom struct {
+	Index        string          // Index name (defaults to Built.OrFromSql)
+	TextFields   map[string]bool // TEXT fields (others are TAG/NUMERIC)
+	ScoreField   string          // KNN distance alias
+	Dialect      int             // DIALECT (default 2)
+	UseAggregate bool            // FT.AGGREGATE instead of FT.SEARCH
+}
+
+// newRedisSearchCustom internal function: creates RediSearch Custom (default configuration)
+func newRedisSearchCustom() *RedisSearchCustom {
+	return &RedisSearchCustom{
+		TextFields: map[string]bool{},
+		ScoreField: "score",
+		Dialect:    2,
+	}
+}
+
+// Generate implements Custom interface
+// ⭐ Returns []interface{}: "FT.SEARCH", index, query, options...
+func (c *RedisSearchCustom) Generate(built *Built) (interface{}, error) {
+	if built.Inserts != nil || built.Updates != nil || built.Delete {
+		return nil, fmt.Errorf("RediSearch Custom only supports select, use HSET/JSON.SET for writes")
+	}
+	return c.buildArgs(built)
+}
+
+// RedisArgsOfSelect generates FT.SEARCH / FT.AGGREGATE arguments
+//
+// Example:
+//
+//	args, err := built.RedisArgsOfSelect()
+//	res, err := rdb.Do(ctx, args...).Result()
+func (built *Built) RedisArgsOfSelect() ([]interface{}, error) {
+	if built.Custom == nil {
+		return nil, fmt.Errorf("Custom is nil, use Custom(xb.NewRedisSearchBuilder().Build())")
+	}
+	result, err := built.Custom.Generate(built)
+	if err != nil {
+		return nil, err
+	}
+	if args, ok := result.([]interface{}); ok {
+		return args, nil
+	}
+	return nil, fmt.Errorf("unexpected result type: %T", result)
+}
+
+// RedisVectorBlob packs a vector as a little-endian float32 blob
+// The format expected by RediSearch FLOAT32 vector fields (HSET and PARAMS)
+func RedisVectorBlob(v Vector) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func (c *RedisSearchCustom) buildArgs(built *Built) ([]interface{}, error) {
+	index := c.Index
+	if index == "" {
+		index = strings.TrimSpace(built.OrFromSql)
+	}
+	if index == "" {
+		return nil, fmt.Errorf("RediSearch index is empty, use Of(\"idx\") or Index()")
+	}
+
+	params := []interface{}{}
+	query, err := c.toQuery(built.Conds, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	vectorBb := findVectorSearchBb(built.Conds)
+	if vectorBb != nil {
+		vp := vectorBb.Value.(VectorSearchParams)
+		if vp.QueryMultiVector != nil {
+			return nil, fmt.Errorf("RediSearch does not support MultiVectorSearch(%q)", vectorBb.Key)
+		}
+		if vp.QuerySparseVector != nil {
+			return nil, fmt.Errorf("RediSearch does not support SparseVectorSearch(%q)", vectorBb.Key)
+		}
+		name := "BLOB"
+		params = append(params, name, RedisVectorBlob(vp.QueryVector))
+		if query != "*" {
+			query = "(" + query + ")"
+		}
+		query += fmt.Sprintf("=>[KNN %d @%s $%s AS %s]", vp.fetchK(), vectorBb.Key, name, c.ScoreField)
+	}
+
+	cmd := "FT.SEARCH"
+	if c.UseAggregate {
+		cmd = "FT.AGGREGATE"
+	}
+	args := []interface{}{cmd, index, query}
+
+	if c.UseAggregate {
+		args = c.appendAggregateOptions(args, built, vectorBb)
+	} else {
+		args = c.appendSearchOptions(args, built, vectorBb)
+	}
+
+	if len(params) > 0 {
+		args = append(args, "PARAMS", strconv.Itoa(len(params)))
+		args = append(args, params...)
+	}
+	args = append(args, "DIALECT", strconv.Itoa(c.Dialect))
+	return args, nil
+}
+
+func (c *RedisSearchCustom) appendSearchOptions(args []interface{}, built *Built, vectorBb *Bb) []interface{} {
+	if len(built.ResultKeys) > 0 {
+		keys := append([]string(nil), built.ResultKeys...)
+		if vectorBb != nil {
+			keys = append(keys, c.ScoreField)
+		}
+		args = append(args, "RETURN", strconv.Itoa(len(keys)))
+		for _, k := range keys {
+			args = append(args, k)
+		}
+	}
+
+	// FT.SEARCH supports one SORTBY field only
+	if len(built.Sorts) > 0 {
+		sort := built.Sorts[0]
+		args = append(args, "SORTBY", sort.orderBy)
+		if sort.direction != "" {
+			args = append(args, strings.ToUpper(sort.direction))
+		}
+	} else if vectorBb != nil {
+		args = append(args, "SORTBY", c.ScoreField, "ASC")
+	}
+
+	return c.appendLimit(args, built, vectorBb)
+}
+
+func (c *RedisSearchCustom) appendAggregateOptions(args []interface{}, built *Built, vectorBb *Bb) []interface{} {
+	if len(built.ResultKeys) > 0 {
+		args = append(args, "LOAD", strconv.Itoa(len(built.ResultKeys)))
+		for _, k := range built.ResultKeys {
+			args = append(args, "@"+k)
+		}
+	}
+
+	if len(built.GroupBys) > 0 {
+		args = append(args, "GROUPBY", strconv.Itoa(len(built.GroupBys)))
+		for _, g := range built.GroupBys {
+			args = append(args, "@"+g)
+		}
+		args = append(args, "REDUCE", "COUNT", "0", "AS", "count")
+	}
+
+	if len(built.Sorts) > 0 {
+		sortArgs := []interface{}{}
+		for _, sort := range built.Sorts {
+			sortArgs = append(sortArgs, "@"+sort.orderBy)
+			if sort.direction != "" {
+				sortArgs = append(sortArgs, strings.ToUpper(sort.direction))
+			}
+		}
+		args = append(args, "SORTBY", strconv.Itoa(len(sortArgs)))
+		args = append(args, sortArgs...)
+	} else if vectorBb != nil {
+		args = append(args, "SORTBY", "2", "@"+c.ScoreField, "ASC")
+	}
+
+	return c.appendLimit(args, built, vectorBb)
+}
+
+func (c *RedisSearchCustom) appendLimit(args []interface{}, built *Built, vectorBb *Bb) []interface{} {
+	offset, num := 0, 0
+	switch {
+	case built.PageCondition != nil && built.PageCondition.Rows > 0:
+		page := built.PageCondition.Page
+		if page < 1 {
+			page = 1
+		}
+		num = int(built.PageCondition.Rows)
+		offset = int(page-1) * num
+	case built.LimitValue > 0:
+		num = built.LimitValue
+		offset = built.OffsetValue
+	case vectorBb != nil:
+		num = vectorBb.Value.(VectorSearchParams).fetchK()
+	}
+	if num == 0 {
+		return args
+	}
+	return append(args, "LIMIT", strconv.Itoa(offset), strconv.Itoa(num))
+}
+
+// ============================================================================
+// Query String Translation
+// ============================================================================
+
+// toQuery translates the Bb tree into RediSearch query syntax
+// Conditions are AND-ed (space), OR() groups are joined by |
+func (c *RedisSearchCustom) toQuery(bbs []Bb, params *[]interface{}) (string, error) {
+	q, err := c.toGroupsQuery(bbs, params)
+	if err != nil {
+		return "", err
+	}
+	if q == "" {
+		return "*", nil
+	}
+	return q, nil
+}
+
+func (c *RedisSearchCustom) toGroupsQuery(bbs []Bb, params *[]interface{}) (string, error) {
+	groups := splitOrGroups(bbs)
+	parts := []string{}
+	for _, group := range groups {
+		items, err := c.toGroupItems(group, params)
+		if err != nil {
+			return "", err
+		}
+		if len(items) == 0 {
+			continue
+		}
+		part := strings.Join(items, " ")
+		if len(groups) > 1 && len(items) > 1 {
+			part = "(" + part + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " | "), nil
+}
+
+// redisRange collects numeric bounds of one field, so Gte+Lte become @f:[lo hi]
+type redisRange struct {
+	key    string
+	lo, hi string
+}
+
+func (c *RedisSearchCustom) toGroupItems(group []Bb, params *[]interface{}) ([]string, error) {
+	items := []string{}
+	ranges := map[string]*redisRange{}
+	rangeAt := map[string]int{}
+
+	addRange := func(key, lo, hi string) {
+		r, ok := ranges[key]
+		if !ok {
+			r = &redisRange{key: key, lo: "-inf", hi: "+inf"}
+			ranges[key] = r
+			rangeAt[key] = len(items)
+			items = append(items, "")
+		}
+		if lo != "" {
+			r.lo = lo
+		}
+		if hi != "" {
+			r.hi = hi
+		}
+	}
+
+	for _, bb := range group {
+		// KNN is appended after the filter, Qdrant-only knobs don't apply
+		if bb.Op == VECTOR_SEARCH || isQdrantSpecificOp(bb.Op) {
+			continue
+		}
+
+		switch bb.Op {
+		case AND, OR:
+			sub, err := c.toGroupsQuery(bb.Subs, params)
+			if err != nil {
+				return nil, err
+			}
+			if sub != "" {
+				items = append(items, "("+sub+")")
+			}
+
+		case EQ, NE:
+			item, err := c.eqItem(bb)
+			if err != nil {
+				return nil, err
+			}
+			if bb.Op == NE {
+				item = "-" + item
+			}
+			items = append(items, item)
+
+		case GT, GTE, LT, LTE:
+			n, ok := redisNumber(bb.Value)
+			if !ok {
+				return nil, fmt.Errorf("RediSearch range on %s expects a number, got %T", bb.Key, bb.Value)
+			}
+			switch bb.Op {
+			case GT:
+				addRange(bb.Key, "("+n, "")
+			case GTE:
+				addRange(bb.Key, n, "")
+			case LT:
+				addRange(bb.Key, "", "("+n)
+			case LTE:
+				addRange(bb.Key, "", n)
+			}
+
+		case IN, NIN:
+			item, err := c.inItem(bb)
+			if err != nil {
+				return nil, err
+			}
+			if item == "" {
+				continue
+			}
+			if bb.Op == NIN {
+				item = "-" + item
+			}
+			items = append(items, item)
+
+		case LIKE, NOT_LIKE:
+			item := c.likeItem(bb)
+			if bb.Op == NOT_LIKE {
+				item = "-" + item
+			}
+			items = append(items, item)
+
+		case IS_NULL:
+			items = append(items, "ismissing(@"+bb.Key+")")
+		case NON_NULL:
+			items = append(items, "-ismissing(@"+bb.Key+")")
+
+		case VECTOR_DISTANCE_FILTER:
+			item, err := c.vectorRangeItem(bb, params)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+
+		case XX:
+			// Raw RediSearch fragment, used verbatim
+			if bb.Key != "" {
+				items = append(items, bb.Key)
+			}
+
+		default:
+			return nil, fmt.Errorf("operator %q on %s is not supported by RediSearch", bb.Op, bb.Key)
+		}
+	}
+
+	for key, r := range ranges {
+		items[rangeAt[key]] = "@" + r.key + ":[" + r.lo + " " + r.hi + "]"
+	}
+	return items, nil
+}
+
+func (c *RedisSearchCustom) eqItem(bb Bb) (string, error) {
+	if n, ok := redisNumber(bb.Value); ok {
+		return "@" + bb.Key + ":[" + n + " " + n + "]", nil
+	}
+	var s string
+	switch v := bb.Value.(type) {
+	case string:
+		s = v
+	case bool:
+		s = strconv.FormatBool(v)
+	default:
+		return "", fmt.Errorf("RediSearch cannot match %s against %T", bb.Key, bb.Value)
+	}
+	if c.TextFields[bb.Key] {
+		return "@" + bb.Key + ":\"" + escapeRedisPhrase(s) + "\"", nil
+	}
+	return "@" + bb.Key + ":{" + escapeRedisTag(s) + "}", nil
+}
+
+func (c *RedisSearchCustom) inItem(bb Bb) (string, error) {
+	arr, ok := bb.Value.(*[]string)
+	if !ok || arr == nil || len(*arr) == 0 {
+		return "", nil
+	}
+
+	tags := []string{}
+	nums := []string{}
+	for _, s := range *arr {
+		if len(s) >= 2 && strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") {
+			tags = append(tags, s[1:len(s)-1])
+		} else {
+			nums = append(nums, s)
+		}
+	}
+
+	if len(tags) > 0 && len(nums) > 0 {
+		return "", fmt.Errorf("RediSearch IN on %s mixes strings and numbers", bb.Key)
+	}
+
+	if len(tags) > 0 {
+		if c.TextFields[bb.Key] {
+			phrases := make([]string, len(tags))
+			for i, t := range tags {
+				phrases[i] = "\"" + escapeRedisPhrase(t) + "\""
+			}
+			return "@" + bb.Key + ":(" + strings.Join(phrases, "|") + ")", nil
+		}
+		escaped := make([]string, len(tags))
+		for i, t := range tags {
+			escaped[i] = escapeRedisTag(t)
+		}
+		return "@" + bb.Key + ":{" + strings.Join(escaped, "|") + "}", nil
+	}
+
+	ors := make([]string, len(nums))
+	for i, n := range nums {
+		ors[i] = "@" + bb.Key + ":[" + n + " " + n + "]"
+	}
+	if len(ors) == 1 {
+		return ors[0], nil
+	}
+	return "(" + strings.Join(ors, "|") + ")", nil
+}
+
+// likeItem maps SQL LIKE patterns to RediSearch prefix/infix wildcards
+// %v% => *v*, v% => v*
+func (c *RedisSearchCustom) likeItem(bb Bb) string {
+	pattern, _ := bb.Value.(string)
+	prefix := strings.HasPrefix(pattern, "%")
+	suffix := strings.HasSuffix(pattern, "%")
+	word := strings.Trim(pattern, "%")
+
+	term := escapeRedisTag(word)
+	if prefix {
+		term = "*" + term
+	}
+	if suffix {
+		term = term + "*"
+	}
+
+	if c.TextFields[bb.Key] {
+		return "@" + bb.Key + ":" + term
+	}
+	return "@" + bb.Key + ":{" + term + "}"
+}
+
+// vectorRangeItem maps VectorDistanceFilter(<, <=) to VECTOR_RANGE
+func (c *RedisSearchCustom) vectorRangeItem(bb Bb, params *[]interface{}) (string, error) {
+	p := bb.Value.(VectorDistanceFilterParams)
+	if p.Operator != "<" && p.Operator != "<=" {
+		return "", fmt.Errorf("RediSearch VECTOR_RANGE only supports < and <=, got %s", p.Operator)
+	}
+	name := fmt.Sprintf("BLOB_%d", len(*params)/2)
+	*params = append(*params, name, RedisVectorBlob(p.QueryVector))
+	radius := strconv.FormatFloat(float64(p.Threshold), 'f', -1, 32)
+	return "@" + bb.Key + ":[VECTOR_RANGE " + radius + " $" + name + "]", nil
+}
+
+func redisNumber(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", val), true
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// escapeRedisTag escapes punctuation and spaces inside {tag} values and bare terms
+func escapeRedisTag(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(",.<>{}[]\"':;!@#$%^&*()-+=~|/\\ ", r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// escapeRedisPhrase escapes a "quoted phrase"
+func escapeRedisPhrase(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
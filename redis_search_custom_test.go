@@ -0,0 +1,154 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xb
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRedisSearchCustom_ImplementsCustomInterface(t *testing.T) {
+	var _ Custom = (*RedisSearchCustom)(nil)
+}
+
+func TestRedisSearch_KNNWithFilters(t *testing.T) {
+	vec := Vector{0.1, 0.2}
+
+	args, err := Of("idx:products").
+		Custom(NewRedisSearchBuilder().Build()).
+		In("brand", "apple", "sony").
+		Gte("price", 10).
+		Lte("price", 20).
+		Ne("status", "deleted").
+		VectorSearch("vec", vec, 10).
+		Build().
+		RedisArgsOfSelect()
+	if err != nil {
+		t.Fatalf("RedisArgsOfSelect failed: %v", err)
+	}
+
+	t.Logf("args: %v", args[:3])
+
+	wantQuery := "(@brand:{apple|sony} @price:[10 20] -@status:{deleted})=>[KNN 10 @vec $BLOB AS score]"
+	if args[0] != "FT.SEARCH" || args[1] != "idx:products" || args[2] != wantQuery {
+		t.Fatalf("unexpected head: %v", args[:3])
+	}
+
+	want := []interface{}{"SORTBY", "score", "ASC", "LIMIT", "0", "10", "PARAMS", "2", "BLOB"}
+	if !reflect.DeepEqual(args[3:12], want) {
+		t.Errorf("options = %v, want %v", args[3:12], want)
+	}
+	if !reflect.DeepEqual(args[len(args)-2:], []interface{}{"DIALECT", "2"}) {
+		t.Errorf("DIALECT missing: %v", args[len(args)-2:])
+	}
+
+	blob := args[12].([]byte)
+	if len(blob) != 8 {
+		t.Fatalf("blob length = %d, want 8", len(blob))
+	}
+	if math.Float32frombits(binary.LittleEndian.Uint32(blob[4:])) != 0.2 {
+		t.Errorf("blob is not little-endian float32")
+	}
+}
+
+func TestRedisSearch_OrGroupsAndText(t *testing.T) {
+	args, err := Of("idx").
+		Custom(NewRedisSearchBuilder().TextFields("title").Build()).
+		Eq("title", "hello world").
+		Or(func(cb *CondBuilder) {
+			cb.Eq("lang", "go").OR().Gt("stars", 100)
+		}).
+		Like("title", "redis").
+		IsNull("deleted_at").
+		Build().
+		RedisArgsOfSelect()
+	if err != nil {
+		t.Fatalf("RedisArgsOfSelect failed: %v", err)
+	}
+
+	want := `@title:"hello world" (@lang:{go} | @stars:[(100 +inf]) @title:*redis* ismissing(@deleted_at)`
+	if args[2] != want {
+		t.Errorf("query = %s\nwant    %s", args[2], want)
+	}
+	if len(args) != 5 {
+		t.Errorf("expected no PARAMS/LIMIT without KNN, got %v", args)
+	}
+}
+
+func TestRedisSearch_TagEscapingAndEmptyQuery(t *testing.T) {
+	args, _ := Of("idx").
+		Custom(NewRedisSearchBuilder().Build()).
+		Eq("email", "a.b@c.com").
+		Build().
+		RedisArgsOfSelect()
+	if args[2] != `@email:{a\.b\@c\.com}` {
+		t.Errorf("tag not escaped: %s", args[2])
+	}
+
+	args, _ = Of("idx").
+		Custom(NewRedisSearchBuilder().Build()).
+		Paged(func(pb *PageBuilder) { pb.Page(3).Rows(20) }).
+		Build().
+		RedisArgsOfSelect()
+	want := []interface{}{"FT.SEARCH", "idx", "*", "LIMIT", "40", "20", "DIALECT", "2"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestRedisSearch_Aggregate(t *testing.T) {
+	args, err := Of("idx").
+		Custom(NewRedisSearchBuilder().Aggregate().Dialect(3).Build()).
+		Select("brand", "price").
+		GroupBy("brand").
+		Sort("count", DESC).
+		Limit(5).
+		Eq("lang", "go").
+		Build().
+		RedisArgsOfSelect()
+	if err != nil {
+		t.Fatalf("RedisArgsOfSelect failed: %v", err)
+	}
+
+	want := []interface{}{
+		"FT.AGGREGATE", "idx", "@lang:{go}",
+		"LOAD", "2", "@brand", "@price",
+		"GROUPBY", "1", "@brand", "REDUCE", "COUNT", "0", "AS", "count",
+		"SORTBY", "2", "@count", "DESC",
+		"LIMIT", "0", "5",
+		"DIALECT", "3",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v\nwant   %v", args, want)
+	}
+}
+
+func TestRedisSearch_UnsupportedOperator(t *testing.T) {
+	_, err := Of("idx").
+		Custom(NewRedisSearchBuilder().Build()).
+		Sub("id IN ?", func(sb *BuilderX) {
+			sb.From("t").Select("id")
+		}).
+		Build().
+		RedisArgsOfSelect()
+	if err == nil {
+		t.Fatalf("expected error for SUB operator")
+	}
+}
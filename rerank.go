@@ -0,0 +1,325 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ============================================================================
+// Reranking: a second scoring stage over the retrieved candidates
+// ============================================================================
+
+// Reranker scores retrieved candidates against the query, higher is more relevant
+// Returns one score per doc, in the order of docs (the retrieval order)
+// Cross-encoders and hosted rerank APIs implement it outside xb
+type Reranker interface {
+	Rerank(ctx context.Context, query string, docs []RerankDoc) ([]float32, error)
+}
+
+// RerankerFunc adapts a function to Reranker
+type RerankerFunc func(ctx context.Context, query string, docs []RerankDoc) ([]float32, error)
+
+// Rerank implements Reranker
+func (f RerankerFunc) Rerank(ctx context.Context, query string, docs []RerankDoc) ([]float32, error) {
+	return f(ctx, query, docs)
+}
+
+// RerankDoc one candidate: Payload is the decoded payload (map or struct, see payloadField)
+type RerankDoc struct {
+	ID      interface{}
+	Score   float32 // Retrieval score, as returned by Qdrant (MemoryVectorCustom returns the same)
+	Payload interface{}
+}
+
+// RerankParams set by Rerank(), stored on the VectorSearch
+type RerankParams struct {
+	Stages     []RerankStage
+	Query      string // Defaults to the SemanticSearch() text
+	Candidates int    // Over-fetch: the search returns Candidates hits
+	TopN       int    // Results kept after reranking, defaults to the VectorSearch topK
+}
+
+// RerankStage a reranker and its weight, the final score is the weighted sum of the stages
+type RerankStage struct {
+	Reranker Reranker
+	Weight   float32
+}
+
+// RerankBuilder configures Rerank()
+type RerankBuilder struct {
+	params RerankParams
+}
+
+// With adds a reranker, weight defaults to 1
+func (rr *RerankBuilder) With(reranker Reranker, weight ...float32) *RerankBuilder {
+	if reranker == nil {
+		panic("Rerank(): With() requires a Reranker")
+	}
+	w := float32(1)
+	if len(weight) > 0 {
+		w = weight[0]
+	}
+	rr.params.Stages = append(rr.params.Stages, RerankStage{Reranker: reranker, Weight: w})
+	return rr
+}
+
+// Query sets the text the candidates are reranked against
+func (rr *RerankBuilder) Query(text string) *RerankBuilder {
+	rr.params.Query = text
+	return rr
+}
+
+// Candidates sets how many hits the search fetches (default 5 * TopN)
+func (rr *RerankBuilder) Candidates(n int) *RerankBuilder {
+	if n < 1 {
+		panic(fmt.Sprintf("Rerank(): Candidates must be >= 1, got: %d", n))
+	}
+	rr.params.Candidates = n
+	return rr
+}
+
+// TopN sets how many results are kept after reranking (default: the VectorSearch topK)
+func (rr *RerankBuilder) TopN(n int) *RerankBuilder {
+	if n < 1 {
+		panic(fmt.Sprintf("Rerank(): TopN must be >= 1, got: %d", n))
+	}
+	rr.params.TopN = n
+	return rr
+}
+
+// Rerank adds a reranking stage after the VectorSearch / SemanticSearch
+// The generated query over-fetches Candidates hits (SqlOfVectorSearch, ToQdrantRequest,
+// Redis, MemoryVectorCustom); ApplyRerank() scores the decoded results and keeps TopN.
+// qdrant.Search() and MemorySearch() apply it themselves.
+//
+// Example:
+//
+//	xb.Of(&Doc{}).
+//	    Custom(qdrantCustom).
+//	    SemanticSearch("embedding", question, 10).
+//	    Rerank(func(rr *xb.RerankBuilder) {
+//	        rr.With(xb.LexicalOverlap("title", "content")).
+//	            With(xb.RecencyBoost("updated_at", 30*24*time.Hour), 0.2).
+//	            Candidates(50)
+//	    }).
+//	    Build()
+func (x *BuilderX) Rerank(fn func(rr *RerankBuilder)) *BuilderX {
+	if fn == nil {
+		x.rerank = nil
+		return x
+	}
+	rr := &RerankBuilder{}
+	fn(rr)
+	if len(rr.params.Stages) == 0 {
+		panic("Rerank() requires at least one With()")
+	}
+	if rr.params.Candidates > 0 && rr.params.TopN > rr.params.Candidates {
+		panic(fmt.Sprintf("Rerank(): TopN %d > Candidates %d", rr.params.TopN, rr.params.Candidates))
+	}
+	x.rerank = &rr.params
+	return x
+}
+
+// applyRerank stores the Rerank() params on the VectorSearch, defaults come from its topK and text
+func (x *BuilderX) applyRerank() {
+	if x.rerank == nil {
+		return
+	}
+	vectorBb := findVectorSearchBb(x.bbs)
+	if vectorBb == nil {
+		panic("Rerank() requires a VectorSearch() or SemanticSearch()")
+	}
+	params := vectorBb.Value.(VectorSearchParams)
+	rerank := *x.rerank
+	if rerank.TopN == 0 {
+		rerank.TopN = params.TopK
+	}
+	if rerank.Candidates == 0 {
+		rerank.Candidates = rerank.TopN * 5
+	}
+	if rerank.Candidates < rerank.TopN {
+		panic(fmt.Sprintf("Rerank(): TopN %d > Candidates %d", rerank.TopN, rerank.Candidates))
+	}
+	if rerank.Query == "" {
+		rerank.Query = params.QueryText
+	}
+	params.Rerank = &rerank
+	vectorBb.Value = params
+}
+
+// ApplyRerank reranks decoded search results by the Rerank() stage of built
+// Score becomes the reranker score (higher is better), results are sorted by it and cut to TopN.
+// Without Rerank() the results are returned as is.
+//
+// Example:
+//
+//	rows := decodeSQLRows(db.Query(built.SqlOfVectorSearch()))
+//	rows, err = xb.ApplyRerank(ctx, built, rows)
+func ApplyRerank[T any](ctx context.Context, built *Built, results []ScoredPoint[T]) ([]ScoredPoint[T], error) {
+	vectorBb := findVectorSearchBb(built.Conds)
+	if vectorBb == nil {
+		return results, nil
+	}
+	params := vectorBb.Value.(VectorSearchParams).Rerank
+	if params == nil || len(results) == 0 {
+		return results, nil
+	}
+
+	docs := make([]RerankDoc, len(results))
+	for i, r := range results {
+		docs[i] = RerankDoc{ID: r.ID, Score: r.Score, Payload: r.Payload}
+	}
+	total := make([]float32, len(results))
+	for _, stage := range params.Stages {
+		scores, err := stage.Reranker.Rerank(ctx, params.Query, docs)
+		if err != nil {
+			return nil, fmt.Errorf("rerank failed: %w", err)
+		}
+		if len(scores) != len(docs) {
+			return nil, fmt.Errorf("reranker returned %d scores for %d docs", len(scores), len(docs))
+		}
+		for i, s := range scores {
+			total[i] += stage.Weight * s
+		}
+	}
+
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	// Stable: equal scores keep the retrieval order
+	sort.SliceStable(order, func(a, b int) bool {
+		return total[order[a]] > total[order[b]]
+	})
+	if len(order) > params.TopN {
+		order = order[:params.TopN]
+	}
+
+	out := make([]ScoredPoint[T], len(order))
+	for n, i := range order {
+		out[n] = results[i]
+		out[n].Score = total[i]
+	}
+	return out, nil
+}
+
+// ============================================================================
+// Built-in rerankers
+// ============================================================================
+
+// LexicalOverlap scores the share of distinct query words found in the fields (0..1)
+// A cheap keyword check over dense retrieval, e.g. for error codes and identifiers
+func LexicalOverlap(fields ...string) Reranker {
+	if len(fields) == 0 {
+		panic("LexicalOverlap() requires at least one field")
+	}
+	return RerankerFunc(func(ctx context.Context, query string, docs []RerankDoc) ([]float32, error) {
+		words := rerankWords(query)
+		scores := make([]float32, len(docs))
+		if len(words) == 0 {
+			return scores, nil
+		}
+		for i, doc := range docs {
+			found := map[string]bool{}
+			for _, field := range fields {
+				v, ok := payloadField(doc.Payload, field)
+				if !ok {
+					continue
+				}
+				for w := range rerankWords(fmt.Sprint(v)) {
+					if words[w] {
+						found[w] = true
+					}
+				}
+			}
+			scores[i] = float32(len(found)) / float32(len(words))
+		}
+		return scores, nil
+	})
+}
+
+func rerankWords(text string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[w] = true
+	}
+	return words
+}
+
+// FieldBoost scores the numeric value of a payload field (popularity, rating, ...)
+// Missing or non-numeric values score 0; combine with a weight, e.g. With(xb.FieldBoost("stars"), 0.01)
+func FieldBoost(field string) Reranker {
+	return RerankerFunc(func(ctx context.Context, query string, docs []RerankDoc) ([]float32, error) {
+		scores := make([]float32, len(docs))
+		for i, doc := range docs {
+			if v, ok := payloadField(doc.Payload, field); ok {
+				if f, ok := payloadNumber(v); ok {
+					scores[i] = float32(f)
+				}
+			}
+		}
+		return scores, nil
+	})
+}
+
+// RecencyBoost scores 0.5^(age / halfLife) of a time field: 1 for now, 0.5 one half-life ago
+// The field is a time.Time, a date / RFC 3339 string or unix seconds; missing values score 0
+func RecencyBoost(field string, halfLife time.Duration) Reranker {
+	if halfLife <= 0 {
+		panic(fmt.Sprintf("RecencyBoost() halfLife must be > 0, got: %v", halfLife))
+	}
+	return RerankerFunc(func(ctx context.Context, query string, docs []RerankDoc) ([]float32, error) {
+		now := time.Now()
+		scores := make([]float32, len(docs))
+		for i, doc := range docs {
+			v, ok := payloadField(doc.Payload, field)
+			if !ok {
+				continue
+			}
+			t, ok := rerankTime(v)
+			if !ok {
+				continue
+			}
+			age := max(now.Sub(t), 0)
+			scores[i] = float32(math.Pow(0.5, float64(age)/float64(halfLife)))
+		}
+		return scores, nil
+	})
+}
+
+// rerankTime payloadTime with a unix seconds fallback
+func rerankTime(v interface{}) (time.Time, bool) {
+	if t, ok := v.(*time.Time); ok && t != nil {
+		v = *t
+	}
+	if t, ok := payloadTime(v); ok {
+		return t, !t.IsZero()
+	}
+	if sec, ok := payloadNumber(v); ok {
+		return time.Unix(int64(sec), 0), true
+	}
+	return time.Time{}, false
+}
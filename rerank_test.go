@@ -0,0 +1,214 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type rerankDoc struct {
+	Title     string    `json:"title"`
+	Stars     int       `json:"stars"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func TestRerank_OverFetch(t *testing.T) {
+	rerank := func(rr *RerankBuilder) { rr.With(LexicalOverlap("content")).Candidates(40) }
+
+	sql, _ := Of(&CodeVector{}).
+		VectorSearch("embedding", Vector{1, 0}, 5).
+		Rerank(rerank).
+		Build().
+		SqlOfVectorSearch()
+	if !strings.HasSuffix(sql, "ORDER BY distance LIMIT 40") {
+		t.Errorf("sql = %s", sql)
+	}
+
+	// Rerank() may come before the search, TopN defaults to topK and Candidates to 5 * TopN
+	built := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Rerank(func(rr *RerankBuilder) { rr.With(FieldBoost("stars")) }).
+		VectorSearch("embedding", Vector{1, 0}, 4).
+		Build()
+	req, err := built.ToQdrantRequest()
+	if err != nil {
+		t.Fatalf("ToQdrantRequest failed: %v", err)
+	}
+	params := findVectorSearchBb(built.Conds).Value.(VectorSearchParams).Rerank
+	if req.Limit != 20 || params.TopN != 4 || params.Candidates != 20 {
+		t.Errorf("limit = %d, rerank = %+v", req.Limit, params)
+	}
+
+	// The SemanticSearch text is the default query
+	built, err = Of(&CodeVector{}).Embedder(NewHashEmbedder(4)).
+		SemanticSearch("embedding", "retry request", 3).
+		Rerank(rerank).
+		BuildContext(context.Background())
+	if err != nil {
+		t.Fatalf("BuildContext failed: %v", err)
+	}
+	if q := findVectorSearchBb(built.Conds).Value.(VectorSearchParams).Rerank.Query; q != "retry request" {
+		t.Errorf("query = %q", q)
+	}
+}
+
+func TestApplyRerank(t *testing.T) {
+	now := time.Now()
+	results := []ScoredPoint[rerankDoc]{
+		{ID: 1, Score: 0.9, Payload: rerankDoc{Title: "bake bread", Stars: 10, UpdatedAt: now.Add(-48 * time.Hour)}},
+		{ID: 2, Score: 0.8, Payload: rerankDoc{Title: "retry the HTTP request", Stars: 1, UpdatedAt: now.Add(-24 * time.Hour)}},
+		{ID: 3, Score: 0.7, Payload: rerankDoc{Title: "http timeout", Stars: 5, UpdatedAt: now}},
+	}
+	built := Of(&CodeVector{}).
+		VectorSearch("embedding", Vector{1, 0}, 2).
+		Rerank(func(rr *RerankBuilder) {
+			rr.With(LexicalOverlap("title")).Query("retry http request").Candidates(10)
+		}).
+		Build()
+
+	got, err := ApplyRerank(context.Background(), built, results)
+	if err != nil {
+		t.Fatalf("ApplyRerank failed: %v", err)
+	}
+	// Overlap: 2 => 3/3, 3 => 1/3, 1 => 0; TopN 2
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 || got[0].Score != 1 {
+		t.Fatalf("got = %+v", got)
+	}
+
+	// Weighted stages: recency (half-life 1 day) + stars
+	built = Of(&CodeVector{}).
+		VectorSearch("embedding", Vector{1, 0}, 3).
+		Rerank(func(rr *RerankBuilder) {
+			rr.With(RecencyBoost("updated_at", 24*time.Hour)).With(FieldBoost("stars"), 0.1)
+		}).
+		Build()
+	got, _ = ApplyRerank(context.Background(), built, results)
+	// 1 => 0.25 + 1.0, 2 => 0.5 + 0.1, 3 => 1 + 0.5
+	if got[0].ID != 3 || got[1].ID != 1 || got[2].ID != 2 {
+		t.Errorf("got = %+v", got)
+	}
+
+	// Without Rerank() the results are unchanged
+	plain := Of(&CodeVector{}).VectorSearch("embedding", Vector{1, 0}, 3).Build()
+	if got, _ := ApplyRerank(context.Background(), plain, results); len(got) != 3 || got[0].Score != 0.9 {
+		t.Errorf("got = %+v", got)
+	}
+
+	failing := Of(&CodeVector{}).
+		VectorSearch("embedding", Vector{1, 0}, 3).
+		Rerank(func(rr *RerankBuilder) {
+			rr.With(RerankerFunc(func(context.Context, string, []RerankDoc) ([]float32, error) {
+				return nil, errors.New("model down")
+			}))
+		}).
+		Build()
+	if _, err := ApplyRerank(context.Background(), failing, results); err == nil || !strings.Contains(err.Error(), "model down") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRerank_PayloadTypes(t *testing.T) {
+	docs := func(vs ...interface{}) []RerankDoc {
+		out := make([]RerankDoc, len(vs))
+		for i, v := range vs {
+			out[i] = RerankDoc{Payload: map[string]interface{}{"f": v}}
+		}
+		return out
+	}
+
+	// Same number types as the filter matching
+	scores, _ := FieldBoost("f").Rerank(context.Background(), "", docs(json.Number("2.5"), int8(3), uint16(4), uint32(5), "6"))
+	if scores[0] != 2.5 || scores[1] != 3 || scores[2] != 4 || scores[3] != 5 || scores[4] != 0 {
+		t.Errorf("FieldBoost scores = %v", scores)
+	}
+
+	now := time.Now()
+	day := now.Add(-24 * time.Hour)
+	scores, _ = RecencyBoost("f", 24*time.Hour).Rerank(context.Background(), "", docs(
+		day.Format(time.RFC3339Nano), day.UTC().Format("2006-01-02 15:04:05"), day.Unix(), json.Number(fmt.Sprint(day.Unix())), "yesterday"))
+	for i, score := range scores[:4] {
+		if score < 0.49 || score > 0.51 {
+			t.Errorf("RecencyBoost score %d = %v", i, score)
+		}
+	}
+	if scores[4] != 0 {
+		t.Errorf("unparsable time scored %v", scores[4])
+	}
+}
+
+func TestMemoryVector_Rerank(t *testing.T) {
+	mem := NewMemoryVectorBuilder().Build()
+	for id, title := range map[int]string{1: "sourdough bread", 2: "retry with backoff", 3: "bread recipe"} {
+		_, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+			Insert(func(ib *InsertBuilder) {
+				ib.Set("id", id).Set("embedding", Vector{1, float32(id) / 10}).Set("title", title)
+			}).
+			Build().
+			MemoryOfExec()
+		if err != nil {
+			t.Fatalf("insert %d failed: %v", id, err)
+		}
+	}
+
+	// Vector order is 1 2 3, the reranker moves 2 up from the over-fetched candidates
+	results, err := MemorySearch[map[string]interface{}](Of(&CodeVectorForQdrant{}).Custom(mem).
+		VectorSearch("embedding", Vector{1, 0}, 1).
+		Rerank(func(rr *RerankBuilder) {
+			rr.With(LexicalOverlap("title")).Query("retry").Candidates(3)
+		}).
+		Build())
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := memoryIDs(results); got != "2 " {
+		t.Errorf("ids = %s", got)
+	}
+}
+
+func TestRerank_Validation(t *testing.T) {
+	cases := map[string]func(){
+		"no stage":     func() { Of(&CodeVector{}).Rerank(func(rr *RerankBuilder) { rr.TopN(3) }) },
+		"nil reranker": func() { Of(&CodeVector{}).Rerank(func(rr *RerankBuilder) { rr.With(nil) }) },
+		"topN":         func() { Of(&CodeVector{}).Rerank(func(rr *RerankBuilder) { rr.TopN(0) }) },
+		"candidates":   func() { Of(&CodeVector{}).Rerank(func(rr *RerankBuilder) { rr.Candidates(-1) }) },
+		"topN > fetch": func() {
+			Of(&CodeVector{}).Rerank(func(rr *RerankBuilder) { rr.With(FieldBoost("a")).TopN(5).Candidates(2) })
+		},
+		"no search":      func() { Of(&CodeVector{}).Rerank(func(rr *RerankBuilder) { rr.With(FieldBoost("a")) }).Build() },
+		"lexical fields": func() { LexicalOverlap() },
+		"half-life":      func() { RecencyBoost("ts", 0) },
+		"default topN": func() {
+			Of(&CodeVector{}).VectorSearch("embedding", Vector{1}, 10).
+				Rerank(func(rr *RerankBuilder) { rr.With(FieldBoost("a")).Candidates(5) }).Build()
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ScoredPoint one hit of a vector search, in the order returned by the database
+// T is the payload type: map[string]interface{} or a user struct with json tags
+type ScoredPoint[T any] struct {
+	ID      interface{}       `json:"id"`
+	Score   float32           `json:"score"`
+	Payload T                 `json:"payload,omitempty"`
+	Vector  Vector            `json:"vector,omitempty"`
+	Vectors map[string]Vector `json:"vectors,omitempty"` // Named vectors (Qdrant with_vector on a named-vector collection)
+}
+
+// payloadField reads one field of a payload
+// Supports maps with string keys and structs (matched by json tag, db tag, then field name)
+func payloadField(payload interface{}, field string) (interface{}, bool) {
+	if payload == nil || field == "" {
+		return nil, false
+	}
+	if m, ok := payload.(map[string]interface{}); ok {
+		v, ok := m[field]
+		return v, ok
+	}
+
+	rv := reflect.ValueOf(payload)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		v := rv.MapIndex(reflect.ValueOf(field).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, false
+		}
+		return v.Interface(), true
+	case reflect.Struct:
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			sf := rt.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			if tagName(sf.Tag.Get("json")) == field || tagName(sf.Tag.Get("db")) == field {
+				return rv.Field(i).Interface(), true
+			}
+		}
+		if sf, ok := rt.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, field) }); ok && sf.IsExported() {
+			return rv.FieldByIndex(sf.Index).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// tagName returns the name part of a struct tag: `json:"doc_id,omitempty"` => doc_id
+func tagName(tag string) string {
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
@@ -0,0 +1,371 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fndome/xb/interceptor"
+)
+
+// ============================================================================
+// SemanticCache: results of near-identical vector queries
+// ============================================================================
+
+// SemanticCacheStore storage of a SemanticCache, MemorySemanticCacheStore is the in-process one
+// Shared stores (Redis, ...) implement it outside xb
+type SemanticCacheStore interface {
+	// Get returns the results of the entry of fingerprint whose query is the most similar to query,
+	// if its cosine similarity is >= minSimilarity
+	Get(fingerprint string, query Vector, minSimilarity float32) (interface{}, bool)
+
+	// Set adds an entry
+	Set(entry SemanticCacheEntry)
+
+	// InvalidateTable drops the entries of a table or collection
+	InvalidateTable(table string)
+}
+
+// SemanticCacheEntry one cached query
+type SemanticCacheEntry struct {
+	Fingerprint string // Table, filters, vector field, topK, sort and page: everything but the query vector
+	Table       string
+	Query       Vector
+	Results     interface{}
+}
+
+// SemanticCache returns cached results for a vector search whose filters are identical and whose
+// query vector is close to a cached one (cosine similarity >= threshold)
+//
+// Use it from the executor with CachedSearch(), and call Invalidate(built) once a write
+// (Insert, Update, Delete) has executed. A search that overlaps an invalidation is not cached.
+// Registered as an interceptor, Insert/Update builds also drop their table early (before they run):
+//
+//	cache := xb.NewSemanticCache(xb.NewMemorySemanticCacheStore(10000, 10*time.Minute), 0.97)
+//
+//	results, err := xb.CachedSearch(cache, built, func() ([]xb.ScoredPoint[Doc], error) {
+//	    return qdrant.Search[Doc](ctx, client, "docs", built)
+//	})
+//
+//	_, err = db.Exec(del.SqlOfDelete())
+//	cache.Invalidate(del)
+type SemanticCache struct {
+	store     SemanticCacheStore
+	threshold float32
+	hits      atomic.Int64
+	misses    atomic.Int64
+
+	mu       sync.Mutex
+	versions map[string]uint64 // Per table, bumped by every invalidation
+}
+
+// NewSemanticCache creates a cache, threshold is the minimum cosine similarity of a hit, in (0, 1]
+func NewSemanticCache(store SemanticCacheStore, threshold float32) *SemanticCache {
+	if store == nil {
+		panic("NewSemanticCache() requires a store")
+	}
+	if threshold <= 0 || threshold > 1 {
+		panic(fmt.Sprintf("NewSemanticCache() threshold must be in (0, 1], got: %v", threshold))
+	}
+	return &SemanticCache{store: store, threshold: threshold, versions: map[string]uint64{}}
+}
+
+// Name implements interceptor.Interceptor
+func (c *SemanticCache) Name() string {
+	return "semantic_cache"
+}
+
+// BeforeBuild implements interceptor.Interceptor
+func (c *SemanticCache) BeforeBuild(meta *interceptor.Metadata) error {
+	return nil
+}
+
+// AfterBuild implements interceptor.Interceptor: Insert/Update builds drop their table early
+// The write has not run yet, Invalidate() after it is still required
+func (c *SemanticCache) AfterBuild(built interface{}) error {
+	if b, ok := built.(*Built); ok {
+		if (b.Inserts != nil && len(*b.Inserts) > 0) || (b.Updates != nil && len(*b.Updates) > 0) {
+			c.InvalidateTable(cacheTable(b))
+		}
+	}
+	return nil
+}
+
+// Invalidate drops the entries of the table of a write Built, call it after the write executed
+// Any Built is accepted: a delete Built is flagged only by JsonOfDelete() / MemoryOfDelete()
+func (c *SemanticCache) Invalidate(built *Built) {
+	if built == nil {
+		return
+	}
+	c.InvalidateTable(cacheTable(built))
+}
+
+// InvalidateTable drops the entries of a table, for writes that bypass xb
+func (c *SemanticCache) InvalidateTable(table string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versions[table]++
+	c.store.InvalidateTable(table)
+}
+
+// version of a table, compared by storeIfCurrent
+func (c *SemanticCache) version(table string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.versions[table]
+}
+
+// Lookup the cached results of a search Built
+// Only dense vector searches are cached, other Builts always miss
+func (c *SemanticCache) Lookup(built *Built) (interface{}, bool) {
+	fingerprint, query, ok := SemanticFingerprint(built)
+	if !ok {
+		return nil, false
+	}
+	results, ok := c.store.Get(fingerprint, query, c.threshold)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return results, ok
+}
+
+// Store caches the results of a search Built
+// The results are shared with later hits, do not modify them
+func (c *SemanticCache) Store(built *Built, results interface{}) {
+	c.storeIfCurrent(built, results, nil)
+}
+
+// storeIfCurrent stores unless the table was invalidated since version was read
+func (c *SemanticCache) storeIfCurrent(built *Built, results interface{}, version *uint64) {
+	fingerprint, query, ok := SemanticFingerprint(built)
+	if !ok {
+		return
+	}
+	table := cacheTable(built)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version != nil && c.versions[table] != *version {
+		return // A write ran during the search, its results may be stale
+	}
+	c.store.Set(SemanticCacheEntry{Fingerprint: fingerprint, Table: table, Query: query, Results: results})
+}
+
+// Stats number of hits and misses of Lookup()
+func (c *SemanticCache) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// CachedSearch returns the cached results of built, or runs search and caches its results
+// Errors, and results of a search that overlapped an invalidation of the table, are not cached.
+// The returned slice is a copy, the cached entry is not modified through it
+func CachedSearch[T any](cache *SemanticCache, built *Built, search func() ([]ScoredPoint[T], error)) ([]ScoredPoint[T], error) {
+	if cached, ok := cache.Lookup(built); ok {
+		if results, ok := cached.([]ScoredPoint[T]); ok {
+			return append([]ScoredPoint[T](nil), results...), nil
+		}
+	}
+	version := cache.version(cacheTable(built))
+	results, err := search()
+	if err != nil {
+		return nil, err
+	}
+	cache.storeIfCurrent(built, append([]ScoredPoint[T](nil), results...), &version)
+	return results, nil
+}
+
+// SemanticFingerprint the cache key of a dense vector search: a hash of everything but the query vector
+// ok is false for Builts without a dense VectorSearch (writes, scrolls, multivector, sparse, ...)
+func SemanticFingerprint(built *Built) (fingerprint string, query Vector, ok bool) {
+	if built == nil || built.Delete || built.Inserts != nil || built.Updates != nil {
+		return "", nil, false
+	}
+	vectorBb := findVectorSearchBb(built.Conds)
+	if vectorBb == nil {
+		return "", nil, false
+	}
+	params := vectorBb.Value.(VectorSearchParams)
+	if params.QueryVector == nil || params.QueryMultiVector != nil || params.QuerySparseVector != nil {
+		return "", nil, false
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%T|%s|", built.Custom, cacheTable(built))
+	writeFingerprintBbs(&sb, built.Conds)
+	fmt.Fprintf(&sb, "|%v|%v|%d|%d|%v", built.Sorts, built.ResultKeys, built.LimitValue, built.OffsetValue, built.PageCondition)
+
+	sum := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(sum[:]), params.QueryVector, true
+}
+
+// writeFingerprintBbs writes conditions by value (IN lists are pointers), without query vectors
+func writeFingerprintBbs(sb *strings.Builder, bbs []Bb) {
+	for _, bb := range bbs {
+		sb.WriteString(bb.Op)
+		sb.WriteByte(':')
+		sb.WriteString(bb.Key)
+		sb.WriteByte('=')
+		if params, ok := bb.Value.(VectorSearchParams); ok {
+			fmt.Fprintf(sb, "%d,%s,%s", params.TopK, params.DistanceMetric, params.Encoding)
+			if params.Diversity != nil {
+				fmt.Fprintf(sb, ",%+v", *params.Diversity)
+			}
+			if params.Rerank != nil {
+				fmt.Fprintf(sb, ",%d,%d,%q,%d", params.Rerank.Candidates, params.Rerank.TopN, params.Rerank.Query, len(params.Rerank.Stages))
+			}
+		} else if bytes, err := json.Marshal(bb.Value); err == nil {
+			sb.Write(bytes)
+		} else {
+			fmt.Fprintf(sb, "%T", bb.Value)
+		}
+		if len(bb.Subs) > 0 {
+			sb.WriteByte('(')
+			writeFingerprintBbs(sb, bb.Subs)
+			sb.WriteByte(')')
+		}
+		sb.WriteByte(';')
+	}
+}
+
+// cacheTable table or collection name of a Built, without alias
+func cacheTable(built *Built) string {
+	fields := strings.Fields(built.OrFromSql)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// ============================================================================
+// MemorySemanticCacheStore
+// ============================================================================
+
+// MemorySemanticCacheStore in-process SemanticCacheStore with TTL and LRU eviction
+// Get scans the entries of one fingerprint, keep maxEntries moderate (thousands)
+type MemorySemanticCacheStore struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List // Front: most recently used
+	byPrint map[string]map[*list.Element]bool
+}
+
+type memoryCacheEntry struct {
+	SemanticCacheEntry
+	expires time.Time
+}
+
+// NewMemorySemanticCacheStore keeps at most maxEntries entries, each for ttl (0: no expiry)
+func NewMemorySemanticCacheStore(maxEntries int, ttl time.Duration) *MemorySemanticCacheStore {
+	if maxEntries < 1 {
+		panic(fmt.Sprintf("NewMemorySemanticCacheStore() maxEntries must be >= 1, got: %d", maxEntries))
+	}
+	if ttl < 0 {
+		panic(fmt.Sprintf("NewMemorySemanticCacheStore() ttl must be >= 0, got: %v", ttl))
+	}
+	return &MemorySemanticCacheStore{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		lru:        list.New(),
+		byPrint:    map[string]map[*list.Element]bool{},
+	}
+}
+
+// Get implements SemanticCacheStore
+func (s *MemorySemanticCacheStore) Get(fingerprint string, query Vector, minSimilarity float32) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var best *list.Element
+	bestSim := minSimilarity
+	for el := range s.byPrint[fingerprint] {
+		entry := el.Value.(*memoryCacheEntry)
+		if s.ttl > 0 && now.After(entry.expires) {
+			s.remove(el)
+			continue
+		}
+		if len(entry.Query) != len(query) {
+			continue
+		}
+		if sim := 1 - cosineDistance(entry.Query, query); sim >= bestSim {
+			best, bestSim = el, sim
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	s.lru.MoveToFront(best)
+	return best.Value.(*memoryCacheEntry).Results, true
+}
+
+// Set implements SemanticCacheStore
+func (s *MemorySemanticCacheStore) Set(entry SemanticCacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el := s.lru.PushFront(&memoryCacheEntry{SemanticCacheEntry: entry, expires: s.now().Add(s.ttl)})
+	if s.byPrint[entry.Fingerprint] == nil {
+		s.byPrint[entry.Fingerprint] = map[*list.Element]bool{}
+	}
+	s.byPrint[entry.Fingerprint][el] = true
+	for s.lru.Len() > s.maxEntries {
+		s.remove(s.lru.Back())
+	}
+}
+
+// InvalidateTable implements SemanticCacheStore
+func (s *MemorySemanticCacheStore) InvalidateTable(table string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for el := s.lru.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*memoryCacheEntry).Table == table {
+			s.remove(el)
+		}
+		el = next
+	}
+}
+
+// Len number of entries, expired ones included until they are looked up or evicted
+func (s *MemorySemanticCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+func (s *MemorySemanticCacheStore) remove(el *list.Element) {
+	fingerprint := el.Value.(*memoryCacheEntry).Fingerprint
+	s.lru.Remove(el)
+	delete(s.byPrint[fingerprint], el)
+	if len(s.byPrint[fingerprint]) == 0 {
+		delete(s.byPrint, fingerprint)
+	}
+}
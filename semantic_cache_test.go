@@ -0,0 +1,187 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fndome/xb/interceptor"
+)
+
+func cacheSearch(lang string, vec Vector) *Built {
+	return Of(&CodeVector{}).Eq("language", lang).In("layer", "service", "repository").VectorSearch("embedding", vec, 5).Build()
+}
+
+func TestSemanticFingerprint(t *testing.T) {
+	a, qa, ok := SemanticFingerprint(cacheSearch("golang", Vector{1, 0}))
+	b, _, _ := SemanticFingerprint(cacheSearch("golang", Vector{0, 1}))
+	c, _, _ := SemanticFingerprint(cacheSearch("python", Vector{1, 0}))
+	if !ok || a != b || a == c || qa[0] != 1 {
+		t.Errorf("fingerprints: same filters %v, other filters %v", a == b, a == c)
+	}
+	d, _, _ := SemanticFingerprint(Of(&CodeVector{}).Eq("language", "golang").In("layer", "service", "repository").
+		VectorSearch("embedding", Vector{1, 0}, 10).Build())
+	if d == a {
+		t.Error("topK must change the fingerprint")
+	}
+
+	for name, built := range map[string]*Built{
+		"insert":           Of(&CodeVector{}).Insert(func(ib *InsertBuilder) { ib.Set("id", 1) }).Build(),
+		"no vector search": Of(&CodeVector{}).Eq("language", "golang").Build(),
+		"sparse":           Of(&CodeVector{}).SparseVectorSearch("s", NewSparseVector(map[uint32]float32{1: 1}), 5).Build(),
+	} {
+		if _, _, ok := SemanticFingerprint(built); ok {
+			t.Errorf("%s should not be cacheable", name)
+		}
+	}
+}
+
+func TestSemanticCache_CachedSearch(t *testing.T) {
+	cache := NewSemanticCache(NewMemorySemanticCacheStore(10, 0), 0.99)
+	calls := 0
+	search := func() ([]ScoredPoint[CodeVector], error) {
+		calls++
+		return []ScoredPoint[CodeVector]{{ID: calls, Score: 0.9}}, nil
+	}
+
+	first, _ := CachedSearch(cache, cacheSearch("golang", Vector{1, 0}), search)
+	near, _ := CachedSearch(cache, cacheSearch("golang", Vector{1, 0.05}), search) // cos 0.9988
+	far, _ := CachedSearch(cache, cacheSearch("golang", Vector{1, 0.5}), search)   // cos 0.894
+	other, _ := CachedSearch(cache, cacheSearch("python", Vector{1, 0}), search)
+	if calls != 3 || near[0].ID != first[0].ID || far[0].ID == first[0].ID || other[0].ID != 3 {
+		t.Errorf("calls = %d, results: %v %v %v %v", calls, first, near, far, other)
+	}
+	if hits, misses := cache.Stats(); hits != 1 || misses != 3 {
+		t.Errorf("hits = %d, misses = %d", hits, misses)
+	}
+
+	// Errors are not cached
+	failing := func() ([]ScoredPoint[CodeVector], error) { return nil, errors.New("down") }
+	if _, err := CachedSearch(cache, cacheSearch("rust", Vector{1, 0}), failing); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := cache.Lookup(cacheSearch("rust", Vector{1, 0})); ok {
+		t.Error("error was cached")
+	}
+}
+
+func TestSemanticCache_Invalidation(t *testing.T) {
+	store := NewMemorySemanticCacheStore(10, 0)
+	cache := NewSemanticCache(store, 0.95)
+	cache.Store(cacheSearch("golang", Vector{1, 0}), "cached")
+	cache.Store(Of("other").VectorSearch("embedding", Vector{1, 0}, 5).Build(), "other")
+
+	interceptor.Clear()
+	interceptor.Register(cache)
+	defer interceptor.Clear()
+
+	// A select keeps the entries, an insert into code_vectors drops its own
+	cacheSearch("golang", Vector{1, 0})
+	if store.Len() != 2 {
+		t.Fatalf("Len = %d after a select", store.Len())
+	}
+	Of(&CodeVector{}).Insert(func(ib *InsertBuilder) { ib.Set("id", 1).Set("language", "golang") }).Build()
+	if _, ok := cache.Lookup(cacheSearch("golang", Vector{1, 0})); ok || store.Len() != 1 {
+		t.Errorf("insert did not invalidate, Len = %d", store.Len())
+	}
+
+	// A delete is invalidated after it executed
+	del := Of("other").Eq("id", 1).Build()
+	if sql, _ := del.SqlOfDelete(); !strings.HasPrefix(sql, "DELETE") {
+		t.Fatalf("sql = %s", sql)
+	}
+	cache.Invalidate(del)
+	if store.Len() != 0 {
+		t.Errorf("delete did not invalidate, Len = %d", store.Len())
+	}
+}
+
+func TestSemanticCache_WriteDuringSearch(t *testing.T) {
+	store := NewMemorySemanticCacheStore(10, 0)
+	cache := NewSemanticCache(store, 0.95)
+	built := cacheSearch("golang", Vector{1, 0})
+
+	// The write commits while the search runs: its results may predate the write
+	stale, _ := CachedSearch(cache, built, func() ([]ScoredPoint[CodeVector], error) {
+		cache.InvalidateTable("code_vectors")
+		return []ScoredPoint[CodeVector]{{ID: 1}}, nil
+	})
+	if len(stale) != 1 || store.Len() != 0 {
+		t.Fatalf("results of an overlapping search were cached, Len = %d", store.Len())
+	}
+
+	// Returned slices are copies
+	fresh := func() ([]ScoredPoint[CodeVector], error) { return []ScoredPoint[CodeVector]{{ID: 2}}, nil }
+	first, _ := CachedSearch(cache, built, fresh)
+	first[0].ID = 99
+	second, _ := CachedSearch(cache, built, fresh)
+	second[0].ID = 98
+	third, _ := CachedSearch(cache, built, fresh)
+	if third[0].ID != 2 {
+		t.Errorf("cached entry modified through a returned slice: %v", third[0].ID)
+	}
+}
+
+func TestMemorySemanticCacheStore_TTLAndEviction(t *testing.T) {
+	store := NewMemorySemanticCacheStore(2, time.Minute)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	store.Set(SemanticCacheEntry{Fingerprint: "f", Table: "t", Query: Vector{1, 0}, Results: "a"})
+	store.Set(SemanticCacheEntry{Fingerprint: "f", Table: "t", Query: Vector{0, 1}, Results: "b"})
+	if r, ok := store.Get("f", Vector{0.1, 1}, 0.9); !ok || r != "b" {
+		t.Errorf("Get = %v, %v, want the most similar entry", r, ok)
+	}
+
+	// "a" is the least recently used
+	store.Set(SemanticCacheEntry{Fingerprint: "g", Table: "t", Query: Vector{1, 1}, Results: "c"})
+	if _, ok := store.Get("f", Vector{1, 0}, 0.9); ok || store.Len() != 2 {
+		t.Errorf("LRU entry not evicted, Len = %d", store.Len())
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := store.Get("f", Vector{0, 1}, 0.9); ok || store.Len() != 1 {
+		t.Errorf("expired entry returned, Len = %d", store.Len())
+	}
+
+	// Dimension mismatch never matches
+	if _, ok := store.Get("g", Vector{1, 1, 1}, 0.1); ok {
+		t.Error("dimension mismatch matched")
+	}
+}
+
+func TestSemanticCache_Validation(t *testing.T) {
+	cases := map[string]func(){
+		"nil store":   func() { NewSemanticCache(nil, 0.9) },
+		"threshold":   func() { NewSemanticCache(NewMemorySemanticCacheStore(1, 0), 1.5) },
+		"max entries": func() { NewMemorySemanticCacheStore(0, 0) },
+		"ttl":         func() { NewMemorySemanticCacheStore(1, -time.Second) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
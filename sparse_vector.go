@@ -0,0 +1,188 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SparseVector sparse vector (SPLADE, BM42, BM25 term weights)
+// Indices are 0-based and strictly ascending, Values has the same length
+//
+// Stored as pgvector sparsevec ('{1:0.5,7:1.2}/30522', 1-based) and
+// sent to Qdrant as {"indices": [...], "values": [...]}.
+// Similarity is the dot product.
+type SparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+
+	// Dimensions total dimension for pgvector sparsevec(n), 0 = max index + 1
+	Dimensions int `json:"-"`
+}
+
+// NewSparseVector creates a sparse vector from index => weight, zero weights are dropped
+//
+// Example:
+//
+//	sv := xb.NewSparseVector(map[uint32]float32{102: 0.8, 7: 1.3})
+//	// Indices: [7 102], Values: [1.3 0.8]
+func NewSparseVector(weights map[uint32]float32) SparseVector {
+	sv := SparseVector{
+		Indices: make([]uint32, 0, len(weights)),
+		Values:  make([]float32, 0, len(weights)),
+	}
+	for i, w := range weights {
+		if w != 0 {
+			sv.Indices = append(sv.Indices, i)
+		}
+	}
+	sort.Slice(sv.Indices, func(a, b int) bool { return sv.Indices[a] < sv.Indices[b] })
+	for _, i := range sv.Indices {
+		sv.Values = append(sv.Values, weights[i])
+	}
+	return sv
+}
+
+// Len number of non-zero entries
+func (sv SparseVector) Len() int {
+	return len(sv.Indices)
+}
+
+// Validate checks lengths, ascending unique indices and Dimensions
+func (sv SparseVector) Validate() error {
+	if len(sv.Indices) != len(sv.Values) {
+		return fmt.Errorf("sparse vector has %d indices and %d values", len(sv.Indices), len(sv.Values))
+	}
+	for i := 1; i < len(sv.Indices); i++ {
+		if sv.Indices[i] <= sv.Indices[i-1] {
+			return fmt.Errorf("sparse vector indices must be strictly ascending, got %d after %d", sv.Indices[i], sv.Indices[i-1])
+		}
+	}
+	if n := len(sv.Indices); sv.Dimensions > 0 && n > 0 && int(sv.Indices[n-1]) >= sv.Dimensions {
+		return fmt.Errorf("sparse vector index %d out of dimension %d", sv.Indices[n-1], sv.Dimensions)
+	}
+	return nil
+}
+
+// Dot dot product, both vectors must have ascending indices
+func (sv SparseVector) Dot(other SparseVector) float32 {
+	var sum float32
+	i, j := 0, 0
+	for i < len(sv.Indices) && j < len(other.Indices) {
+		switch {
+		case sv.Indices[i] == other.Indices[j]:
+			sum += sv.Values[i] * other.Values[j]
+			i++
+			j++
+		case sv.Indices[i] < other.Indices[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return sum
+}
+
+// Distance negative dot product, lower is more similar (same as InnerProduct for Vector)
+func (sv SparseVector) Distance(other SparseVector) float32 {
+	return -sv.Dot(other)
+}
+
+// dim Dimensions, or max index + 1
+func (sv SparseVector) dim() int {
+	if sv.Dimensions > 0 {
+		return sv.Dimensions
+	}
+	if n := len(sv.Indices); n > 0 {
+		return int(sv.Indices[n-1]) + 1
+	}
+	return 1
+}
+
+// Value implements driver.Valuer interface
+// pgvector sparsevec format: '{1:0.5,3:1.2}/5' (1-based indices)
+func (sv SparseVector) Value() (driver.Value, error) {
+	if err := sv.Validate(); err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, idx := range sv.Indices {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(strconv.FormatUint(uint64(idx)+1, 10))
+		sb.WriteString(":")
+		sb.WriteString(strconv.FormatFloat(float64(sv.Values[i]), 'f', -1, 32))
+	}
+	sb.WriteString("}/")
+	sb.WriteString(strconv.Itoa(sv.dim()))
+	return sb.String(), nil
+}
+
+// Scan implements sql.Scanner interface
+func (sv *SparseVector) Scan(value interface{}) error {
+	var s string
+	switch value := value.(type) {
+	case nil:
+		*sv = SparseVector{}
+		return nil
+	case []byte:
+		s = string(value)
+	case string:
+		s = value
+	default:
+		return fmt.Errorf("unsupported sparse vector type: %T", value)
+	}
+
+	body, dims, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok || !strings.HasPrefix(body, "{") || !strings.HasSuffix(body, "}") {
+		return fmt.Errorf("invalid sparsevec: %q", s)
+	}
+	n, err := strconv.Atoi(dims)
+	if err != nil {
+		return fmt.Errorf("invalid sparsevec dimension: %q", s)
+	}
+	out := SparseVector{Dimensions: n}
+	if body = strings.TrimSpace(body[1 : len(body)-1]); body != "" {
+		for _, pair := range strings.Split(body, ",") {
+			idx, val, ok := strings.Cut(pair, ":")
+			if !ok {
+				return fmt.Errorf("invalid sparsevec element %q", pair)
+			}
+			i, err := strconv.ParseUint(strings.TrimSpace(idx), 10, 32)
+			if err != nil || i == 0 {
+				return fmt.Errorf("invalid sparsevec index %q", idx)
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(val), 32)
+			if err != nil {
+				return fmt.Errorf("invalid sparsevec value %q", val)
+			}
+			out.Indices = append(out.Indices, uint32(i-1))
+			out.Values = append(out.Values, float32(v))
+		}
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*sv = out
+	return nil
+}
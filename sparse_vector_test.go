@@ -0,0 +1,305 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSparseVector_NewAndValidate(t *testing.T) {
+	sv := NewSparseVector(map[uint32]float32{102: 0.8, 7: 1.3, 50: 0})
+	if len(sv.Indices) != 2 || sv.Indices[0] != 7 || sv.Indices[1] != 102 || sv.Values[0] != 1.3 || sv.Values[1] != 0.8 {
+		t.Fatalf("unexpected sparse vector: %+v", sv)
+	}
+	if err := sv.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	invalid := map[string]SparseVector{
+		"length mismatch": {Indices: []uint32{1, 2}, Values: []float32{1}},
+		"unsorted":        {Indices: []uint32{5, 2}, Values: []float32{1, 1}},
+		"duplicate":       {Indices: []uint32{2, 2}, Values: []float32{1, 1}},
+		"out of range":    {Indices: []uint32{9}, Values: []float32{1}, Dimensions: 5},
+	}
+	for name, sv := range invalid {
+		if sv.Validate() == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestSparseVector_DotAndDistance(t *testing.T) {
+	a := SparseVector{Indices: []uint32{1, 3, 8}, Values: []float32{1, 2, 3}}
+	b := SparseVector{Indices: []uint32{3, 4, 8}, Values: []float32{0.5, 9, 2}}
+	if got := a.Dot(b); got != 7 {
+		t.Errorf("Dot = %v, want 7", got)
+	}
+	if got := a.Distance(b); got != -7 {
+		t.Errorf("Distance = %v, want -7", got)
+	}
+	if got := a.Dot(SparseVector{}); got != 0 {
+		t.Errorf("Dot with empty = %v", got)
+	}
+}
+
+func TestSparseVector_ValueAndScan(t *testing.T) {
+	sv := SparseVector{Indices: []uint32{0, 2}, Values: []float32{0.5, 1.25}, Dimensions: 5}
+	v, err := sv.Value()
+	if err != nil || v != "{1:0.5,3:1.25}/5" {
+		t.Fatalf("Value = %v, %v", v, err)
+	}
+
+	// Without Dimensions the dimension is max index + 1
+	v, _ = SparseVector{Indices: []uint32{6}, Values: []float32{2}}.Value()
+	if v != "{7:2}/7" {
+		t.Errorf("Value = %v", v)
+	}
+
+	var scanned SparseVector
+	if err := scanned.Scan([]byte("{1:0.5,3:1.25}/5")); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.Dimensions != 5 || scanned.Indices[1] != 2 || scanned.Values[1] != 1.25 {
+		t.Errorf("unexpected scan: %+v", scanned)
+	}
+	if err := scanned.Scan("{}/3"); err != nil || scanned.Len() != 0 || scanned.Dimensions != 3 {
+		t.Errorf("empty scan: %+v %v", scanned, err)
+	}
+
+	for _, bad := range []interface{}{"[1,2]", "{1:a}/3", "{0:1}/3", "{3:1,2:1}/3", 42} {
+		if err := scanned.Scan(bad); err == nil {
+			t.Errorf("Scan(%v) should fail", bad)
+		}
+	}
+	if _, err := (SparseVector{Indices: []uint32{1}}).Value(); err == nil {
+		t.Error("Value of an invalid sparse vector should fail")
+	}
+}
+
+func TestSparseVectorSearch_SQL(t *testing.T) {
+	sv := SparseVector{Indices: []uint32{2, 7}, Values: []float32{0.5, 1.25}}
+	sql, args := Of(&CodeVectorForQdrant{}).
+		Eq("language", "golang").
+		SparseVectorSearch("text_sparse", sv, 5).
+		Build().
+		SqlOfVectorSearch()
+
+	want := "SELECT *, text_sparse <=> ? AS distance FROM code_vectors WHERE language = ? ORDER BY distance LIMIT 5"
+	if sql != want {
+		t.Errorf("sql = %s\nwant %s", sql, want)
+	}
+	if _, ok := args[0].(SparseVector); !ok || len(args) != 2 {
+		t.Errorf("args = %v", args)
+	}
+
+	sql, args = Of(&CodeVectorForQdrant{}).
+		Insert(func(ib *InsertBuilder) {
+			ib.Set("id", 1).Set("text_sparse", sv)
+		}).
+		Build().
+		SqlOfInsert()
+	if !strings.Contains(sql, "text_sparse") {
+		t.Errorf("insert sql = %s", sql)
+	}
+	if _, ok := args[1].(SparseVector); !ok {
+		t.Errorf("insert args = %v", args)
+	}
+}
+
+func TestSparseVectorSearch_Qdrant(t *testing.T) {
+	sv := SparseVector{Indices: []uint32{2, 7}, Values: []float32{0.5, 1.25}}
+	js, err := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Eq("language", "golang").
+		SparseVectorSearch("text_sparse", sv, 5).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{
+		"vector": {"name": "text_sparse", "vector": {"indices": [2, 7], "values": [0.5, 1.25]}},
+		"limit": 5,
+		"filter": {"must": [{"key": "language", "match": {"value": "golang"}}]},
+		"with_payload": true,
+		"params": {"hnsw_ef": 128}
+	}`)
+
+	// Sparse vectors are named, the unnamed dense vector goes under ""
+	js, err = Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Insert(func(ib *InsertBuilder) {
+			ib.Set("id", 1).Set("vector", Vector{1, 0}).Set("text_sparse", sv).Set("language", "golang")
+		}).
+		Build().
+		JsonOfInsert()
+	if err != nil {
+		t.Fatalf("JsonOfInsert failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{"points": [{
+		"id": 1,
+		"vector": {"": [1, 0], "text_sparse": {"indices": [2, 7], "values": [0.5, 1.25]}},
+		"payload": {"language": "golang"}
+	}]}`)
+
+	_, err = Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Insert(func(ib *InsertBuilder) {
+			ib.Set("id", 1).Set("text_sparse", SparseVector{Indices: []uint32{3, 1}, Values: []float32{1, 1}})
+		}).
+		Build().
+		JsonOfInsert()
+	if err == nil {
+		t.Error("unsorted sparse vector should fail")
+	}
+
+	js, err = Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().
+		Query(func(qb *QueryBuilder) {
+			qb.NearestSparse(sv).Using("text_sparse").Limit(3)
+		}).
+		Build()).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("NearestSparse JsonOfSelect failed: %v", err)
+	}
+	if !strings.Contains(js, `"indices"`) || !strings.Contains(js, `"using": "text_sparse"`) {
+		t.Errorf("unexpected NearestSparse query: %s", js)
+	}
+}
+
+func TestSparseVectorSearch_Hybrid(t *testing.T) {
+	sv := SparseVector{Indices: []uint32{2, 7}, Values: []float32{0.5, 1.25}}
+	built := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Hybrid(func(h *HybridBuilder) {
+			h.Vector("embedding", Vector{1, 0}, 20).
+				Sparse("text_sparse", sv, 30).
+				Limit(5)
+		}).
+		Build()
+
+	js, err := built.JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{
+		"prefetch": [
+			{"query": [1, 0], "params": {"hnsw_ef": 128}, "limit": 20},
+			{"query": {"indices": [2, 7], "values": [0.5, 1.25]}, "using": "text_sparse", "params": {"hnsw_ef": 128}, "limit": 30}
+		],
+		"query": {"fusion": "rrf"},
+		"limit": 5,
+		"with_payload": true
+	}`)
+
+	sql, args, err := built.SqlOfHybridSearch()
+	if err != nil {
+		t.Fatalf("SqlOfHybridSearch failed: %v", err)
+	}
+	if !strings.Contains(sql, "text_sparse <=> ? AS distance") {
+		t.Errorf("sql = %s", sql)
+	}
+	if _, ok := args[1].(SparseVector); !ok {
+		t.Errorf("args = %v", args)
+	}
+
+	// A sparse-only hybrid needs no dense leg, a keyword leg still does
+	sparseOnly := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Hybrid(func(h *HybridBuilder) {
+			h.Sparse("text_sparse", sv).Sparse("title_sparse", sv)
+		}).
+		Build()
+	if _, err := sparseOnly.JsonOfSelect(); err != nil {
+		t.Errorf("sparse-only hybrid failed: %v", err)
+	}
+	keyword := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Hybrid(func(h *HybridBuilder) {
+			h.Keyword("content", "golang").Sparse("text_sparse", sv)
+		}).
+		Build()
+	if _, err := keyword.JsonOfSelect(); err == nil {
+		t.Error("keyword leg without a dense leg should fail")
+	}
+}
+
+func TestSparseVectorSearch_Validation(t *testing.T) {
+	unsorted := SparseVector{Indices: []uint32{3, 1}, Values: []float32{1, 1}}
+	cases := map[string]func(){
+		"SparseVectorSearch": func() { Of(&CodeVectorForQdrant{}).SparseVectorSearch("s", unsorted, 3) },
+		"Hybrid Sparse": func() {
+			Of(&CodeVectorForQdrant{}).Hybrid(func(h *HybridBuilder) { h.Sparse("s", unsorted) })
+		},
+		"NearestSparse": func() {
+			NewQdrantBuilder().Query(func(qb *QueryBuilder) { qb.NearestSparse(SparseVector{}) })
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+
+	// Empty sparse vectors are ignored like empty dense vectors
+	built := Of(&CodeVectorForQdrant{}).SparseVectorSearch("s", SparseVector{}, 3).Build()
+	if findVectorSearchBb(built.Conds) != nil {
+		t.Error("empty sparse vector should be ignored")
+	}
+
+	redis := Of(&CodeVectorForQdrant{}).Custom(NewRedisSearchBuilder().Build()).
+		SparseVectorSearch("s", SparseVector{Indices: []uint32{1}, Values: []float32{1}}, 3).
+		Build()
+	if _, err := redis.JsonOfSelect(); err == nil {
+		t.Error("Redis sparse search should fail")
+	}
+}
+
+func TestMemoryVector_SparseVectorSearch(t *testing.T) {
+	mem := NewMemoryVectorBuilder().HNSW(8, 32).Build()
+	docs := map[int]SparseVector{
+		1: NewSparseVector(map[uint32]float32{1: 1, 5: 2}),
+		2: NewSparseVector(map[uint32]float32{5: 0.5}),
+		3: NewSparseVector(map[uint32]float32{9: 4}),
+	}
+	for id := 1; id <= 3; id++ {
+		_, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+			Insert(func(ib *InsertBuilder) {
+				ib.Set("id", id).Set("text_sparse", docs[id]).Set("embedding", Vector{float32(id), 1})
+			}).
+			Build().
+			MemoryOfExec()
+		if err != nil {
+			t.Fatalf("insert %d failed: %v", id, err)
+		}
+	}
+
+	results, err := MemorySearch[map[string]interface{}](Of(&CodeVectorForQdrant{}).Custom(mem).
+		SparseVectorSearch("text_sparse", NewSparseVector(map[uint32]float32{5: 1, 9: 0.1}), 3).
+		Build())
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	// Dot products: 1 => 2, 2 => 0.5, 3 => 0.4
+	if got := memoryIDs(results); got != "1 2 3 " {
+		t.Fatalf("ids = %s", got)
+	}
+	if results[0].Score != 2 {
+		t.Errorf("score = %v, want 2 (Dot)", results[0].Score)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SqlOfHybridSearch generates hybrid search SQL (PostgreSQL full-text + pgvector)
+// Returns: sql, args, error (FusionDBSF is Qdrant only)
+//
+// One CTE per leg ranks its candidates, the outer query fuses them by id:
+//
+//	WITH leg_0 AS (
+//	    SELECT id, ROW_NUMBER() OVER (ORDER BY score DESC) AS rank, <min-max> AS norm
+//	    FROM (SELECT id, ts_rank(to_tsvector('simple', content), plainto_tsquery('simple', ?)) AS score
+//	          FROM t WHERE to_tsvector('simple', content) @@ plainto_tsquery('simple', ?) AND tenant_id = ?
+//	          ORDER BY score DESC LIMIT 50) c),
+//	leg_1 AS (
+//	    SELECT id, ROW_NUMBER() OVER (ORDER BY distance) AS rank, <min-max> AS norm
+//	    FROM (SELECT id, embedding <-> ? AS distance FROM t WHERE tenant_id = ?
+//	          ORDER BY distance LIMIT 50) c)
+//	SELECT id, SUM(score) AS score FROM (
+//	    SELECT id, 1.0 / (60 + rank) AS score FROM leg_0
+//	    UNION ALL
+//	    SELECT id, 1.0 / (60 + rank) AS score FROM leg_1) fused
+//	GROUP BY id ORDER BY score DESC LIMIT 10
+//
+// Weighted fusion replaces "w / (k + rank)" with "w * norm".
+func (built *Built) SqlOfHybridSearch() (string, []interface{}, error) {
+	params := built.Hybrid()
+	if params == nil {
+		return "", nil, fmt.Errorf("no hybrid search found")
+	}
+	if params.Fusion == FusionDBSF {
+		return "", nil, fmt.Errorf("fusion %q is not supported in SQL, use rrf or weighted", params.Fusion)
+	}
+
+	id := params.IdKey
+	scalarConds := filterScalarConds(built.Conds)
+
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString("WITH ")
+	for i, leg := range params.Legs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(fmt.Sprintf("leg_%d AS (SELECT %s, ", i, id))
+
+		switch leg.Kind {
+		case HybridKeyword:
+			// The config is bound, it may come from request data (per-language choice)
+			tsv := fmt.Sprintf("to_tsvector(?::regconfig, %s)", leg.Field)
+			tsq := "plainto_tsquery(?::regconfig, ?)"
+			sb.WriteString("ROW_NUMBER() OVER (ORDER BY score DESC) AS rank, ")
+			sb.WriteString("COALESCE((score - MIN(score) OVER ()) / NULLIF(MAX(score) OVER () - MIN(score) OVER (), 0), 1) AS norm")
+			sb.WriteString(fmt.Sprintf(" FROM (SELECT %s, ts_rank(%s, %s) AS score FROM %s WHERE %s @@ %s",
+				id, tsv, tsq, built.OrFromSql, tsv, tsq))
+			args = append(args,
+				params.TextConfig, params.TextConfig, leg.Text,
+				params.TextConfig, params.TextConfig, leg.Text)
+			built.writeHybridScalarConds(scalarConds, &sb, &args)
+			sb.WriteString(fmt.Sprintf(" ORDER BY score DESC LIMIT %d) c)", leg.Limit))
+
+		case HybridVector, HybridSparse:
+			sb.WriteString("ROW_NUMBER() OVER (ORDER BY distance) AS rank, ")
+			sb.WriteString("COALESCE((MAX(distance) OVER () - distance) / NULLIF(MAX(distance) OVER () - MIN(distance) OVER (), 0), 1) AS norm")
+			sb.WriteString(fmt.Sprintf(" FROM (SELECT %s, %s %s ? AS distance FROM %s",
+				id, leg.Field, leg.DistanceMetric, built.OrFromSql))
+			if leg.QuerySparse != nil {
+				args = append(args, *leg.QuerySparse) // sparsevec
+			} else {
+				args = append(args, leg.QueryVector)
+			}
+			if len(scalarConds) > 0 {
+				sb.WriteString(" WHERE ")
+				built.toCondSql(scalarConds, &sb, &args, nil)
+			}
+			sb.WriteString(fmt.Sprintf(" ORDER BY distance LIMIT %d) c)", leg.Limit))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf(" SELECT %s, SUM(score) AS score FROM (", id))
+	for i, leg := range params.Legs {
+		if i > 0 {
+			sb.WriteString(" UNION ALL ")
+		}
+		weight := sqlFloat(leg.Weight)
+		if params.Fusion == FusionWeighted {
+			sb.WriteString(fmt.Sprintf("SELECT %s, %s * norm AS score FROM leg_%d", id, weight, i))
+		} else {
+			sb.WriteString(fmt.Sprintf("SELECT %s, %s / (%d + rank) AS score FROM leg_%d", id, weight, params.RRFK, i))
+		}
+	}
+	sb.WriteString(fmt.Sprintf(") fused GROUP BY %s ORDER BY score DESC", id))
+
+	if built.PageCondition != nil && built.PageCondition.Rows > 0 {
+		sb.WriteString(fmt.Sprintf(" LIMIT %d", built.PageCondition.Rows))
+		if built.PageCondition.Page > 1 {
+			sb.WriteString(fmt.Sprintf(" OFFSET %d", (built.PageCondition.Page-1)*built.PageCondition.Rows))
+		}
+	} else {
+		sb.WriteString(fmt.Sprintf(" LIMIT %d", params.Limit))
+	}
+
+	return sb.String(), args, nil
+}
+
+// writeHybridScalarConds appends " AND (scalar conditions)" to a leg that already has a WHERE
+func (built *Built) writeHybridScalarConds(conds []Bb, sb *strings.Builder, args *[]interface{}) {
+	if len(conds) == 0 {
+		return
+	}
+	sb.WriteString(" AND (")
+	built.toCondSql(conds, sb, args, nil)
+	sb.WriteString(")")
+}
+
+// sqlFloat formats a weight as a numeric literal (1 => 1.0, so division is never integer)
+func sqlFloat(f float32) string {
+	s := strconv.FormatFloat(float64(f), 'f', -1, 32)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Bb => Qdrant filter
+// ============================================================================
+
+// buildQdrantFilter builds Qdrant filter, same semantics as the SQL WHERE:
+//   - conditions are AND (must), Ne/Nin/NotLike/NonNull go to must_not
+//   - OR() splits the conditions into groups => should
+//   - Or()/And() sub-builders => nested filter (recursive)
+//   - Qdrant payload conditions (GeoRadius, Datetime, HasId, Nested, ...) => typed conditions
+//
+// Vector and Qdrant-specific operators are skipped.
+// Operators without a Qdrant equivalent (X, Sub, ...) return an error,
+// dropping them would silently return more points than intended.
+func buildQdrantFilter(bbs []Bb) (*QdrantFilter, error) {
+	conds := make([]Bb, 0, len(bbs))
+	for _, bb := range bbs {
+		// ⭐ Skip vector-specific and Qdrant-specific operators (handled separately)
+		if isVectorOp(bb.Op) || isQdrantOp(bb.Op) {
+			continue
+		}
+		conds = append(conds, bb)
+	}
+	return qdrantFilterOf(conds)
+}
+
+// qdrantFilterOf converts one condition level: OR groups => should, single group => must/must_not
+func qdrantFilterOf(bbs []Bb) (*QdrantFilter, error) {
+	groups := splitOrGroups(bbs)
+	if len(groups) == 1 {
+		return qdrantAndFilter(groups[0])
+	}
+
+	filter := &QdrantFilter{}
+	for _, group := range groups {
+		sub, err := qdrantAndFilter(group)
+		if err != nil {
+			return nil, err
+		}
+		if cond, ok := qdrantFilterAsCondition(sub); ok {
+			filter.Should = append(filter.Should, cond)
+		}
+	}
+	return filter, nil
+}
+
+// qdrantAndFilter converts conditions joined by AND
+func qdrantAndFilter(bbs []Bb) (*QdrantFilter, error) {
+	filter := &QdrantFilter{}
+	for _, bb := range bbs {
+		if isPureOperator(bb) {
+			continue
+		}
+
+		// Or() / And() sub-builder => nested filter
+		if (bb.Op == OR || bb.Op == AND) && len(bb.Subs) > 0 {
+			sub, err := qdrantFilterOf(bb.Subs)
+			if err != nil {
+				return nil, err
+			}
+			if len(sub.Should) == 0 && len(sub.MustNot) == 0 {
+				filter.Must = append(filter.Must, sub.Must...) // AND inside AND, flatten
+			} else if cond, ok := qdrantFilterAsCondition(sub); ok {
+				filter.Must = append(filter.Must, cond)
+			}
+			continue
+		}
+
+		cond, negate, err := bbToQdrantCondition(bb)
+		if err != nil {
+			return nil, err
+		}
+		if cond == nil {
+			continue
+		}
+		if negate {
+			filter.MustNot = append(filter.MustNot, *cond)
+		} else {
+			filter.Must = append(filter.Must, *cond)
+		}
+	}
+	return filter, nil
+}
+
+// qdrantFilterAsCondition wraps a filter as a condition, a single must condition is used as is
+func qdrantFilterAsCondition(filter *QdrantFilter) (QdrantCondition, bool) {
+	if nonEmptyQdrantFilter(filter) == nil {
+		return QdrantCondition{}, false
+	}
+	if len(filter.Must) == 1 && len(filter.Should) == 0 && len(filter.MustNot) == 0 {
+		return filter.Must[0], true
+	}
+	return QdrantCondition{QdrantFilter: filter}, true
+}
+
+// bbToQdrantCondition converts Bb to Qdrant condition
+// negate: the condition belongs to must_not
+func bbToQdrantCondition(bb Bb) (cond *QdrantCondition, negate bool, err error) {
+	switch bb.Op {
+	case EQ, NE:
+		return qdrantMatchOrRange(bb.Key, bb.Value), bb.Op == NE, nil
+
+	case IN, NIN:
+		values, err := qdrantInValues(bb)
+		if err != nil || len(values) == 0 {
+			return nil, false, err
+		}
+		return &QdrantCondition{
+			Key:   bb.Key,
+			Match: &QdrantMatchCondition{Any: values},
+		}, bb.Op == NIN, nil
+
+	case GT, GTE, LT, LTE:
+		// Gte("created_at", time.Time) stores "2006-01-02 15:04:05" => datetime range
+		if ts, ok := qdrantDatetime(bb.Value); ok {
+			r := &QdrantRangeCondition{}
+			switch bb.Op {
+			case GT:
+				r.GtTime = ts
+			case GTE:
+				r.GteTime = ts
+			case LT:
+				r.LtTime = ts
+			case LTE:
+				r.LteTime = ts
+			}
+			return &QdrantCondition{Key: bb.Key, Range: r}, false, nil
+		}
+		val, err := toFloat64(bb.Value)
+		if err != nil {
+			return nil, false, fmt.Errorf("qdrant range on %s: %w", bb.Key, err)
+		}
+		r := &QdrantRangeCondition{}
+		switch bb.Op {
+		case GT:
+			r.Gt = &val
+		case GTE:
+			r.Gte = &val
+		case LT:
+			r.Lt = &val
+		case LTE:
+			r.Lte = &val
+		}
+		return &QdrantCondition{Key: bb.Key, Range: r}, false, nil
+
+	case LIKE, NOT_LIKE:
+		// Full-text match, requires a text payload index on the field
+		text := strings.Trim(fmt.Sprint(bb.Value), "%")
+		if text == "" {
+			return nil, false, nil
+		}
+		return &QdrantCondition{
+			Key:   bb.Key,
+			Match: &QdrantMatchCondition{Text: text},
+		}, bb.Op == NOT_LIKE, nil
+
+	case IS_NULL, NON_NULL:
+		// SQL NULL ≈ Qdrant null value or missing/empty field
+		return &QdrantCondition{QdrantFilter: &QdrantFilter{
+			Should: []QdrantCondition{
+				{IsNull: &QdrantFieldRef{Key: bb.Key}},
+				{IsEmpty: &QdrantFieldRef{Key: bb.Key}},
+			},
+		}}, bb.Op == NON_NULL, nil
+
+	case QDRANT_MATCH_EXCEPT:
+		return &QdrantCondition{
+			Key:   bb.Key,
+			Match: &QdrantMatchCondition{Except: bb.Value.([]interface{})},
+		}, false, nil
+
+	case QDRANT_GEO_RADIUS:
+		return &QdrantCondition{Key: bb.Key, GeoRadius: bb.Value.(*QdrantGeoRadius)}, false, nil
+
+	case QDRANT_GEO_BOUNDING_BOX:
+		return &QdrantCondition{Key: bb.Key, GeoBoundingBox: bb.Value.(*QdrantGeoBoundingBox)}, false, nil
+
+	case QDRANT_GEO_POLYGON:
+		return &QdrantCondition{Key: bb.Key, GeoPolygon: bb.Value.(*QdrantGeoPolygon)}, false, nil
+
+	case QDRANT_DATETIME_RANGE:
+		return &QdrantCondition{Key: bb.Key, Range: bb.Value.(*QdrantRangeCondition)}, false, nil
+
+	case QDRANT_VALUES_COUNT:
+		return &QdrantCondition{Key: bb.Key, ValuesCount: bb.Value.(*QdrantValuesCount)}, false, nil
+
+	case QDRANT_IS_EMPTY:
+		return &QdrantCondition{IsEmpty: &QdrantFieldRef{Key: bb.Key}}, false, nil
+
+	case QDRANT_IS_NULL:
+		return &QdrantCondition{IsNull: &QdrantFieldRef{Key: bb.Key}}, false, nil
+
+	case QDRANT_HAS_ID:
+		return &QdrantCondition{HasId: bb.Value.([]PointID)}, false, nil
+
+	case QDRANT_NESTED:
+		sub, err := qdrantFilterOf(bb.Subs)
+		if err != nil {
+			return nil, false, fmt.Errorf("nested %s: %w", bb.Key, err)
+		}
+		if nonEmptyQdrantFilter(sub) == nil {
+			return nil, false, nil
+		}
+		return &QdrantCondition{Nested: &QdrantNested{Key: bb.Key, Filter: *sub}}, false, nil
+
+	case XX:
+		return nil, false, fmt.Errorf("X(%q) raw SQL is not supported in Qdrant filter", bb.Key)
+
+	case SUB:
+		return nil, false, fmt.Errorf("Sub(%q) subquery is not supported in Qdrant filter", bb.Key)
+
+	default:
+		return nil, false, fmt.Errorf("operator %q on %s is not supported in Qdrant filter", bb.Op, bb.Key)
+	}
+}
+
+// qdrantMatchOrRange match.value for keyword/integer/bool, float equality as range gte=lte
+func qdrantMatchOrRange(key string, value interface{}) *QdrantCondition {
+	switch v := value.(type) {
+	case float32, float64:
+		f, _ := toFloat64(v)
+		return &QdrantCondition{Key: key, Range: &QdrantRangeCondition{Gte: &f, Lte: &f}}
+	}
+	return &QdrantCondition{Key: key, Match: &QdrantMatchCondition{Value: value}}
+}
+
+// qdrantDatetime "2006-01-02 15:04:05" (doGLE of time.Time) or RFC3339 => Qdrant datetime
+// Without a zone Qdrant reads the time as UTC
+func qdrantDatetime(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return "", false
+	}
+	if t, err := time.Parse("2006-01-02 15:04:05", s); err == nil {
+		return t.Format("2006-01-02T15:04:05"), true
+	}
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return s, true
+	}
+	return "", false
+}
+
+// qdrantInValues decodes IN values
+// In() stores SQL literals: strings quoted ('golang'), numbers as text (42)
+func qdrantInValues(bb Bb) ([]interface{}, error) {
+	var values []interface{}
+	switch v := bb.Value.(type) {
+	case *[]string:
+		if v == nil {
+			return nil, nil
+		}
+		for _, s := range *v {
+			values = append(values, sqlLiteralValue(s))
+		}
+	case []interface{}:
+		values = v
+	case []string:
+		for _, s := range v {
+			values = append(values, s)
+		}
+	default:
+		return nil, fmt.Errorf("IN operator expects []string or []interface{}, got %T", bb.Value)
+	}
+	return values, nil
+}
+
+// sqlLiteralValue 'abc' => "abc", 42 => int64(42), 1.5 => float64(1.5)
+func sqlLiteralValue(s string) interface{} {
+	if len(s) >= 2 && strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") {
+		return s[1 : len(s)-1]
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// qdrantFilterJSON builds a Qdrant search and returns its filter as JSON
+func qdrantFilterJSON(t *testing.T, fn func(x *BuilderX)) string {
+	t.Helper()
+	x := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build())
+	fn(x)
+	jsonStr, err := x.VectorSearch("embedding", Vector{0.1, 0.2}, 10).Build().JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	var req map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(jsonStr), &req); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	return string(req["filter"])
+}
+
+func TestQdrantFilter_MustNot(t *testing.T) {
+	got := qdrantFilterJSON(t, func(x *BuilderX) {
+		x.Eq("language", "golang").
+			Ne("layer", "dao").
+			In("layer", "service", "controller").
+			Nin("id", 7, 8).
+			NotLike("content", "deprecated")
+	})
+	assertJSONEqual(t, got, `{
+		"must": [
+			{"key": "language", "match": {"value": "golang"}},
+			{"key": "layer", "match": {"any": ["service", "controller"]}}
+		],
+		"must_not": [
+			{"key": "layer", "match": {"value": "dao"}},
+			{"key": "id", "match": {"any": [7, 8]}},
+			{"key": "content", "match": {"text": "deprecated"}}
+		]
+	}`)
+}
+
+func TestQdrantFilter_OrGroupsAndSubs(t *testing.T) {
+	got := qdrantFilterJSON(t, func(x *BuilderX) {
+		x.Eq("language", "golang").
+			Or(func(cb *CondBuilder) {
+				cb.Eq("layer", "service").OR().Gte("stars", 100)
+			}).
+			And(func(cb *CondBuilder) {
+				cb.Eq("a", 1).Eq("b", 2)
+			})
+	})
+	assertJSONEqual(t, got, `{
+		"must": [
+			{"key": "language", "match": {"value": "golang"}},
+			{"should": [
+				{"key": "layer", "match": {"value": "service"}},
+				{"key": "stars", "range": {"gte": 100}}
+			]},
+			{"key": "a", "match": {"value": 1}},
+			{"key": "b", "match": {"value": 2}}
+		]
+	}`)
+
+	// Top-level OR(): x AND y OR z => should [ {must [x, y]}, z ]
+	got = qdrantFilterJSON(t, func(x *BuilderX) {
+		x.Eq("x", 1).Ne("y", 2).OR().Eq("z", 3)
+	})
+	assertJSONEqual(t, got, `{
+		"should": [
+			{"must": [{"key": "x", "match": {"value": 1}}], "must_not": [{"key": "y", "match": {"value": 2}}]},
+			{"key": "z", "match": {"value": 3}}
+		]
+	}`)
+}
+
+func TestQdrantFilter_NestedRecursive(t *testing.T) {
+	got := qdrantFilterJSON(t, func(x *BuilderX) {
+		x.Or(func(cb *CondBuilder) {
+			cb.Eq("a", 1).
+				OR().
+				And(func(cb *CondBuilder) {
+					cb.Eq("b", 2).Or(func(cb *CondBuilder) {
+						cb.Eq("c", 3).OR().Eq("d", 4)
+					})
+				})
+		})
+	})
+	assertJSONEqual(t, got, `{
+		"must": [
+			{"should": [
+				{"key": "a", "match": {"value": 1}},
+				{"must": [
+					{"key": "b", "match": {"value": 2}},
+					{"should": [
+						{"key": "c", "match": {"value": 3}},
+						{"key": "d", "match": {"value": 4}}
+					]}
+				]}
+			]}
+		]
+	}`)
+}
+
+func TestQdrantFilter_NullLikeAndFloat(t *testing.T) {
+	got := qdrantFilterJSON(t, func(x *BuilderX) {
+		x.IsNull("deleted_at").
+			NonNull("owner").
+			Like("content", "retry").
+			Eq("ratio", 0.5)
+	})
+	assertJSONEqual(t, got, `{
+		"must": [
+			{"should": [{"is_null": {"key": "deleted_at"}}, {"is_empty": {"key": "deleted_at"}}]},
+			{"key": "content", "match": {"text": "retry"}},
+			{"key": "ratio", "range": {"gte": 0.5, "lte": 0.5}}
+		],
+		"must_not": [
+			{"should": [{"is_null": {"key": "owner"}}, {"is_empty": {"key": "owner"}}]}
+		]
+	}`)
+}
+
+func TestQdrantFilter_UnsupportedErrors(t *testing.T) {
+	cases := map[string]func(x *BuilderX){
+		"raw X":          func(x *BuilderX) { x.X("age > 18") },
+		"non-numeric gt": func(x *BuilderX) { x.Gt("name", "bob") },
+		"nested raw X": func(x *BuilderX) {
+			x.Or(func(cb *CondBuilder) { cb.Eq("a", 1).OR().X("b = c") })
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			x := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build())
+			fn(x)
+			_, err := x.VectorSearch("embedding", Vector{0.1}, 10).Build().JsonOfSelect()
+			if err == nil {
+				t.Fatalf("expected error, unsupported conditions must not be dropped")
+			}
+			if !strings.Contains(err.Error(), "not supported") && !strings.Contains(err.Error(), "range") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestQdrantFilter_DeleteByFilter(t *testing.T) {
+	built := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		Ne("language", "golang").
+		Build()
+
+	jsonStr, err := built.JsonOfDelete()
+	if err != nil {
+		t.Fatalf("JsonOfDelete failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{"filter": {"must_not": [{"key": "language", "match": {"value": "golang"}}]}}`)
+
+	byIds := Of(&CodeVectorForQdrant{}).
+		Custom(NewQdrantBuilder().Build()).
+		In("id", 1, 2).
+		Build()
+	jsonStr, err = byIds.JsonOfDelete()
+	if err != nil {
+		t.Fatalf("JsonOfDelete failed: %v", err)
+	}
+	assertJSONEqual(t, jsonStr, `{"points": [1, 2]}`)
+}
@@ -19,6 +19,7 @@ package xb
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // ============================================================================
@@ -35,12 +36,17 @@ type QdrantRequest interface {
 
 	// GetQdrantFilter gets Qdrant-specific filter (type-safe)
 	GetQdrantFilter() **QdrantFilter
+
+	// GetWithPayload gets with_payload (true, false, or []string)
+	GetWithPayload() *interface{}
 }
 
 // QdrantSearchRequest Qdrant search request structure
 // Documentation: https://qdrant.tech/documentation/concepts/search/
 type QdrantSearchRequest struct {
 	Vector         []float32           `json:"vector"`
+	VectorName     string              `json:"-"` // Named vector: "vector": {"name": ..., "vector": [...]}
+	SparseVector   *SparseVector       `json:"-"` // Named sparse vector, VectorName is its name
 	Limit          int                 `json:"limit"`
 	Filter         *QdrantFilter       `json:"filter,omitempty"`
 	WithPayload    interface{}         `json:"with_payload,omitempty"` // true, false, or []string
@@ -50,6 +56,37 @@ type QdrantSearchRequest struct {
 	Params         *QdrantSearchParams `json:"params,omitempty"`
 }
 
+// QdrantNamedVector {"name": "title", "vector": [...]}
+type QdrantNamedVector struct {
+	Name   string    `json:"name"`
+	Vector []float32 `json:"vector"`
+}
+
+// QdrantNamedSparseVector {"name": "text", "vector": {"indices": [...], "values": [...]}}
+type QdrantNamedSparseVector struct {
+	Name   string       `json:"name"`
+	Vector SparseVector `json:"vector"`
+}
+
+// MarshalJSON writes the vector as QdrantNamedVector when VectorName is set,
+// as QdrantNamedSparseVector when SparseVector is set
+func (r QdrantSearchRequest) MarshalJSON() ([]byte, error) {
+	type plain QdrantSearchRequest
+	if r.SparseVector != nil {
+		return json.Marshal(struct {
+			plain
+			Vector QdrantNamedSparseVector `json:"vector"`
+		}{plain(r), QdrantNamedSparseVector{Name: r.VectorName, Vector: *r.SparseVector}})
+	}
+	if r.VectorName == "" {
+		return json.Marshal(plain(r))
+	}
+	return json.Marshal(struct {
+		plain
+		Vector QdrantNamedVector `json:"vector"`
+	}{plain(r), QdrantNamedVector{Name: r.VectorName, Vector: r.Vector}})
+}
+
 // Implements VectorDBRequest interface (common)
 func (r *QdrantSearchRequest) GetScoreThreshold() **float32 {
 	return &r.ScoreThreshold
@@ -72,6 +109,10 @@ func (r *QdrantSearchRequest) GetQdrantFilter() **QdrantFilter {
 	return &r.Filter
 }
 
+func (r *QdrantSearchRequest) GetWithPayload() *interface{} {
+	return &r.WithPayload
+}
+
 // QdrantFilter Qdrant filter
 type QdrantFilter struct {
 	Must    []QdrantCondition `json:"must,omitempty"`
@@ -80,38 +121,157 @@ type QdrantFilter struct {
 }
 
 // QdrantCondition Qdrant condition
+// A nested filter (Or()/And() sub-builders) is also a condition: its must/should/must_not are inlined
+// Documentation: https://qdrant.tech/documentation/concepts/filtering/
 type QdrantCondition struct {
-	Key   string                `json:"key,omitempty"`
-	Match *QdrantMatchCondition `json:"match,omitempty"`
-	Range *QdrantRangeCondition `json:"range,omitempty"`
+	Key            string                `json:"key,omitempty"`
+	Match          *QdrantMatchCondition `json:"match,omitempty"`
+	Range          *QdrantRangeCondition `json:"range,omitempty"`
+	GeoRadius      *QdrantGeoRadius      `json:"geo_radius,omitempty"`
+	GeoBoundingBox *QdrantGeoBoundingBox `json:"geo_bounding_box,omitempty"`
+	GeoPolygon     *QdrantGeoPolygon     `json:"geo_polygon,omitempty"`
+	ValuesCount    *QdrantValuesCount    `json:"values_count,omitempty"`
+	IsNull         *QdrantFieldRef       `json:"is_null,omitempty"`
+	IsEmpty        *QdrantFieldRef       `json:"is_empty,omitempty"`
+	HasId          []PointID             `json:"has_id,omitempty"`
+	Nested         *QdrantNested         `json:"nested,omitempty"`
+	*QdrantFilter
+}
+
+// QdrantFieldRef {"key": "field"}, used by is_null / is_empty
+type QdrantFieldRef struct {
+	Key string `json:"key"`
 }
 
 // QdrantMatchCondition Qdrant exact match condition
 type QdrantMatchCondition struct {
-	Value interface{}   `json:"value,omitempty"`
-	Any   []interface{} `json:"any,omitempty"`
+	Value  interface{}   `json:"value,omitempty"`
+	Any    []interface{} `json:"any,omitempty"`
+	Except []interface{} `json:"except,omitempty"`
+	Text   string        `json:"text,omitempty"` // Full-text match (requires a text payload index)
 }
 
 // QdrantRangeCondition Qdrant range condition
+// Either numeric bounds (Gt, Gte, Lt, Lte) or datetime bounds (GtTime, ..., RFC3339), not both
 type QdrantRangeCondition struct {
 	Gt  *float64 `json:"gt,omitempty"`
 	Gte *float64 `json:"gte,omitempty"`
 	Lt  *float64 `json:"lt,omitempty"`
 	Lte *float64 `json:"lte,omitempty"`
+
+	GtTime  string `json:"-"`
+	GteTime string `json:"-"`
+	LtTime  string `json:"-"`
+	LteTime string `json:"-"`
+}
+
+// IsDatetime the range has datetime bounds
+func (r QdrantRangeCondition) IsDatetime() bool {
+	return r.GtTime != "" || r.GteTime != "" || r.LtTime != "" || r.LteTime != ""
+}
+
+// MarshalJSON both kinds are written as "range": {"gte": ...}
+func (r QdrantRangeCondition) MarshalJSON() ([]byte, error) {
+	if !r.IsDatetime() {
+		type numericRange QdrantRangeCondition
+		return json.Marshal(numericRange(r))
+	}
+	return json.Marshal(struct {
+		Gt  string `json:"gt,omitempty"`
+		Gte string `json:"gte,omitempty"`
+		Lt  string `json:"lt,omitempty"`
+		Lte string `json:"lte,omitempty"`
+	}{r.GtTime, r.GteTime, r.LtTime, r.LteTime})
+}
+
+// UnmarshalJSON numbers go to the numeric bounds, strings to the datetime bounds
+func (r *QdrantRangeCondition) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	bounds := []struct {
+		key  string
+		num  **float64
+		time *string
+	}{
+		{"gt", &r.Gt, &r.GtTime},
+		{"gte", &r.Gte, &r.GteTime},
+		{"lt", &r.Lt, &r.LtTime},
+		{"lte", &r.Lte, &r.LteTime},
+	}
+	for _, b := range bounds {
+		switch v := raw[b.key].(type) {
+		case nil:
+		case float64:
+			f := v
+			*b.num = &f
+		case string:
+			*b.time = v
+		default:
+			return fmt.Errorf("invalid range bound %s: %v", b.key, v)
+		}
+	}
+	return nil
+}
+
+// QdrantGeoPoint geo point
+type QdrantGeoPoint struct {
+	Lon float64 `json:"lon"`
+	Lat float64 `json:"lat"`
+}
+
+// QdrantGeoRadius points within radius (meters) of center
+type QdrantGeoRadius struct {
+	Center QdrantGeoPoint `json:"center"`
+	Radius float64        `json:"radius"`
+}
+
+// QdrantGeoBoundingBox points inside the rectangle
+type QdrantGeoBoundingBox struct {
+	TopLeft     QdrantGeoPoint `json:"top_left"`
+	BottomRight QdrantGeoPoint `json:"bottom_right"`
+}
+
+// QdrantGeoLineString closed ring of points (first == last)
+type QdrantGeoLineString struct {
+	Points []QdrantGeoPoint `json:"points"`
+}
+
+// QdrantGeoPolygon points inside exterior and outside all interiors (holes)
+type QdrantGeoPolygon struct {
+	Exterior  QdrantGeoLineString   `json:"exterior"`
+	Interiors []QdrantGeoLineString `json:"interiors,omitempty"`
+}
+
+// QdrantValuesCount number of values of an array payload field
+type QdrantValuesCount struct {
+	Gt  *int `json:"gt,omitempty"`
+	Gte *int `json:"gte,omitempty"`
+	Lt  *int `json:"lt,omitempty"`
+	Lte *int `json:"lte,omitempty"`
+}
+
+// QdrantNested filter applied to each element of an array of objects
+// Keys in Filter are relative to the element
+type QdrantNested struct {
+	Key    string       `json:"key"`
+	Filter QdrantFilter `json:"filter"`
 }
 
 // QdrantSearchParams Qdrant search parameters
 type QdrantSearchParams struct {
-	HnswEf      int  `json:"hnsw_ef,omitempty"`
-	Exact       bool `json:"exact,omitempty"`
-	IndexedOnly bool `json:"indexed_only,omitempty"`
+	HnswEf       int                       `json:"hnsw_ef,omitempty"`
+	Exact        bool                      `json:"exact,omitempty"`
+	IndexedOnly  bool                      `json:"indexed_only,omitempty"`
+	Quantization *QdrantQuantizationParams `json:"quantization,omitempty"`
 }
 
 // QdrantRecommendRequest Qdrant recommend request structure (v0.10.0)
 // Documentation: https://qdrant.tech/documentation/concepts/explore/#recommendation-api
 type QdrantRecommendRequest struct {
-	Positive       []int64             `json:"positive"`           // Positive sample ID list
-	Negative       []int64             `json:"negative,omitempty"` // Negative sample ID list (optional)
+	Positive       []QdrantExample     `json:"positive,omitempty"` // Positive examples: ids or vectors
+	Negative       []QdrantExample     `json:"negative,omitempty"` // Negative examples (optional)
 	Limit          int                 `json:"limit"`
 	Filter         *QdrantFilter       `json:"filter,omitempty"`
 	WithPayload    interface{}         `json:"with_payload,omitempty"` // true, false, or []string
@@ -119,7 +279,117 @@ type QdrantRecommendRequest struct {
 	ScoreThreshold *float32            `json:"score_threshold,omitempty"`
 	Offset         int                 `json:"offset,omitempty"`
 	Params         *QdrantSearchParams `json:"params,omitempty"`
-	Strategy       string              `json:"strategy,omitempty"` // "average_vector" or "best_score"
+	Strategy       string              `json:"strategy,omitempty"` // "average_vector", "best_score" or "sum_scores"
+	Using          string              `json:"using,omitempty"`    // Named vector to search
+	LookupFrom     *QdrantLookupFrom   `json:"lookup_from,omitempty"`
+}
+
+// QdrantRecommendStrategy how Qdrant combines recommend examples
+type QdrantRecommendStrategy string
+
+const (
+	// QdrantAverageVector averages the examples into one query vector (Qdrant default)
+	QdrantAverageVector QdrantRecommendStrategy = "average_vector"
+	// QdrantBestScore scores each candidate against every example, negatives only is allowed
+	QdrantBestScore QdrantRecommendStrategy = "best_score"
+	// QdrantSumScores sums the scores against all examples, negatives only is allowed
+	QdrantSumScores QdrantRecommendStrategy = "sum_scores"
+)
+
+// QdrantExample recommend / discover example: a point id or a raw vector
+// JSON: the id (number or UUID string) or the vector array
+type QdrantExample struct {
+	ID     PointID
+	Vector Vector // non-nil: vector example, ID is ignored
+}
+
+// IsVector the example is a raw vector
+func (e QdrantExample) IsVector() bool {
+	return e.Vector != nil
+}
+
+// MarshalJSON id => number or string, vector => array
+func (e QdrantExample) MarshalJSON() ([]byte, error) {
+	if e.Vector != nil {
+		return json.Marshal([]float32(e.Vector))
+	}
+	return e.ID.MarshalJSON()
+}
+
+// UnmarshalJSON array => vector, otherwise point id
+func (e *QdrantExample) UnmarshalJSON(data []byte) error {
+	if s := strings.TrimSpace(string(data)); strings.HasPrefix(s, "[") {
+		var vec Vector
+		if err := json.Unmarshal(data, &vec); err != nil {
+			return err
+		}
+		*e = QdrantExample{Vector: vec}
+		return nil
+	}
+	var id PointID
+	if err := id.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*e = QdrantExample{ID: id}
+	return nil
+}
+
+// ParseQdrantExample converts v to QdrantExample
+// Accepts QdrantExample, Vector, []float32, []float64 and everything ParsePointID accepts
+func ParseQdrantExample(v interface{}) (QdrantExample, error) {
+	switch e := v.(type) {
+	case QdrantExample:
+		return e, nil
+	case Vector:
+		return vectorExample(e)
+	case []float32:
+		return vectorExample(e)
+	case []float64:
+		vec := make(Vector, len(e))
+		for i, f := range e {
+			vec[i] = float32(f)
+		}
+		return vectorExample(vec)
+	}
+	id, err := ParsePointID(v)
+	if err != nil {
+		return QdrantExample{}, fmt.Errorf("example must be a point id or a vector: %w", err)
+	}
+	return QdrantExample{ID: id}, nil
+}
+
+func vectorExample(vec []float32) (QdrantExample, error) {
+	if len(vec) == 0 {
+		return QdrantExample{}, fmt.Errorf("vector example must not be empty")
+	}
+	return QdrantExample{Vector: append(Vector(nil), vec...)}, nil
+}
+
+// mustQdrantExamples converts builder input examples, panics on invalid examples
+func mustQdrantExamples(method string, vs []interface{}) []QdrantExample {
+	result := make([]QdrantExample, 0, len(vs))
+	for _, v := range vs {
+		e, err := ParseQdrantExample(v)
+		if err != nil {
+			panic(fmt.Sprintf("%s: %v", method, err))
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// QdrantLookupFrom takes id examples from another collection
+// Vector: named vector of that collection, "" for the default vector
+type QdrantLookupFrom struct {
+	Collection string `json:"collection"`
+	Vector     string `json:"vector,omitempty"`
+}
+
+// QdrantContextPair discover context pair
+// Qdrant steers results to be closer to Positive than to Negative
+type QdrantContextPair struct {
+	Positive QdrantExample `json:"positive"`
+	Negative QdrantExample `json:"negative"`
 }
 
 // Implements VectorDBRequest interface (common)
@@ -144,10 +414,15 @@ func (r *QdrantRecommendRequest) GetQdrantFilter() **QdrantFilter {
 	return &r.Filter
 }
 
+func (r *QdrantRecommendRequest) GetWithPayload() *interface{} {
+	return &r.WithPayload
+}
+
 // QdrantScrollRequest Qdrant Scroll request structure (v0.10.0)
 // Documentation: https://qdrant.tech/documentation/concepts/points/#scroll-points
 type QdrantScrollRequest struct {
-	ScrollID    string        `json:"scroll_id,omitempty"`
+	ScrollID    string        `json:"scroll_id,omitempty"` // Deprecated: set by ScrollID(), not part of the Qdrant API
+	Offset      *PointID      `json:"offset,omitempty"`    // First point id of the page, nil: first page
 	Limit       int           `json:"limit,omitempty"`
 	Filter      *QdrantFilter `json:"filter,omitempty"`
 	WithPayload interface{}   `json:"with_payload,omitempty"`
@@ -176,10 +451,17 @@ func (r *QdrantScrollRequest) GetQdrantFilter() **QdrantFilter {
 	return &r.Filter
 }
 
+func (r *QdrantScrollRequest) GetWithPayload() *interface{} {
+	return &r.WithPayload
+}
+
 // QdrantDiscoverRequest Qdrant Discover request structure (v0.10.0)
 // Documentation: https://qdrant.tech/documentation/concepts/explore/#discovery-api
 type QdrantDiscoverRequest struct {
-	Context        []int64             `json:"context"` // Context sample ID list
+	Target         *QdrantExample      `json:"target,omitempty"` // Optional, without target it is a context search
+	Context        []QdrantContextPair `json:"context"`
+	Using          string              `json:"using,omitempty"`
+	LookupFrom     *QdrantLookupFrom   `json:"lookup_from,omitempty"`
 	Limit          int                 `json:"limit"`
 	Filter         *QdrantFilter       `json:"filter,omitempty"`
 	WithPayload    interface{}         `json:"with_payload,omitempty"` // true, false, or []string
@@ -211,6 +493,10 @@ func (r *QdrantDiscoverRequest) GetQdrantFilter() **QdrantFilter {
 	return &r.Filter
 }
 
+func (r *QdrantDiscoverRequest) GetWithPayload() *interface{} {
+	return &r.WithPayload
+}
+
 func ensureQdrantAdvanced(built *Built) *Built {
 	if built == nil {
 		return nil
@@ -285,26 +571,25 @@ func (built *Built) toQdrantRecommendJSON() (string, error) {
 
 	// Build recommend request
 	req := &QdrantRecommendRequest{
-		Positive:    recommendData["positive"].([]int64),
-		Negative:    []int64{},
 		Limit:       recommendData["limit"].(int),
 		WithPayload: true,
 		WithVector:  false,
 	}
-
-	// Handle negative samples (optional)
-	if negative, ok := recommendData["negative"].([]int64); ok && len(negative) > 0 {
-		req.Negative = negative
-	}
+	req.Positive, _ = recommendData["positive"].([]QdrantExample)
+	req.Negative, _ = recommendData["negative"].([]QdrantExample)
+	req.Strategy, _ = recommendData["strategy"].(string)
+	req.Using, _ = recommendData["using"].(string)
+	req.LookupFrom, _ = recommendData["lookup_from"].(*QdrantLookupFrom)
 
 	// ⭐ Use unified parameter application function
 	applyQdrantParams(built.Conds, req)
 
 	// Apply filter
 	filter, err := buildQdrantFilter(built.Conds)
-	if err == nil && (len(filter.Must) > 0 || len(filter.Should) > 0 || len(filter.MustNot) > 0) {
-		req.Filter = filter
+	if err != nil {
+		return "", err
 	}
+	req.Filter = nonEmptyQdrantFilter(filter)
 
 	// ⭐ Use unified serialization function
 	return mergeAndSerialize(req, built.Conds)
@@ -316,7 +601,7 @@ func (built *Built) toQdrantRecommendJSON() (string, error) {
 // Example output:
 //
 //	{
-//	  "scroll_id": "xxxx-yyyy-zzzz",
+//	  "offset": 42,
 //	  "limit": 100,
 //	  "filter": {...}
 //	}
@@ -327,25 +612,31 @@ func (built *Built) toQdrantScrollJSON() (string, error) {
 	// Find Scroll ID
 	scrollBb := findScrollBb(built.Conds)
 	if scrollBb == nil {
-		return "", fmt.Errorf("no scroll_id found")
+		return "", fmt.Errorf("no scroll found")
 	}
 
 	// Build Scroll request
 	req := &QdrantScrollRequest{
-		ScrollID:    scrollBb.Value.(string),
 		Limit:       100, // Default value
 		WithPayload: true,
 		WithVector:  false,
 	}
+	switch v := scrollBb.Value.(type) {
+	case string:
+		req.ScrollID = v
+	case *PointID:
+		req.Offset = v
+	}
 
 	// ⭐ Use unified parameter application function (Scroll supports WithVector)
 	applyQdrantParams(built.Conds, req)
 
 	// Apply filter
 	filter, err := buildQdrantFilter(built.Conds)
-	if err == nil && (len(filter.Must) > 0 || len(filter.Should) > 0 || len(filter.MustNot) > 0) {
-		req.Filter = filter
+	if err != nil {
+		return "", err
 	}
+	req.Filter = nonEmptyQdrantFilter(filter)
 
 	// ⭐ Use unified serialization function
 	return mergeAndSerialize(req, built.Conds)
@@ -387,7 +678,8 @@ func findDiscoverBb(bbs []Bb) *Bb {
 // Example output:
 //
 //	{
-//	  "context": [101, 102, 103],
+//	  "target": 100,
+//	  "context": [{"positive": 101, "negative": 102}],
 //	  "limit": 20,
 //	  "filter": {...}
 //	}
@@ -405,20 +697,24 @@ func (built *Built) toQdrantDiscoverJSON() (string, error) {
 
 	// Build discover request
 	req := &QdrantDiscoverRequest{
-		Context:     discoverData["context"].([]int64),
+		Context:     discoverData["context"].([]QdrantContextPair),
 		Limit:       discoverData["limit"].(int),
 		WithPayload: true,
 		WithVector:  false,
 	}
+	req.Target, _ = discoverData["target"].(*QdrantExample)
+	req.Using, _ = discoverData["using"].(string)
+	req.LookupFrom, _ = discoverData["lookup_from"].(*QdrantLookupFrom)
 
 	// ⭐ Use unified parameter application function
 	applyQdrantParams(built.Conds, req)
 
 	// Apply filter
 	filter, err := buildQdrantFilter(built.Conds)
-	if err == nil && (len(filter.Must) > 0 || len(filter.Should) > 0 || len(filter.MustNot) > 0) {
-		req.Filter = filter
+	if err != nil {
+		return "", err
 	}
+	req.Filter = nonEmptyQdrantFilter(filter)
 
 	// ⭐ Use unified serialization function
 	return mergeAndSerialize(req, built.Conds)
@@ -435,14 +731,24 @@ func (built *Built) ToQdrantRequest() (*QdrantSearchRequest, error) {
 	}
 
 	params := vectorBb.Value.(VectorSearchParams)
+	if params.QueryMultiVector != nil {
+		return nil, fmt.Errorf("MultiVectorSearch(%q) is only supported by the Qdrant query API, not by search or groups", vectorBb.Key)
+	}
 
 	// Build request
 	req := &QdrantSearchRequest{
 		Vector:      params.QueryVector,
-		Limit:       params.TopK,
+		Limit:       params.fetchK(),
 		WithPayload: true,
 		WithVector:  false,
 	}
+	if qdrantCustom, ok := built.Custom.(*QdrantCustom); ok && qdrantCustom.NamedVectors {
+		req.VectorName = vectorBb.Key
+	}
+	if params.QuerySparseVector != nil {
+		req.SparseVector = params.QuerySparseVector
+		req.VectorName = vectorBb.Key
+	}
 
 	// ⭐ Diversity handling: if diversity is enabled, need to over-fetch
 	if params.Diversity != nil && params.Diversity.Enabled {
@@ -453,7 +759,7 @@ func (built *Built) ToQdrantRequest() (*QdrantSearchRequest, error) {
 		req.Limit = params.TopK * factor
 
 		// Note: Qdrant doesn't natively support diversity, needs application-layer processing
-		// Here we just fetch more results, application layer needs to filter later
+		// Here we just fetch more results, Diversify() selects the final TopK
 	}
 
 	// Build filter
@@ -491,6 +797,12 @@ func (built *Built) ToQdrantRequest() (*QdrantSearchRequest, error) {
 	}
 	req.WithVector = defaultWithVector
 
+	// ⭐ MMR / min-distance diversity compares result vectors, Diversify() needs them
+	if params.Diversity != nil && params.Diversity.Enabled &&
+		(params.Diversity.Strategy == DiversityByMMR || params.Diversity.Strategy == DiversityByDistance) {
+		req.WithVector = true
+	}
+
 	// ⭐ Apply Qdrant-specific configuration (extracted from Conds, will override defaults)
 	applyQdrantSpecificConfig(built.Conds, req)
 
@@ -511,21 +823,20 @@ func (built *Built) ToQdrantRequest() (*QdrantSearchRequest, error) {
 		}
 	}
 
+	// ⭐ Rerank: ApplyRerank() keeps TopN of the candidates
+	if params.Rerank != nil && req.Limit < params.Rerank.Candidates {
+		req.Limit = params.Rerank.Candidates
+	}
+
 	return req, nil
 }
 
 // applyQdrantSpecificConfig extracts Qdrant-specific configuration from Bb
 func applyQdrantSpecificConfig(bbs []Bb, req *QdrantSearchRequest) {
+	applyQdrantSearchParams(bbs, req.Params)
+	applyQdrantWithPayload(bbs, req)
 	for _, bb := range bbs {
 		switch bb.Op {
-		case QDRANT_HNSW_EF:
-			if ef, ok := bb.Value.(int); ok {
-				req.Params.HnswEf = ef
-			}
-		case QDRANT_EXACT:
-			if exact, ok := bb.Value.(bool); ok {
-				req.Params.Exact = exact
-			}
 		case QDRANT_SCORE_THRESHOLD:
 			if threshold, ok := bb.Value.(float32); ok {
 				req.ScoreThreshold = &threshold
@@ -544,40 +855,9 @@ func applyQdrantSpecificConfig(bbs []Bb, req *QdrantSearchRequest) {
 	}
 }
 
-// buildQdrantFilter builds Qdrant filter
-func buildQdrantFilter(bbs []Bb) (*QdrantFilter, error) {
-	filter := &QdrantFilter{
-		Must: []QdrantCondition{},
-	}
-
-	for _, bb := range bbs {
-		// ⭐ Skip vector-specific operators (handled separately)
-		if isVectorOp(bb.Op) {
-			continue
-		}
-
-		// ⭐ Skip Qdrant-specific operators (handled separately)
-		if isQdrantOp(bb.Op) {
-			continue
-		}
-
-		cond, err := bbToQdrantCondition(bb)
-		if err != nil {
-			// ⭐ Key: unsupported operations don't error, just ignore
-			continue
-		}
-
-		if cond != nil {
-			filter.Must = append(filter.Must, *cond)
-		}
-	}
-
-	return filter, nil
-}
-
 // isVectorOp checks if operator is vector operator
 func isVectorOp(op string) bool {
-	return op == VECTOR_SEARCH || op == VECTOR_DISTANCE_FILTER
+	return op == VECTOR_SEARCH || op == VECTOR_DISTANCE_FILTER || op == HYBRID_SEARCH
 }
 
 // isQdrantOp checks if operator is Qdrant-specific operator
@@ -586,126 +866,43 @@ func isQdrantOp(op string) bool {
 		op == QDRANT_EXACT ||
 		op == QDRANT_SCORE_THRESHOLD ||
 		op == QDRANT_WITH_VECTOR ||
+		op == QDRANT_INDEXED_ONLY ||
+		op == QDRANT_QUANTIZATION ||
+		op == QDRANT_WITH_PAYLOAD ||
+		op == QDRANT_RECOMMEND ||
+		op == QDRANT_SCROLL ||
+		op == QDRANT_BATCH_SEARCH ||
+		op == QDRANT_DISCOVER ||
+		op == QDRANT_QUERY ||
+		op == QDRANT_GROUP_SIZE ||
+		op == QDRANT_WITH_LOOKUP ||
+		op == QDRANT_FACET ||
 		op == QDRANT_XX
 }
 
-// bbToQdrantCondition converts Bb to Qdrant condition
-func bbToQdrantCondition(bb Bb) (*QdrantCondition, error) {
-	switch bb.Op {
-	case EQ:
-		return &QdrantCondition{
-			Key: bb.Key,
-			Match: &QdrantMatchCondition{
-				Value: bb.Value,
-			},
-		}, nil
-
-	case NE:
-		// Note: Qdrant's != needs to use must_not
-		// Simplified handling here, caller needs to handle at upper level
-		return nil, fmt.Errorf("NE not directly supported, use must_not")
-
-	case IN:
-		// IN converts to match.any
-		// Note: IN's value is *[]string
-		var anyValues []interface{}
-
-		switch v := bb.Value.(type) {
-		case *[]string:
-			if v == nil {
-				return nil, nil
-			}
-			for _, s := range *v {
-				anyValues = append(anyValues, s)
-			}
-		case []interface{}:
-			anyValues = v
-		case []string:
-			for _, s := range v {
-				anyValues = append(anyValues, s)
-			}
-		default:
-			return nil, fmt.Errorf("IN operator expects []string or []interface{}, got %T", bb.Value)
-		}
-
-		if len(anyValues) == 0 {
-			return nil, nil
-		}
-
-		return &QdrantCondition{
-			Key: bb.Key,
-			Match: &QdrantMatchCondition{
-				Any: anyValues,
-			},
-		}, nil
-
-	case GT:
-		val, err := toFloat64(bb.Value)
-		if err != nil {
-			return nil, err
-		}
-		return &QdrantCondition{
-			Key: bb.Key,
-			Range: &QdrantRangeCondition{
-				Gt: &val,
-			},
-		}, nil
-
-	case GTE:
-		val, err := toFloat64(bb.Value)
-		if err != nil {
-			return nil, err
-		}
-		return &QdrantCondition{
-			Key: bb.Key,
-			Range: &QdrantRangeCondition{
-				Gte: &val,
-			},
-		}, nil
-
-	case LT:
-		val, err := toFloat64(bb.Value)
-		if err != nil {
-			return nil, err
-		}
-		return &QdrantCondition{
-			Key: bb.Key,
-			Range: &QdrantRangeCondition{
-				Lt: &val,
-			},
-		}, nil
-
-	case LTE:
-		val, err := toFloat64(bb.Value)
-		if err != nil {
-			return nil, err
-		}
-		return &QdrantCondition{
-			Key: bb.Key,
-			Range: &QdrantRangeCondition{
-				Lte: &val,
-			},
-		}, nil
-
-	case LIKE:
-		// Qdrant doesn't natively support LIKE, ignore
-		return nil, fmt.Errorf("LIKE not supported in Qdrant")
-
-	default:
-		// Unsupported operations, return nil (ignore)
-		return nil, nil
-	}
-}
-
 // toFloat64 helper function: converts to float64
 func toFloat64(v interface{}) (float64, error) {
 	switch val := v.(type) {
 	case int:
 		return float64(val), nil
+	case int8:
+		return float64(val), nil
+	case int16:
+		return float64(val), nil
 	case int32:
 		return float64(val), nil
 	case int64:
 		return float64(val), nil
+	case uint:
+		return float64(val), nil
+	case uint8:
+		return float64(val), nil
+	case uint16:
+		return float64(val), nil
+	case uint32:
+		return float64(val), nil
+	case uint64:
+		return float64(val), nil
 	case float32:
 		return float64(val), nil
 	case float64:
@@ -744,21 +941,52 @@ func applyQdrantParams(bbs []Bb, req QdrantRequest) {
 	ApplyCommonVectorParams(bbs, req)
 
 	// ⭐ Second layer: apply Qdrant-specific parameters
+	// QDRANT_SCORE_THRESHOLD and QDRANT_WITH_VECTOR are already handled in ApplyCommonVectorParams
+	applyQdrantWithPayload(bbs, req)
+	if req.GetParams() == nil || !hasQdrantSearchParams(bbs) {
+		return
+	}
+	ensureParams(req)
+	applyQdrantSearchParams(bbs, *req.GetParams())
+}
+
+// applyQdrantSearchParams applies QdrantX search parameters (hnsw_ef, exact, indexed_only, quantization)
+func applyQdrantSearchParams(bbs []Bb, params *QdrantSearchParams) {
 	for _, bb := range bbs {
 		switch bb.Op {
 		case QDRANT_HNSW_EF:
-			if req.GetParams() != nil {
-				ensureParams(req)
-				(*req.GetParams()).HnswEf = bb.Value.(int)
+			if ef, ok := bb.Value.(int); ok {
+				params.HnswEf = ef
 			}
-
 		case QDRANT_EXACT:
-			if req.GetParams() != nil {
-				ensureParams(req)
-				(*req.GetParams()).Exact = bb.Value.(bool)
+			if exact, ok := bb.Value.(bool); ok {
+				params.Exact = exact
+			}
+		case QDRANT_INDEXED_ONLY:
+			if indexedOnly, ok := bb.Value.(bool); ok {
+				params.IndexedOnly = indexedOnly
 			}
+		case QDRANT_QUANTIZATION:
+			if quantization, ok := bb.Value.(*QdrantQuantizationParams); ok {
+				params.Quantization = quantization
+			}
+		}
+	}
+}
 
-			// ⭐ QDRANT_SCORE_THRESHOLD and QDRANT_WITH_VECTOR are already handled in ApplyCommonVectorParams
+// hasQdrantSearchParams checks if any search parameter is set per query
+func hasQdrantSearchParams(bbs []Bb) bool {
+	return hasBbWithOp(bbs, QDRANT_HNSW_EF) ||
+		hasBbWithOp(bbs, QDRANT_EXACT) ||
+		hasBbWithOp(bbs, QDRANT_INDEXED_ONLY) ||
+		hasBbWithOp(bbs, QDRANT_QUANTIZATION)
+}
+
+// applyQdrantWithPayload applies QdrantX WithPayload()
+func applyQdrantWithPayload(bbs []Bb, req QdrantRequest) {
+	for _, bb := range bbs {
+		if bb.Op == QDRANT_WITH_PAYLOAD {
+			*req.GetWithPayload() = bb.Value
 		}
 	}
 }
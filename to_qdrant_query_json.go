@@ -0,0 +1,347 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import "fmt"
+
+// ============================================================================
+// Qdrant Universal Query API (/points/query)
+// ============================================================================
+
+// QdrantQueryRequest Qdrant universal query request structure (v1.10+)
+// Documentation: https://qdrant.tech/documentation/concepts/hybrid-queries/
+type QdrantQueryRequest struct {
+	Prefetch       []QdrantPrefetch    `json:"prefetch,omitempty"`
+	Query          interface{}         `json:"query,omitempty"` // []float32, QdrantFusionQuery, ...
+	Using          string              `json:"using,omitempty"` // Named vector
+	Filter         *QdrantFilter       `json:"filter,omitempty"`
+	Params         *QdrantSearchParams `json:"params,omitempty"`
+	ScoreThreshold *float32            `json:"score_threshold,omitempty"`
+	Limit          int                 `json:"limit"`
+	Offset         int                 `json:"offset,omitempty"`
+	WithPayload    interface{}         `json:"with_payload,omitempty"` // true, false, or []string
+	WithVector     bool                `json:"with_vector,omitempty"`
+}
+
+// Implements VectorDBRequest interface (common)
+func (r *QdrantQueryRequest) GetScoreThreshold() **float32 {
+	return &r.ScoreThreshold
+}
+
+func (r *QdrantQueryRequest) GetWithVector() *bool {
+	return &r.WithVector
+}
+
+func (r *QdrantQueryRequest) GetFilter() interface{} {
+	return &r.Filter
+}
+
+// Implements QdrantRequest interface (specific)
+func (r *QdrantQueryRequest) GetParams() **QdrantSearchParams {
+	return &r.Params
+}
+
+func (r *QdrantQueryRequest) GetQdrantFilter() **QdrantFilter {
+	return &r.Filter
+}
+
+func (r *QdrantQueryRequest) GetWithPayload() *interface{} {
+	return &r.WithPayload
+}
+
+// QdrantPrefetch one prefetch stage, its results are the candidates of the outer query
+type QdrantPrefetch struct {
+	Prefetch       []QdrantPrefetch    `json:"prefetch,omitempty"`
+	Query          interface{}         `json:"query,omitempty"`
+	Using          string              `json:"using,omitempty"`
+	Filter         *QdrantFilter       `json:"filter,omitempty"`
+	Params         *QdrantSearchParams `json:"params,omitempty"`
+	ScoreThreshold *float32            `json:"score_threshold,omitempty"`
+	Limit          int                 `json:"limit,omitempty"`
+}
+
+// QdrantFusionQuery {"fusion": "rrf"} or {"fusion": "dbsf"}
+type QdrantFusionQuery struct {
+	Fusion string `json:"fusion"`
+}
+
+// QdrantOrderByQuery {"order_by": {"key": "...", "direction": "desc"}}
+type QdrantOrderByQuery struct {
+	OrderBy QdrantOrderBy `json:"order_by"`
+}
+
+// QdrantOrderBy order by a payload field
+type QdrantOrderBy struct {
+	Key       string `json:"key"`
+	Direction string `json:"direction,omitempty"` // "asc" or "desc"
+}
+
+// QdrantFormulaQuery {"formula": {...}, "defaults": {...}}
+type QdrantFormulaQuery struct {
+	Formula  interface{}            `json:"formula"`
+	Defaults map[string]interface{} `json:"defaults,omitempty"`
+}
+
+// toQdrantQueryJSON generates /points/query JSON for QdrantBuilder.Query()
+func (built *Built) toQdrantQueryJSON() (string, error) {
+	built = ensureQdrantAdvanced(built)
+	var stage *QueryBuilder
+	for _, bb := range built.Conds {
+		if bb.Op == QDRANT_QUERY {
+			stage = bb.Value.(*QueryBuilder)
+			break
+		}
+	}
+	if stage == nil {
+		stage = multiVectorStage(built)
+	}
+	if stage == nil {
+		return "", fmt.Errorf("no query configuration found")
+	}
+
+	params := qdrantStageParams(built)
+
+	req := &QdrantQueryRequest{
+		Query:       stage.query,
+		Using:       stage.using,
+		Limit:       stage.limit,
+		WithPayload: true,
+	}
+	if req.Limit == 0 {
+		req.Limit = 10 // Qdrant default
+	}
+	if stage.queryKind == "Nearest()" {
+		req.Params = params
+	}
+	if stage.scoreThreshold != nil {
+		req.ScoreThreshold = stage.scoreThreshold
+	} else if qdrantCustom, ok := built.Custom.(*QdrantCustom); ok && qdrantCustom.DefaultScoreThreshold > 0 {
+		threshold := qdrantCustom.DefaultScoreThreshold
+		req.ScoreThreshold = &threshold
+	}
+	if qdrantCustom, ok := built.Custom.(*QdrantCustom); ok {
+		req.WithVector = qdrantCustom.DefaultWithVector
+	}
+	ApplyCommonVectorParams(built.Conds, req)
+	applyQdrantWithPayload(built.Conds, req)
+
+	// Top-level filter: main builder conditions + Filter()
+	filter, err := buildQdrantFilter(append(cloneBbs(built.Conds), stage.conds...))
+	if err != nil {
+		return "", err
+	}
+	req.Filter = nonEmptyQdrantFilter(filter)
+
+	for _, pb := range stage.prefetch {
+		prefetch, err := pb.toQdrantPrefetch(params)
+		if err != nil {
+			return "", err
+		}
+		req.Prefetch = append(req.Prefetch, prefetch)
+	}
+
+	if built.PageCondition != nil && built.PageCondition.Rows > 0 {
+		req.Limit = int(built.PageCondition.Rows)
+		if built.PageCondition.Page > 1 {
+			req.Offset = int((built.PageCondition.Page - 1) * built.PageCondition.Rows)
+		}
+	}
+
+	return mergeAndSerialize(req, built.Conds)
+}
+
+// multiVectorStage the query stage of MultiVectorSearch(): nearest by the multivector
+func multiVectorStage(built *Built) *QueryBuilder {
+	vectorBb := findVectorSearchBb(built.Conds)
+	if vectorBb == nil {
+		return nil
+	}
+	params := vectorBb.Value.(VectorSearchParams)
+	if params.QueryMultiVector == nil {
+		return nil
+	}
+	stage := &QueryBuilder{query: params.QueryMultiVector, queryKind: "Nearest()", limit: params.fetchK()}
+	if qdrantCustom, ok := built.Custom.(*QdrantCustom); ok && qdrantCustom.NamedVectors {
+		stage.using = vectorBb.Key
+	}
+	return stage
+}
+
+// toQdrantPrefetch converts a prefetch stage (recursively)
+func (qb *QueryBuilder) toQdrantPrefetch(params *QdrantSearchParams) (QdrantPrefetch, error) {
+	prefetch := QdrantPrefetch{
+		Query:          qb.query,
+		Using:          qb.using,
+		ScoreThreshold: qb.scoreThreshold,
+		Limit:          qb.limit,
+	}
+	if qb.queryKind == "Nearest()" {
+		prefetch.Params = params
+	}
+
+	filter, err := buildQdrantFilter(qb.conds)
+	if err != nil {
+		return prefetch, err
+	}
+	prefetch.Filter = nonEmptyQdrantFilter(filter)
+
+	for _, nested := range qb.prefetch {
+		p, err := nested.toQdrantPrefetch(params)
+		if err != nil {
+			return prefetch, err
+		}
+		prefetch.Prefetch = append(prefetch.Prefetch, p)
+	}
+	return prefetch, nil
+}
+
+// qdrantStageParams search parameters of vector stages: Custom defaults, then QdrantX
+func qdrantStageParams(built *Built) *QdrantSearchParams {
+	params := &QdrantSearchParams{HnswEf: 128}
+	if qdrantCustom, ok := built.Custom.(*QdrantCustom); ok {
+		params.HnswEf = qdrantCustom.DefaultHnswEf
+	}
+	applyQdrantSearchParams(built.Conds, params)
+	return params
+}
+
+// toQdrantHybridJSON generates /points/query JSON for Hybrid()
+//
+// Each vector leg becomes a prefetch stage; the keyword leg becomes a prefetch
+// searching with the first vector leg, restricted by match.text on the keyword field.
+// Legs are fused natively with rrf or dbsf.
+// Vector legs on different fields set "using" (named vectors), sparse legs always do.
+func (built *Built) toQdrantHybridJSON() (string, error) {
+	built = ensureQdrantAdvanced(built)
+	params := built.Hybrid()
+	if params == nil {
+		return "", fmt.Errorf("no hybrid search found")
+	}
+
+	var fusion string
+	switch params.Fusion {
+	case FusionRRF, "":
+		fusion = string(FusionRRF)
+	case FusionDBSF:
+		fusion = string(FusionDBSF)
+	default:
+		return "", fmt.Errorf("qdrant does not support %q fusion natively, use rrf/dbsf or fuse per-leg results with FuseHybrid()", params.Fusion)
+	}
+
+	var firstVector *HybridLeg
+	vectorFields := map[string]bool{}
+	keyword, sparse := false, false
+	for i := range params.Legs {
+		switch params.Legs[i].Kind {
+		case HybridVector:
+			if firstVector == nil {
+				firstVector = &params.Legs[i]
+			}
+			vectorFields[params.Legs[i].Field] = true
+		case HybridKeyword:
+			keyword = true
+		case HybridSparse:
+			sparse = true
+		}
+	}
+	if firstVector == nil && (keyword || !sparse) {
+		return "", fmt.Errorf("qdrant hybrid search needs at least one vector leg")
+	}
+	named := len(vectorFields) > 1
+	if qdrantCustom, ok := built.Custom.(*QdrantCustom); ok && qdrantCustom.NamedVectors {
+		named = true
+	}
+
+	filter, err := buildQdrantFilter(built.Conds)
+	if err != nil {
+		return "", err
+	}
+
+	// Search parameters apply to every prefetch (the fusion stage has no index lookup)
+	stageParams := qdrantStageParams(built)
+	req := &QdrantQueryRequest{
+		Query:       QdrantFusionQuery{Fusion: fusion},
+		Limit:       params.Limit,
+		WithPayload: true,
+	}
+	if qdrantCustom, ok := built.Custom.(*QdrantCustom); ok {
+		if qdrantCustom.DefaultScoreThreshold > 0 {
+			threshold := qdrantCustom.DefaultScoreThreshold
+			req.ScoreThreshold = &threshold
+		}
+		req.WithVector = qdrantCustom.DefaultWithVector
+	}
+	ApplyCommonVectorParams(built.Conds, req)
+	applyQdrantWithPayload(built.Conds, req)
+
+	for _, leg := range params.Legs {
+		prefetch := QdrantPrefetch{
+			Query:  leg.QueryVector,
+			Filter: nonEmptyQdrantFilter(filter),
+			Params: stageParams,
+			Limit:  leg.Limit,
+		}
+		field := leg.Field
+		if leg.Kind == HybridSparse {
+			prefetch.Query = *leg.QuerySparse
+			prefetch.Using = field // Sparse vectors are always named
+			req.Prefetch = append(req.Prefetch, prefetch)
+			continue
+		}
+		if leg.Kind == HybridKeyword {
+			prefetch.Query = firstVector.QueryVector
+			prefetch.Filter = withQdrantMust(filter, QdrantCondition{
+				Key:   leg.Field,
+				Match: &QdrantMatchCondition{Text: leg.Text},
+			})
+			field = firstVector.Field
+		}
+		if named {
+			prefetch.Using = field
+		}
+		req.Prefetch = append(req.Prefetch, prefetch)
+	}
+
+	if built.PageCondition != nil && built.PageCondition.Rows > 0 {
+		req.Limit = int(built.PageCondition.Rows)
+		if built.PageCondition.Page > 1 {
+			req.Offset = int((built.PageCondition.Page - 1) * built.PageCondition.Rows)
+		}
+	}
+
+	return mergeAndSerialize(req, built.Conds)
+}
+
+// nonEmptyQdrantFilter returns nil for a filter without conditions
+func nonEmptyQdrantFilter(filter *QdrantFilter) *QdrantFilter {
+	if filter == nil || (len(filter.Must) == 0 && len(filter.Should) == 0 && len(filter.MustNot) == 0) {
+		return nil
+	}
+	return filter
+}
+
+// withQdrantMust copies filter and appends conditions to Must
+func withQdrantMust(filter *QdrantFilter, conds ...QdrantCondition) *QdrantFilter {
+	out := &QdrantFilter{}
+	if filter != nil {
+		out.Must = append(out.Must, filter.Must...)
+		out.Should = append(out.Should, filter.Should...)
+		out.MustNot = append(out.MustNot, filter.MustNot...)
+	}
+	out.Must = append(out.Must, conds...)
+	return out
+}
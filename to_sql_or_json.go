@@ -261,6 +261,10 @@ func (built *Built) toBb(bb Bb, bp *strings.Builder, vs *[]interface{}) {
 			bp.WriteString(ss)
 		}
 	default:
+		if isQdrantPayloadCondOp(op) {
+			// Skipping a filter would widen the query
+			panic(fmt.Sprintf("Qdrant-only condition %s on %q is not supported in SQL, use a Qdrant Custom", op, bb.Key))
+		}
 		bp.WriteString(bb.Key)
 		bp.WriteString(SPACE)
 		bp.WriteString(bb.Op)
@@ -27,11 +27,18 @@ func (built *Built) toUpdateSql(bp *strings.Builder, vs *[]interface{}) {
 		return
 	}
 
+	updates := make([]Bb, 0, len(*built.Updates))
+	for _, u := range *built.Updates {
+		if !isQdrantUpdateOp(u.Op) {
+			updates = append(updates, u)
+		}
+	}
+
 	bp.WriteString(SET)
-	length := len(*built.Updates)
+	length := len(updates)
 
 	for i := 0; i < length; i++ {
-		u := (*built.Updates)[i]
+		u := updates[i]
 		bp.WriteString(u.Key)
 		if !strings.Contains(u.Key, EQ) {
 			bp.WriteString(SPACE)
@@ -50,14 +50,21 @@ func (built *Built) SqlOfVectorSearch() (string, []interface{}) {
 	vectorBb := findVectorSearchBb(built.Conds)
 	if vectorBb != nil {
 		params := vectorBb.Value.(VectorSearchParams)
+		if params.QueryMultiVector != nil {
+			panic(fmt.Sprintf("MultiVectorSearch(%q) is not supported in SQL, use a vector database Custom", vectorBb.Key))
+		}
+		if params.Encoding == Int8Encoding {
+			panic(fmt.Sprintf("VectorEncoding(Int8Encoding) on %q is not supported in SQL, pgvector has no int8 type", vectorBb.Key))
+		}
 
 		// Add distance field
 		sb.WriteString(fmt.Sprintf(
-			", %s %s ? AS distance",
+			", %s %s %s AS distance",
 			vectorBb.Key,
 			params.DistanceMetric,
+			params.queryPlaceholder(),
 		))
-		args = append(args, params.QueryVector)
+		args = append(args, params.queryArg())
 	}
 
 	// 2. FROM clause
@@ -95,7 +102,7 @@ func (built *Built) SqlOfVectorSearch() (string, []interface{}) {
 		params := vectorBb.Value.(VectorSearchParams)
 
 		// 5. LIMIT Top-K
-		sb.WriteString(fmt.Sprintf(" LIMIT %d", params.TopK))
+		sb.WriteString(fmt.Sprintf(" LIMIT %d", params.fetchK()))
 	}
 
 	return sb.String(), args
@@ -116,7 +123,7 @@ func filterScalarConds(bbs []Bb) []Bb {
 	result := []Bb{}
 	for _, bb := range bbs {
 		// Skip vector operators
-		if bb.Op == VECTOR_SEARCH || bb.Op == VECTOR_DISTANCE_FILTER {
+		if isVectorOp(bb.Op) {
 			continue
 		}
 		// ⭐ Skip Qdrant-specific operators (PostgreSQL doesn't support)
@@ -134,9 +141,26 @@ func isQdrantSpecificOp(op string) bool {
 		op == QDRANT_EXACT ||
 		op == QDRANT_SCORE_THRESHOLD ||
 		op == QDRANT_WITH_VECTOR ||
+		op == QDRANT_INDEXED_ONLY ||
+		op == QDRANT_QUANTIZATION ||
+		op == QDRANT_WITH_PAYLOAD ||
+		op == QDRANT_GROUP_SIZE ||
+		op == QDRANT_WITH_LOOKUP ||
+		op == QDRANT_FACET ||
 		op == QDRANT_XX
 }
 
+// isQdrantPayloadCondOp checks if operator is a Qdrant payload condition (no SQL equivalent)
+func isQdrantPayloadCondOp(op string) bool {
+	switch op {
+	case QDRANT_MATCH_EXCEPT, QDRANT_GEO_RADIUS, QDRANT_GEO_BOUNDING_BOX, QDRANT_GEO_POLYGON,
+		QDRANT_DATETIME_RANGE, QDRANT_VALUES_COUNT, QDRANT_IS_EMPTY, QDRANT_IS_NULL,
+		QDRANT_HAS_ID, QDRANT_NESTED:
+		return true
+	}
+	return false
+}
+
 // Helper function: filter vector distance conditions
 func filterVectorDistanceConds(bbs []Bb) []Bb {
 	result := []Bb{}
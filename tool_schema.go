@@ -0,0 +1,531 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Tool calling: a filterable model as an LLM tool (JSON Schema in, BuilderX out)
+// ============================================================================
+
+// Tool operators, declared per field with `xb:"filter=eq,in,range"`
+// range allows gt, gte, lt and lte
+const (
+	toolEq    = "eq"
+	toolNe    = "ne"
+	toolIn    = "in"
+	toolNin   = "nin"
+	toolGt    = "gt"
+	toolGte   = "gte"
+	toolLt    = "lt"
+	toolLte   = "lte"
+	toolLike  = "like"
+	toolRange = "range"
+)
+
+// toolOps the operators in the order FromToolCall applies them
+var toolOps = []string{toolEq, toolNe, toolIn, toolNin, toolGt, toolGte, toolLt, toolLte, toolLike}
+
+// ToolOptions describes the tool generated by ToolSchema
+type ToolOptions struct {
+	Name         string            // Defaults to "search_" + table name
+	Description  string            // What the tool returns, shown to the model
+	Descriptions map[string]string // Field descriptions, by column name
+	MaxLimit     int               // Upper bound of "limit", defaults to 100
+}
+
+// ToolDefinition a function-calling tool: name, description and the JSON Schema of its arguments
+type ToolDefinition struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Parameters  *JSONSchema `json:"parameters"`
+}
+
+// JSONSchema the subset of JSON Schema used by tool definitions
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MinProperties        *int                   `json:"minProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+}
+
+// ToolSchema reflects a Po into a tool definition
+// Only fields with an xb tag are exposed, named by their db tag (then json tag):
+//
+//	type Article struct {
+//	    Status    string    `db:"status" xb:"filter=eq,in;enum=draft|published"`
+//	    Views     int64     `db:"views" xb:"filter=range;sort"`
+//	    CreatedAt time.Time `db:"created_at" xb:"filter=range;sort"`
+//	}
+//
+// The arguments look like:
+//
+//	{"filter": {"status": {"in": ["published"]}, "views": {"gte": 100}},
+//	 "sort": [{"field": "created_at", "direction": "desc"}], "limit": 10, "offset": 0}
+//
+// Panics on invalid tags, like the builders
+func ToolSchema[T any](opts ToolOptions) *ToolDefinition {
+	model := toolModelOf[T]()
+	opts = opts.withDefaults(model.table)
+
+	filters := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}, AdditionalProperties: toolFalse()}
+	var sortable []interface{}
+	for _, f := range model.fields {
+		if f.sort {
+			sortable = append(sortable, f.name)
+		}
+		if len(f.ops) == 0 {
+			continue
+		}
+		one := 1
+		ops := &JSONSchema{
+			Type:                 "object",
+			Description:          opts.Descriptions[f.name],
+			Properties:           map[string]*JSONSchema{},
+			MinProperties:        &one,
+			AdditionalProperties: toolFalse(),
+		}
+		for _, op := range toolOps {
+			if !f.ops[op] {
+				continue
+			}
+			if op == toolIn || op == toolNin {
+				ops.Properties[op] = &JSONSchema{Type: "array", Items: f.schema(), MinItems: &one}
+			} else {
+				ops.Properties[op] = f.schema()
+			}
+		}
+		filters.Properties[f.name] = ops
+	}
+
+	one, maxLimit := float64(1), float64(opts.MaxLimit)
+	zero := float64(0)
+	params := &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"filter": filters,
+			"limit":  {Type: "integer", Minimum: &one, Maximum: &maxLimit},
+			"offset": {Type: "integer", Minimum: &zero},
+		},
+		AdditionalProperties: toolFalse(),
+	}
+	if len(sortable) > 0 {
+		params.Properties["sort"] = &JSONSchema{
+			Type: "array",
+			Items: &JSONSchema{
+				Type: "object",
+				Properties: map[string]*JSONSchema{
+					"field":     {Type: "string", Enum: sortable},
+					"direction": {Type: "string", Enum: []interface{}{"asc", "desc"}},
+				},
+				Required:             []string{"field"},
+				AdditionalProperties: toolFalse(),
+			},
+		}
+	}
+
+	return &ToolDefinition{Name: opts.Name, Description: opts.Description, Parameters: params}
+}
+
+// FromToolCall turns the arguments of a ToolSchema tool call into a builder
+// Only the declared fields, operators, enum values and sort fields are accepted,
+// limit must be in [1, MaxLimit]. Add tenant guards, VectorSearch, ... to the returned builder
+//
+// Example:
+//
+//	x, err := xb.FromToolCall[Article](call.Arguments)
+//	if err != nil {
+//	    return toolError(err) // let the model retry
+//	}
+//	sql, args, _ := x.Eq("tenant_id", tenantID).Build().SqlOfSelect()
+func FromToolCall[T any](args json.RawMessage, opts ...ToolOptions) (x *BuilderX, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			x, err = nil, fmt.Errorf("tool call: %v", r)
+		}
+	}()
+
+	model := toolModelOf[T]()
+	var o ToolOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	o = o.withDefaults(model.table)
+
+	var call struct {
+		Filter map[string]map[string]json.RawMessage `json:"filter"`
+		Sort   []struct {
+			Field     string `json:"field"`
+			Direction string `json:"direction"`
+		} `json:"sort"`
+		Limit  *int `json:"limit"`
+		Offset int  `json:"offset"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&call); err != nil {
+		return nil, fmt.Errorf("tool call: invalid arguments: %w", err)
+	}
+
+	for name := range call.Filter {
+		if f := model.field(name); f == nil || len(f.ops) == 0 {
+			return nil, fmt.Errorf("tool call: field %q is not filterable", name)
+		}
+	}
+
+	x = Of(model.table)
+	for _, f := range model.fields {
+		ops, ok := call.Filter[f.name]
+		if !ok {
+			continue
+		}
+		// Like an empty string, a condition without operand would widen the query
+		if len(ops) == 0 {
+			return nil, fmt.Errorf("tool call: %s requires at least one operator", f.name)
+		}
+		for op, raw := range ops {
+			if !f.ops[op] {
+				return nil, fmt.Errorf("tool call: operator %q is not allowed on %q", op, f.name)
+			}
+			if string(bytes.TrimSpace(raw)) == "null" {
+				return nil, fmt.Errorf("tool call: %s %s requires a value, got null", f.name, op)
+			}
+		}
+		for _, op := range toolOps {
+			raw, ok := ops[op]
+			if !ok {
+				continue
+			}
+			if err := f.apply(x, op, raw); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, s := range call.Sort {
+		if f := model.field(s.Field); f == nil || !f.sort {
+			return nil, fmt.Errorf("tool call: field %q is not sortable", s.Field)
+		}
+		switch s.Direction {
+		case "", "asc":
+			x.Sort(s.Field, ASC)
+		case "desc":
+			x.Sort(s.Field, DESC)
+		default:
+			return nil, fmt.Errorf("tool call: sort direction must be asc or desc, got %q", s.Direction)
+		}
+	}
+
+	if call.Limit != nil && (*call.Limit < 1 || *call.Limit > o.MaxLimit) {
+		return nil, fmt.Errorf("tool call: limit must be in [1, %d], got %d", o.MaxLimit, *call.Limit)
+	}
+	if call.Offset < 0 {
+		return nil, fmt.Errorf("tool call: offset must be >= 0, got %d", call.Offset)
+	}
+	limit := o.MaxLimit
+	if call.Limit != nil {
+		limit = *call.Limit
+	}
+	return x.Limit(limit).Offset(call.Offset), nil
+}
+
+func (o ToolOptions) withDefaults(table string) ToolOptions {
+	if o.Name == "" {
+		o.Name = "search_" + table
+	}
+	if o.MaxLimit <= 0 {
+		o.MaxLimit = 100
+	}
+	return o
+}
+
+func toolFalse() *bool {
+	f := false
+	return &f
+}
+
+// toolKind the JSON type of a field and how its values reach the builder
+type toolKind int
+
+const (
+	toolString toolKind = iota
+	toolInt
+	toolUint
+	toolFloat
+	toolBool
+	toolTime
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// toolModel the exposed fields of a Po
+type toolModel struct {
+	table  string
+	fields []*toolField
+}
+
+// toolField one exposed field
+type toolField struct {
+	name string
+	typ  reflect.Type
+	kind toolKind
+	ops  map[string]bool
+	sort bool
+	enum []string
+}
+
+func (m *toolModel) field(name string) *toolField {
+	for _, f := range m.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// toolModelOf parses the tags of T, panics on invalid tags
+func toolModelOf[T any]() *toolModel {
+	var t T
+	po, ok := interface{}(&t).(Po)
+	if !ok {
+		panic(fmt.Sprintf("ToolSchema: %T must implement Po (TableName())", t))
+	}
+	rt := reflect.TypeOf(t)
+	if rt.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("ToolSchema: %T is not a struct", t))
+	}
+
+	model := &toolModel{table: po.TableName()}
+	for _, sf := range reflect.VisibleFields(rt) {
+		tag, ok := sf.Tag.Lookup("xb")
+		if !ok || tag == "-" || sf.Anonymous || !sf.IsExported() {
+			continue
+		}
+		f := parseToolField(sf, tag)
+		if model.field(f.name) != nil {
+			panic(fmt.Sprintf("ToolSchema: duplicate field %q", f.name))
+		}
+		model.fields = append(model.fields, f)
+	}
+	return model
+}
+
+// parseToolField parses `xb:"filter=eq,in,range;sort;enum=a|b"`
+func parseToolField(sf reflect.StructField, tag string) *toolField {
+	name := tagName(sf.Tag.Get("db"))
+	if name == "" || name == "-" {
+		name = tagName(sf.Tag.Get("json"))
+	}
+	if name == "" || name == "-" {
+		panic(fmt.Sprintf("ToolSchema: field %s needs a db or json tag", sf.Name))
+	}
+
+	typ := sf.Type
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	f := &toolField{name: name, typ: typ, ops: map[string]bool{}}
+	switch {
+	case typ == timeType:
+		f.kind = toolTime
+	case typ.Kind() == reflect.String:
+		f.kind = toolString
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Int64:
+		f.kind = toolInt
+	case typ.Kind() >= reflect.Uint && typ.Kind() <= reflect.Uint64:
+		f.kind = toolUint
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		f.kind = toolFloat
+	case typ.Kind() == reflect.Bool:
+		f.kind = toolBool
+	default:
+		panic(fmt.Sprintf("ToolSchema: field %q has unsupported type %s", name, sf.Type))
+	}
+
+	for _, part := range strings.Split(tag, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "filter":
+			for _, op := range strings.Split(value, ",") {
+				f.addOp(strings.TrimSpace(op))
+			}
+		case "sort":
+			if f.kind == toolBool {
+				panic(fmt.Sprintf("ToolSchema: bool field %q is not sortable", name))
+			}
+			f.sort = true
+		case "enum":
+			if f.kind != toolString || value == "" {
+				panic(fmt.Sprintf("ToolSchema: enum requires a string field and values, field %q", name))
+			}
+			f.enum = strings.Split(value, "|")
+		case "":
+		default:
+			panic(fmt.Sprintf("ToolSchema: unknown xb tag option %q on field %q", key, name))
+		}
+	}
+	return f
+}
+
+func (f *toolField) addOp(op string) {
+	allowed := true
+	switch op {
+	case toolEq, toolNe:
+	case toolIn, toolNin:
+		allowed = f.kind != toolBool && f.kind != toolTime
+	case toolRange:
+		allowed = f.kind != toolBool
+	case toolLike:
+		allowed = f.kind == toolString
+	default:
+		panic(fmt.Sprintf("ToolSchema: unknown filter operator %q on field %q", op, f.name))
+	}
+	if !allowed {
+		panic(fmt.Sprintf("ToolSchema: operator %q is not supported on field %q (%s)", op, f.name, f.typ))
+	}
+	if op == toolRange {
+		f.ops[toolGt], f.ops[toolGte], f.ops[toolLt], f.ops[toolLte] = true, true, true, true
+		return
+	}
+	f.ops[op] = true
+}
+
+// schema the JSON Schema of one value of the field
+func (f *toolField) schema() *JSONSchema {
+	switch f.kind {
+	case toolTime:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case toolInt:
+		return &JSONSchema{Type: "integer"}
+	case toolUint:
+		zero := float64(0)
+		return &JSONSchema{Type: "integer", Minimum: &zero}
+	case toolFloat:
+		return &JSONSchema{Type: "number"}
+	case toolBool:
+		return &JSONSchema{Type: "boolean"}
+	}
+	s := &JSONSchema{Type: "string"}
+	for _, e := range f.enum {
+		s.Enum = append(s.Enum, e)
+	}
+	if len(s.Enum) == 0 {
+		one := 1
+		s.MinLength = &one
+	}
+	return s
+}
+
+// apply decodes the operand of op and adds the condition
+func (f *toolField) apply(x *BuilderX, op string, raw json.RawMessage) error {
+	if op == toolIn || op == toolNin {
+		rs := reflect.New(reflect.SliceOf(f.typ))
+		if err := json.Unmarshal(raw, rs.Interface()); err != nil {
+			return fmt.Errorf("tool call: %s.%s: %w", f.name, op, err)
+		}
+		if rs.Elem().Len() == 0 {
+			return fmt.Errorf("tool call: %s.%s requires at least one value", f.name, op)
+		}
+		vs := make([]interface{}, rs.Elem().Len())
+		for i := range vs {
+			v, err := f.value(rs.Elem().Index(i))
+			if err != nil {
+				return err
+			}
+			vs[i] = v
+		}
+		if op == toolIn {
+			x.In(f.name, vs...)
+		} else {
+			x.Nin(f.name, vs...)
+		}
+		return nil
+	}
+
+	rv := reflect.New(f.typ)
+	if err := json.Unmarshal(raw, rv.Interface()); err != nil {
+		return fmt.Errorf("tool call: %s.%s: %w", f.name, op, err)
+	}
+	v, err := f.value(rv.Elem())
+	if err != nil {
+		return err
+	}
+	switch op {
+	case toolEq:
+		x.Eq(f.name, v)
+	case toolNe:
+		x.Ne(f.name, v)
+	case toolGt:
+		x.Gt(f.name, v)
+	case toolGte:
+		x.Gte(f.name, v)
+	case toolLt:
+		x.Lt(f.name, v)
+	case toolLte:
+		x.Lte(f.name, v)
+	case toolLike:
+		x.Like(f.name, v.(string))
+	}
+	return nil
+}
+
+// value converts a decoded operand to a type the builder accepts
+// Numbers and bools become pointers, so an explicit 0 or false is kept
+func (f *toolField) value(rv reflect.Value) (interface{}, error) {
+	switch f.kind {
+	case toolString:
+		s := rv.String()
+		if s == "" {
+			// The builder skips empty strings, the condition would widen the query
+			return nil, fmt.Errorf("tool call: %s requires a non-empty string", f.name)
+		}
+		if len(f.enum) > 0 && !slices.Contains(f.enum, s) {
+			return nil, fmt.Errorf("tool call: %q is not a valid %s, expected one of %v", s, f.name, f.enum)
+		}
+		return s, nil
+	case toolInt:
+		n := rv.Int()
+		return &n, nil
+	case toolUint:
+		n := rv.Uint()
+		return &n, nil
+	case toolFloat:
+		n := rv.Float()
+		return &n, nil
+	case toolBool:
+		b := rv.Bool()
+		return &b, nil
+	}
+	return rv.Interface(), nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type toolArticle struct {
+	Id        int64     `db:"id"`
+	Title     string    `db:"title" xb:"filter=eq,like"`
+	Status    string    `db:"status" xb:"filter=eq,in;enum=draft|published"`
+	Views     int64     `db:"views" xb:"filter=range;sort"`
+	Featured  *bool     `json:"featured" xb:"filter=eq"`
+	CreatedAt time.Time `db:"created_at" xb:"filter=range;sort"`
+	Secret    string    `db:"secret"`
+}
+
+func (toolArticle) TableName() string {
+	return "articles"
+}
+
+func TestToolSchema(t *testing.T) {
+	def := ToolSchema[toolArticle](ToolOptions{
+		Description:  "Search articles",
+		Descriptions: map[string]string{"views": "page views"},
+		MaxLimit:     50,
+	})
+	got, err := json.Marshal(def)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	assertJSONEqual(t, string(got), `{
+		"name": "search_articles",
+		"description": "Search articles",
+		"parameters": {
+			"type": "object",
+			"properties": {
+				"filter": {
+					"type": "object",
+					"properties": {
+						"title": {"type": "object", "properties": {
+							"eq": {"type": "string", "minLength": 1}, "like": {"type": "string", "minLength": 1}
+						}, "minProperties": 1, "additionalProperties": false},
+						"status": {"type": "object", "properties": {
+							"eq": {"type": "string", "enum": ["draft", "published"]},
+							"in": {"type": "array", "minItems": 1, "items": {"type": "string", "enum": ["draft", "published"]}}
+						}, "minProperties": 1, "additionalProperties": false},
+						"views": {"type": "object", "description": "page views", "properties": {
+							"gt": {"type": "integer"}, "gte": {"type": "integer"},
+							"lt": {"type": "integer"}, "lte": {"type": "integer"}
+						}, "minProperties": 1, "additionalProperties": false},
+						"featured": {"type": "object", "properties": {
+							"eq": {"type": "boolean"}
+						}, "minProperties": 1, "additionalProperties": false},
+						"created_at": {"type": "object", "properties": {
+							"gt": {"type": "string", "format": "date-time"}, "gte": {"type": "string", "format": "date-time"},
+							"lt": {"type": "string", "format": "date-time"}, "lte": {"type": "string", "format": "date-time"}
+						}, "minProperties": 1, "additionalProperties": false}
+					},
+					"additionalProperties": false
+				},
+				"sort": {"type": "array", "items": {
+					"type": "object",
+					"properties": {
+						"field": {"type": "string", "enum": ["views", "created_at"]},
+						"direction": {"type": "string", "enum": ["asc", "desc"]}
+					},
+					"required": ["field"],
+					"additionalProperties": false
+				}},
+				"limit": {"type": "integer", "minimum": 1, "maximum": 50},
+				"offset": {"type": "integer", "minimum": 0}
+			},
+			"additionalProperties": false
+		}
+	}`)
+}
+
+func TestFromToolCall(t *testing.T) {
+	x, err := FromToolCall[toolArticle](json.RawMessage(`{
+		"filter": {
+			"status": {"in": ["published", "draft"]},
+			"views": {"gte": 0, "lt": 1000},
+			"featured": {"eq": false},
+			"title": {"like": "go"},
+			"created_at": {"gte": "2025-01-02T03:04:05Z"}
+		},
+		"sort": [{"field": "views", "direction": "desc"}, {"field": "created_at"}],
+		"limit": 10,
+		"offset": 20
+	}`))
+	if err != nil {
+		t.Fatalf("FromToolCall failed: %v", err)
+	}
+	sql, args, _ := x.Build().SqlOfSelect()
+	want := "SELECT * FROM articles WHERE title LIKE ? AND status IN ('published', 'draft') AND views >= ? AND views < ? AND featured = ? AND created_at >= ? ORDER BY views DESC, created_at ASC LIMIT 10 OFFSET 20"
+	if sql != want {
+		t.Errorf("sql:\n got %s\nwant %s", sql, want)
+	}
+	if len(args) != 5 || args[0] != "%go%" || args[1] != int64(0) || args[3] != false || args[4] != "2025-01-02 03:04:05" {
+		t.Errorf("args = %v", args)
+	}
+
+	// No limit: MaxLimit
+	x, _ = FromToolCall[toolArticle](json.RawMessage(`{}`), ToolOptions{MaxLimit: 25})
+	if sql, _, _ := x.Build().SqlOfSelect(); !strings.HasSuffix(sql, "LIMIT 25") {
+		t.Errorf("sql = %s", sql)
+	}
+}
+
+func TestFromToolCall_Rejects(t *testing.T) {
+	cases := map[string]string{
+		"undeclared field":  `{"filter": {"secret": {"eq": "x"}}}`,
+		"unknown field":     `{"filter": {"nope": {"eq": "x"}}}`,
+		"operator":          `{"filter": {"status": {"like": "pub"}}}`,
+		"enum":              `{"filter": {"status": {"eq": "deleted"}}}`,
+		"enum in":           `{"filter": {"status": {"in": ["draft", "deleted"]}}}`,
+		"empty in":          `{"filter": {"status": {"in": []}}}`,
+		"empty eq":          `{"filter": {"title": {"eq": ""}}}`,
+		"empty like":        `{"filter": {"title": {"like": ""}}}`,
+		"empty enum":        `{"filter": {"status": {"eq": ""}}}`,
+		"empty in value":    `{"filter": {"status": {"in": [""]}}}`,
+		"null eq":           `{"filter": {"status": {"eq": null}}}`,
+		"null gte":          `{"filter": {"views": {"gte": null}}}`,
+		"no operator":       `{"filter": {"status": {}}}`,
+		"null operators":    `{"filter": {"status": null}}`,
+		"type":              `{"filter": {"views": {"gte": "many"}}}`,
+		"sort field":        `{"sort": [{"field": "title"}]}`,
+		"sort direction":    `{"sort": [{"field": "views", "direction": "up"}]}`,
+		"limit":             `{"limit": 101}`,
+		"zero limit":        `{"limit": 0}`,
+		"offset":            `{"offset": -1}`,
+		"unknown top level": `{"where": "1=1"}`,
+		"not json":          `filter`,
+	}
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			if x, err := FromToolCall[toolArticle](json.RawMessage(args)); err == nil {
+				sql, _, _ := x.Build().SqlOfSelect()
+				t.Errorf("expected error, got %s", sql)
+			}
+		})
+	}
+}
+
+type toolNoTable struct {
+	Name string `db:"name" xb:"filter=eq"`
+}
+
+type toolBadOp struct {
+	Active bool `db:"active" xb:"filter=range"`
+}
+
+func (toolBadOp) TableName() string { return "t" }
+
+type toolBadTag struct {
+	Name string `db:"name" xb:"filter=eq;index"`
+}
+
+func (toolBadTag) TableName() string { return "t" }
+
+type toolNoName struct {
+	Name string `xb:"filter=eq"`
+}
+
+func (toolNoName) TableName() string { return "t" }
+
+type toolBadType struct {
+	Tags []string `db:"tags" xb:"filter=in"`
+}
+
+func (toolBadType) TableName() string { return "t" }
+
+func TestToolSchema_Validation(t *testing.T) {
+	cases := map[string]func(){
+		"not a Po":       func() { ToolSchema[toolNoTable](ToolOptions{}) },
+		"range on bool":  func() { ToolSchema[toolBadOp](ToolOptions{}) },
+		"unknown option": func() { ToolSchema[toolBadTag](ToolOptions{}) },
+		"no column name": func() { ToolSchema[toolNoName](ToolOptions{}) },
+		"slice field":    func() { ToolSchema[toolBadType](ToolOptions{}) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+
+	if _, err := FromToolCall[toolBadOp](json.RawMessage(`{}`)); err == nil {
+		t.Error("FromToolCall should report invalid tags as an error")
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================================
+// Batch distance kernels
+// ============================================================================
+//
+// Vector.Distance() compares one pair and recomputes both norms for cosine.
+// Re-ranking and diversity compare one query against many candidates, or every
+// pair of a result set; these kernels:
+//   - compute the query norm once (DistancesTo) or every norm once (NormalizedMatrix)
+//   - write into a caller-owned out slice, no allocation per call
+//   - unroll by 4 with independent accumulators over bounds-check-free 4-element
+//     windows, the shape the Go compiler turns into straight-line pipelined code
+//
+// Results match Vector.Distance() up to float32 rounding.
+
+// dotKernel sum(a[i] * b[i]), len(b) >= len(a)
+func dotKernel(a, b []float32) float32 {
+	b = b[:len(a)]
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		x := a[i : i+4 : i+4]
+		y := b[i : i+4 : i+4]
+		s0 += x[0] * y[0]
+		s1 += x[1] * y[1]
+		s2 += x[2] * y[2]
+		s3 += x[3] * y[3]
+	}
+	for ; i < len(a); i++ {
+		s0 += a[i] * b[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+// dotNormKernel dot(a, b) and sum(b[i]²) in one pass
+func dotNormKernel(a, b []float32) (dot, normB float32) {
+	b = b[:len(a)]
+	var d0, d1, d2, d3, n0, n1, n2, n3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		x := a[i : i+4 : i+4]
+		y := b[i : i+4 : i+4]
+		d0 += x[0] * y[0]
+		d1 += x[1] * y[1]
+		d2 += x[2] * y[2]
+		d3 += x[3] * y[3]
+		n0 += y[0] * y[0]
+		n1 += y[1] * y[1]
+		n2 += y[2] * y[2]
+		n3 += y[3] * y[3]
+	}
+	for ; i < len(a); i++ {
+		d0 += a[i] * b[i]
+		n0 += b[i] * b[i]
+	}
+	return (d0 + d1) + (d2 + d3), (n0 + n1) + (n2 + n3)
+}
+
+// l2SquaredKernel sum((a[i] - b[i])²)
+func l2SquaredKernel(a, b []float32) float32 {
+	b = b[:len(a)]
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		x := a[i : i+4 : i+4]
+		y := b[i : i+4 : i+4]
+		d0 := x[0] - y[0]
+		d1 := x[1] - y[1]
+		d2 := x[2] - y[2]
+		d3 := x[3] - y[3]
+		s0 += d0 * d0
+		s1 += d1 * d1
+		s2 += d2 * d2
+		s3 += d3 * d3
+	}
+	for ; i < len(a); i++ {
+		d := a[i] - b[i]
+		s0 += d * d
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+func sqrt32(f float32) float32 {
+	return float32(math.Sqrt(float64(f)))
+}
+
+// outSlice returns out[:n], allocating only if out is too small
+func outSlice[E any](out []E, n int) []E {
+	if cap(out) < n {
+		return make([]E, n)
+	}
+	return out[:n]
+}
+
+// DistancesTo distances from query to every candidate, same values as Vector.Distance()
+// out is reused when its capacity is at least len(candidates), the result is out[:len(candidates)]
+// Panics if a candidate has a different dimension
+//
+// Example:
+//
+//	buf := make([]float32, 0, len(candidates))
+//	dists := xb.DistancesTo(query, candidates, xb.CosineDistance, buf)
+//	best := xb.TopK(dists, 10, nil)
+func DistancesTo(query Vector, candidates []Vector, metric VectorDistance, out []float32) []float32 {
+	out = outSlice(out, len(candidates))
+	var queryNorm float32
+	if metric != L2Distance && metric != InnerProduct && metric != HammingDistance {
+		queryNorm = sqrt32(dotKernel(query, query))
+	}
+	for i, c := range candidates {
+		if len(c) != len(query) {
+			panic(fmt.Sprintf("candidate %d has dimension %d, query has %d", i, len(c), len(query)))
+		}
+		switch metric {
+		case L2Distance:
+			out[i] = sqrt32(l2SquaredKernel(query, c))
+		case InnerProduct:
+			out[i] = -dotKernel(query, c)
+		case HammingDistance:
+			out[i] = signHamming(query, c)
+		default:
+			dot, normSq := dotNormKernel(query, c)
+			if queryNorm == 0 || normSq == 0 {
+				out[i] = 1.0 // Completely dissimilar, as cosineDistance
+				continue
+			}
+			out[i] = 1 - dot/(queryNorm*sqrt32(normSq))
+		}
+	}
+	return out
+}
+
+// NormalizedMatrix vectors stored as contiguous unit rows plus their original norms
+// Every norm is computed once, so cosine is one dot product per pair;
+// inner product and L2 are recovered from the norms.
+// Use it for repeated searches over the same candidates and O(n²) pair comparisons (MMR).
+type NormalizedMatrix struct {
+	dim   int
+	data  []float32 // len = rows * dim
+	norms []float32
+}
+
+// NewNormalizedMatrix copies the vectors into a matrix, all must have the same dimension
+// A zero vector keeps a zero row and norm 0 (cosine distance 1 to everything)
+func NewNormalizedMatrix(vectors []Vector) *NormalizedMatrix {
+	m := &NormalizedMatrix{norms: make([]float32, len(vectors))}
+	if len(vectors) == 0 {
+		return m
+	}
+	m.dim = len(vectors[0])
+	m.data = make([]float32, len(vectors)*m.dim)
+	for i, v := range vectors {
+		if len(v) != m.dim {
+			panic(fmt.Sprintf("NewNormalizedMatrix: vector %d has dimension %d, want %d", i, len(v), m.dim))
+		}
+		row := m.data[i*m.dim : (i+1)*m.dim]
+		norm := sqrt32(dotKernel(v, v))
+		m.norms[i] = norm
+		if norm == 0 {
+			continue
+		}
+		inv := 1 / norm
+		for j, f := range v {
+			row[j] = f * inv
+		}
+	}
+	return m
+}
+
+// Len number of rows
+func (m *NormalizedMatrix) Len() int {
+	return len(m.norms)
+}
+
+// Dim dimension of the rows
+func (m *NormalizedMatrix) Dim() int {
+	return m.dim
+}
+
+// Row unit row i, a view into the matrix (do not modify)
+func (m *NormalizedMatrix) Row(i int) Vector {
+	return m.data[i*m.dim : (i+1)*m.dim : (i+1)*m.dim]
+}
+
+// Norm original L2 norm of row i
+func (m *NormalizedMatrix) Norm(i int) float32 {
+	return m.norms[i]
+}
+
+// Distances distances from query to every row, same values as Vector.Distance()
+// out is reused when its capacity is at least Len()
+func (m *NormalizedMatrix) Distances(query Vector, metric VectorDistance, out []float32) []float32 {
+	if len(query) != m.dim && m.Len() > 0 {
+		panic(fmt.Sprintf("query has dimension %d, matrix has %d", len(query), m.dim))
+	}
+	out = outSlice(out, m.Len())
+	if metric == HammingDistance {
+		// Unit rows keep the signs of the original rows
+		for i := range out {
+			out[i] = signHamming(m.Row(i), query)
+		}
+		return out
+	}
+	queryNorm := sqrt32(dotKernel(query, query))
+	for i := range out {
+		// dot(unit row, query) = dot(row, query) / |row|
+		d := dotKernel(m.Row(i), query)
+		out[i] = m.combine(i, d, queryNorm, metric)
+	}
+	return out
+}
+
+// PairDistance distance between rows i and j
+func (m *NormalizedMatrix) PairDistance(i, j int, metric VectorDistance) float32 {
+	if metric == HammingDistance {
+		return signHamming(m.Row(i), m.Row(j))
+	}
+	unitDot := dotKernel(m.Row(i), m.Row(j))
+	switch metric {
+	case InnerProduct:
+		return -unitDot * m.norms[i] * m.norms[j]
+	case L2Distance:
+		ni, nj := m.norms[i], m.norms[j]
+		sq := ni*ni + nj*nj - 2*unitDot*ni*nj
+		return sqrt32(max(sq, 0))
+	default:
+		if m.norms[i] == 0 || m.norms[j] == 0 {
+			return 1.0
+		}
+		return 1 - unitDot
+	}
+}
+
+// combine turns dot(unit row i, query) into the metric
+func (m *NormalizedMatrix) combine(i int, d, queryNorm float32, metric VectorDistance) float32 {
+	norm := m.norms[i]
+	switch metric {
+	case InnerProduct:
+		return -d * norm
+	case L2Distance:
+		// |q - r|² = |q|² + |r|² - 2·dot(q, r), clamped against rounding
+		sq := queryNorm*queryNorm + norm*norm - 2*d*norm
+		return sqrt32(max(sq, 0))
+	default:
+		if norm == 0 || queryNorm == 0 {
+			return 1.0
+		}
+		return 1 - d/queryNorm
+	}
+}
+
+// TopK indexes of the k smallest distances, ordered by distance (ties by index)
+// Selection uses a bounded max-heap: O(n log k), no allocation when cap(out) >= k
+//
+// Example:
+//
+//	dists := matrix.Distances(query, xb.CosineDistance, buf)
+//	for _, i := range xb.TopK(dists, 10, idx) {
+//	    fmt.Println(ids[i], dists[i])
+//	}
+func TopK(distances []float32, k int, out []int) []int {
+	if k > len(distances) {
+		k = len(distances)
+	}
+	if k <= 0 {
+		return out[:0]
+	}
+	heap := outSlice(out, k)[:0]
+
+	// worse: a ranks after b
+	worse := func(a, b int) bool {
+		if distances[a] != distances[b] {
+			return distances[a] > distances[b]
+		}
+		return a > b
+	}
+	down := func(h []int, i int) {
+		for {
+			l := 2*i + 1
+			if l >= len(h) {
+				return
+			}
+			c := l
+			if r := l + 1; r < len(h) && worse(h[r], h[l]) {
+				c = r
+			}
+			if !worse(h[c], h[i]) {
+				return
+			}
+			h[i], h[c] = h[c], h[i]
+			i = c
+		}
+	}
+
+	for i := range distances {
+		if len(heap) < k {
+			heap = append(heap, i)
+			// sift up
+			for j := len(heap) - 1; j > 0; {
+				p := (j - 1) / 2
+				if !worse(heap[j], heap[p]) {
+					break
+				}
+				heap[j], heap[p] = heap[p], heap[j]
+				j = p
+			}
+			continue
+		}
+		if worse(heap[0], i) {
+			heap[0] = i
+			down(heap, 0)
+		}
+	}
+
+	// Heap sort: pop the worst to the end
+	for n := len(heap) - 1; n > 0; n-- {
+		heap[0], heap[n] = heap[n], heap[0]
+		down(heap[:n], 0)
+	}
+	return heap
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomVectors(r *rand.Rand, n, dim int) []Vector {
+	vectors := make([]Vector, n)
+	for i := range vectors {
+		vectors[i] = make(Vector, dim)
+		for j := range vectors[i] {
+			vectors[i][j] = r.Float32()*2 - 1
+		}
+	}
+	return vectors
+}
+
+var batchMetrics = []VectorDistance{CosineDistance, L2Distance, InnerProduct, HammingDistance}
+
+func TestDistancesTo_MatchesDistance(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	// 13: a remainder after the unrolled loop
+	candidates := randomVectors(r, 50, 13)
+	candidates = append(candidates, make(Vector, 13)) // zero vector
+	query := randomVectors(r, 1, 13)[0]
+	matrix := NewNormalizedMatrix(candidates)
+
+	for _, metric := range batchMetrics {
+		got := DistancesTo(query, candidates, metric, nil)
+		fromMatrix := matrix.Distances(query, metric, nil)
+		for i, c := range candidates {
+			want := query.Distance(c, metric)
+			if math.Abs(float64(got[i]-want)) > 1e-5 {
+				t.Fatalf("%s DistancesTo[%d] = %v, want %v", metric, i, got[i], want)
+			}
+			if math.Abs(float64(fromMatrix[i]-want)) > 1e-4 {
+				t.Fatalf("%s matrix.Distances[%d] = %v, want %v", metric, i, fromMatrix[i], want)
+			}
+		}
+		for i := 0; i < 5; i++ {
+			want := candidates[i].Distance(candidates[i+1], metric)
+			if got := matrix.PairDistance(i, i+1, metric); math.Abs(float64(got-want)) > 1e-4 {
+				t.Fatalf("%s PairDistance = %v, want %v", metric, got, want)
+			}
+		}
+	}
+
+	if matrix.Len() != 51 || matrix.Dim() != 13 || matrix.Norm(50) != 0 {
+		t.Errorf("Len/Dim/Norm = %d %d %v", matrix.Len(), matrix.Dim(), matrix.Norm(50))
+	}
+	if row := matrix.Row(0); math.Abs(float64(row.Distance(candidates[0], CosineDistance))) > 1e-5 {
+		t.Errorf("Row(0) is not the unit row of candidate 0")
+	}
+}
+
+func TestDistancesTo_AllocationFree(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	candidates := randomVectors(r, 100, 64)
+	query := randomVectors(r, 1, 64)[0]
+	matrix := NewNormalizedMatrix(candidates)
+	dists := make([]float32, 0, len(candidates))
+	idx := make([]int, 0, 10)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dists = DistancesTo(query, candidates, CosineDistance, dists)
+		dists = matrix.Distances(query, L2Distance, dists)
+		idx = TopK(dists, 10, idx)
+	})
+	if allocs != 0 {
+		t.Errorf("allocs per run = %v, want 0", allocs)
+	}
+}
+
+func TestDistancesTo_DimensionMismatch(t *testing.T) {
+	cases := map[string]func(){
+		"DistancesTo":         func() { DistancesTo(Vector{1, 2}, []Vector{{1}}, CosineDistance, nil) },
+		"NewNormalizedMatrix": func() { NewNormalizedMatrix([]Vector{{1, 2}, {1}}) },
+		"Distances": func() {
+			NewNormalizedMatrix([]Vector{{1, 2}}).Distances(Vector{1}, CosineDistance, nil)
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func TestTopK(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	dists := make([]float32, 200)
+	for i := range dists {
+		dists[i] = float32(r.Intn(50)) // Many ties
+	}
+	want := make([]int, len(dists))
+	for i := range want {
+		want[i] = i
+	}
+	sort.SliceStable(want, func(a, b int) bool { return dists[want[a]] < dists[want[b]] })
+
+	for _, k := range []int{1, 7, 200, 500} {
+		got := TopK(dists, k, nil)
+		n := min(k, len(dists))
+		if len(got) != n {
+			t.Fatalf("k=%d: len = %d", k, len(got))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("k=%d: TopK[%d] = %d, want %d", k, i, got[i], want[i])
+			}
+		}
+	}
+	if got := TopK(dists, 0, nil); len(got) != 0 {
+		t.Errorf("k=0: %v", got)
+	}
+}
+
+// Benchmarks: one query against 1000 candidates of dimension 768
+//
+//	go test -run ^$ -bench 'Cosine|DistancesTo|NormalizedMatrix|TopK' -benchmem
+
+func benchData(b *testing.B) (Vector, []Vector) {
+	b.Helper()
+	r := rand.New(rand.NewSource(4))
+	return randomVectors(r, 1, 768)[0], randomVectors(r, 1000, 768)
+}
+
+func BenchmarkCosineDistance_Pairwise(b *testing.B) {
+	query, candidates := benchData(b)
+	out := make([]float32, len(candidates))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, c := range candidates {
+			out[j] = query.Distance(c, CosineDistance)
+		}
+	}
+}
+
+func BenchmarkDistancesTo_Cosine(b *testing.B) {
+	query, candidates := benchData(b)
+	out := make([]float32, len(candidates))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out = DistancesTo(query, candidates, CosineDistance, out)
+	}
+}
+
+func BenchmarkNormalizedMatrix_Cosine(b *testing.B) {
+	query, candidates := benchData(b)
+	matrix := NewNormalizedMatrix(candidates)
+	out := make([]float32, len(candidates))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out = matrix.Distances(query, CosineDistance, out)
+	}
+}
+
+func BenchmarkTopK_Heap(b *testing.B) {
+	query, candidates := benchData(b)
+	dists := DistancesTo(query, candidates, CosineDistance, nil)
+	idx := make([]int, 0, 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx = TopK(dists, 10, idx)
+	}
+}
+
+func BenchmarkTopK_Sort(b *testing.B) {
+	query, candidates := benchData(b)
+	dists := DistancesTo(query, candidates, CosineDistance, nil)
+	idx := make([]int, len(dists))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range idx {
+			idx[j] = j
+		}
+		sort.Slice(idx, func(a, c int) bool { return dists[idx[a]] < dists[idx[c]] })
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2025 me.fndo.xb
+//
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package xb
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestHalfVector_Conversion(t *testing.T) {
+	v := Vector{0, 1, -2.5, 0.1, 65504, 1e6, 6e-8, float32(math.Inf(-1))}
+	got := v.ToHalf().ToVector()
+
+	want := Vector{0, 1, -2.5, 0.099975586, 65504, float32(math.Inf(1)), 5.9604645e-08, float32(math.Inf(-1))}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] %v => %v, want %v", i, v[i], got[i], want[i])
+		}
+	}
+	if h := (Vector{1}).ToHalf(); h[0] != 0x3c00 {
+		t.Errorf("1.0 = %#x, want 0x3c00", h[0])
+	}
+	// Round to nearest even: 2049 is halfway between 2048 and 2050
+	if got := (Vector{2049, 2051}).ToHalf().ToVector(); got[0] != 2048 || got[1] != 2052 {
+		t.Errorf("rounding = %v", got)
+	}
+	if nan := (Vector{float32(math.NaN())}).ToHalf().ToVector(); !math.IsNaN(float64(nan[0])) {
+		t.Errorf("NaN = %v", nan[0])
+	}
+
+	val, err := Vector{1, 0.5}.ToHalf().Value()
+	if err != nil || val != "[1,0.5]" {
+		t.Fatalf("Value = %v, %v", val, err)
+	}
+	var scanned HalfVector
+	if err := scanned.Scan([]byte("[1,0.5]")); err != nil || scanned.Dim() != 2 || scanned[1] != 0x3800 {
+		t.Errorf("Scan = %v, %v", scanned, err)
+	}
+	if d := (Vector{1, 0}).ToHalf().Distance(Vector{0, 1}.ToHalf(), L2Distance); math.Abs(float64(d)-math.Sqrt2) > 1e-6 {
+		t.Errorf("Distance = %v", d)
+	}
+}
+
+func TestInt8Vector_Quantize(t *testing.T) {
+	v := Vector{-1, 0, 0.5, 1}
+	q := v.QuantizeInt8()
+	if q.Min != -1 || q.Max != 1 || q.Values[0] != -128 || q.Values[3] != 127 {
+		t.Fatalf("unexpected quantization: %+v", q)
+	}
+	for i, f := range q.ToVector() {
+		if math.Abs(float64(f-v[i])) > 1.01/255 { // Half a step of 2/255
+			t.Errorf("[%d] %v => %v", i, v[i], f)
+		}
+	}
+
+	// Shared range clamps
+	if q := (Vector{-3, 3}).QuantizeInt8Range(-1, 1); q.Values[0] != -128 || q.Values[1] != 127 {
+		t.Errorf("clamped = %v", q.Values)
+	}
+	if q := (Vector{2, 2}).QuantizeInt8(); q.ToVector()[1] != 2 {
+		t.Errorf("constant vector = %v", q.ToVector())
+	}
+
+	a, b := Vector{0.6, 0.8}, Vector{0.8, 0.6}
+	exact := a.Distance(b, CosineDistance)
+	approx := a.QuantizeInt8Range(-1, 1).Distance(b.QuantizeInt8Range(-1, 1), CosineDistance)
+	if math.Abs(float64(exact-approx)) > 0.01 {
+		t.Errorf("Distance = %v, exact %v", approx, exact)
+	}
+
+	val, err := q.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	var scanned Int8Vector
+	if err := scanned.Scan(val); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned.Min != q.Min || scanned.Max != q.Max || scanned.Dim() != 4 || scanned.Values[2] != q.Values[2] {
+		t.Errorf("round trip = %+v, want %+v", scanned, q)
+	}
+	if err := scanned.Scan([]byte{1, 2}); err == nil {
+		t.Error("short bytea should fail")
+	}
+
+	js, _ := q.MarshalJSON()
+	if string(js) != "[0,128,191,255]" {
+		t.Errorf("JSON = %s", js)
+	}
+}
+
+func TestBinaryVector_Hamming(t *testing.T) {
+	a := Vector{0.5, -1, 2, 0, 3, -0.1, 0.2, 1, 1}.Binarize()
+	if a.Dim != 9 || a.String() != "101010111" || a.Bits[0] != 0xab || a.Bits[1] != 0x80 {
+		t.Fatalf("Binarize = %s %x", a, a.Bits)
+	}
+	b := Vector{1, 1, 1, 1, 1, 1, 1, 1, -1}.Binarize()
+	if got := a.Hamming(b); got != 4 {
+		t.Errorf("Hamming = %d, want 4", got)
+	}
+	if got := a.Distance(a); got != 0 {
+		t.Errorf("self Distance = %v", got)
+	}
+	// Vector.Distance with HammingDistance compares the sign bits
+	if got := (Vector{1, -1, 1}).Distance(Vector{1, 1, -1}, HammingDistance); got != 2 {
+		t.Errorf("Vector Hamming = %v", got)
+	}
+
+	val, err := a.Value()
+	if err != nil || val != "101010111" {
+		t.Fatalf("Value = %v, %v", val, err)
+	}
+	var scanned BinaryVector
+	if err := scanned.Scan([]byte("101010111")); err != nil || scanned.Hamming(a) != 0 {
+		t.Errorf("Scan = %v, %v", scanned, err)
+	}
+	if err := scanned.Scan("10x"); err == nil {
+		t.Error("invalid bit string should fail")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("dimension mismatch should panic")
+		}
+	}()
+	a.Hamming(Vector{1}.Binarize())
+}
+
+func TestVectorEncoding_SQL(t *testing.T) {
+	vec := Vector{0.5, -1}
+
+	sql, args := Of(&CodeVector{}).
+		Eq("language", "golang").
+		VectorSearch("embedding", vec, 5).
+		VectorEncoding(Float16Encoding).
+		Build().
+		SqlOfVectorSearch()
+	want := "SELECT *, embedding <-> ?::halfvec AS distance FROM code_vectors WHERE language = ? ORDER BY distance LIMIT 5"
+	if sql != want {
+		t.Errorf("sql = %s\nwant %s", sql, want)
+	}
+	if h, ok := args[0].(HalfVector); !ok || h.Dim() != 2 {
+		t.Errorf("args = %v", args)
+	}
+
+	sql, args = Of(&CodeVector{}).
+		VectorSearch("embedding", vec, 5).
+		VectorEncoding(BinaryEncoding).
+		Build().
+		SqlOfVectorSearch()
+	want = "SELECT *, embedding <~> ?::bit(2) AS distance FROM code_vectors ORDER BY distance LIMIT 5"
+	if sql != want {
+		t.Errorf("sql = %s\nwant %s", sql, want)
+	}
+	if b, ok := args[0].(BinaryVector); !ok || b.String() != "10" {
+		t.Errorf("args = %v", args)
+	}
+
+	// Quantized values are written by their Valuer
+	sql, args = Of(&CodeVector{}).
+		Insert(func(ib *InsertBuilder) {
+			ib.Set("id", 1).Set("embedding", vec.ToHalf()).Set("code", vec.QuantizeInt8()).Set("bits", vec.Binarize())
+		}).
+		Build().
+		SqlOfInsert()
+	if !strings.Contains(sql, "embedding") || len(args) != 4 {
+		t.Fatalf("insert = %s %v", sql, args)
+	}
+	if _, ok := args[1].(HalfVector); !ok {
+		t.Errorf("insert args = %v", args)
+	}
+	if _, ok := args[3].(BinaryVector); !ok {
+		t.Errorf("insert args = %v", args)
+	}
+}
+
+func TestVectorEncoding_Qdrant(t *testing.T) {
+	// Qdrant quantizes server-side: the query stays float
+	js, err := Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		VectorSearch("embedding", Vector{0.5, -1}, 5).
+		VectorEncoding(Int8Encoding).
+		Build().
+		JsonOfSelect()
+	if err != nil {
+		t.Fatalf("JsonOfSelect failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{"vector": [0.5, -1], "limit": 5, "with_payload": true, "params": {"hnsw_ef": 128}}`)
+
+	js, err = Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Insert(func(ib *InsertBuilder) {
+			ib.Set("id", 1).Set("vector", Vector{-1, 1}.QuantizeInt8())
+		}).
+		Build().
+		JsonOfInsert()
+	if err != nil {
+		t.Fatalf("JsonOfInsert failed: %v", err)
+	}
+	assertJSONEqual(t, js, `{"points": [{"id": 1, "vector": [0, 255]}]}`)
+
+	_, err = Of(&CodeVectorForQdrant{}).Custom(NewQdrantBuilder().Build()).
+		Insert(func(ib *InsertBuilder) {
+			ib.Set("id", 1).Set("vector", Vector{-1, 1}.Binarize())
+		}).
+		Build().
+		JsonOfInsert()
+	if err == nil {
+		t.Error("binary vector upsert should fail")
+	}
+
+	body, err := QdrantCollection("docs").
+		NamedVector("title", 4, CosineDistance).
+		NamedVector("body", 4, CosineDistance).
+		Datatype("title", Float16Encoding).
+		Datatype("body", Int8Encoding).
+		CreateJSON()
+	if err != nil {
+		t.Fatalf("CreateJSON failed: %v", err)
+	}
+	assertJSONEqual(t, body, `{"vectors": {
+		"title": {"size": 4, "distance": "Cosine", "datatype": "float16"},
+		"body": {"size": 4, "distance": "Cosine", "datatype": "uint8"}
+	}}`)
+}
+
+func TestMemoryVector_QuantizedPoints(t *testing.T) {
+	mem := NewMemoryVectorBuilder().Build()
+	points := map[int]interface{}{
+		1: Vector{1, 0}.ToHalf(),
+		2: Vector{0, 1}.QuantizeInt8Range(-1, 1),
+	}
+	for id, vec := range points {
+		_, err := Of(&CodeVectorForQdrant{}).Custom(mem).
+			Insert(func(ib *InsertBuilder) {
+				ib.Set("id", id).Set("embedding", vec)
+			}).
+			Build().
+			MemoryOfExec()
+		if err != nil {
+			t.Fatalf("insert %d failed: %v", id, err)
+		}
+	}
+
+	results, err := MemorySearch[map[string]interface{}](Of(&CodeVectorForQdrant{}).Custom(mem).
+		VectorSearch("embedding", Vector{0.1, 0.9}, 2).
+		Build())
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := memoryIDs(results); got != "2 1 " {
+		t.Errorf("ids = %s", got)
+	}
+}
+
+func TestVectorEncoding_Validation(t *testing.T) {
+	cases := map[string]func(){
+		"unknown encoding": func() {
+			Of(&CodeVector{}).VectorSearch("embedding", Vector{1}, 5).VectorEncoding("int4")
+		},
+		"sparse search": func() {
+			Of(&CodeVector{}).SparseVectorSearch("s", NewSparseVector(map[uint32]float32{1: 1}), 5).VectorEncoding(Float16Encoding)
+		},
+		"int8 sql": func() {
+			Of(&CodeVector{}).VectorSearch("embedding", Vector{1}, 5).VectorEncoding(Int8Encoding).Build().SqlOfVectorSearch()
+		},
+		"binary datatype":  func() { QdrantCollection("c").Vectors(4, CosineDistance).Datatype("", BinaryEncoding) },
+		"unnamed datatype": func() { QdrantCollection("c").NamedVector("a", 4, CosineDistance).Datatype("", Float16Encoding) },
+		"unknown vector":   func() { QdrantCollection("c").NamedVector("a", 4, CosineDistance).Datatype("b", Float16Encoding) },
+		"int8 range":       func() { Vector{1}.QuantizeInt8Range(1, -1) },
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
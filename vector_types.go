@@ -18,9 +18,13 @@ package xb
 
 import (
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/bits"
+	"strconv"
+	"strings"
 )
 
 // Vector vector type (compatible with PostgreSQL pgvector)
@@ -74,6 +78,10 @@ const (
 	// InnerProduct inner product distance (dot product)
 	// PostgreSQL: <=>
 	InnerProduct VectorDistance = "<=>"
+
+	// HammingDistance number of differing bits (BinaryVector)
+	// PostgreSQL: <~> on bit columns
+	HammingDistance VectorDistance = "<~>"
 )
 
 // Distance calculates the distance between two vectors
@@ -89,6 +97,8 @@ func (v Vector) Distance(other Vector, metric VectorDistance) float32 {
 		return l2Distance(v, other)
 	case InnerProduct:
 		return innerProduct(v, other)
+	case HammingDistance:
+		return signHamming(v, other)
 	default:
 		return cosineDistance(v, other)
 	}
@@ -141,6 +151,52 @@ func innerProduct(a, b Vector) float32 {
 	return -sum
 }
 
+// signHamming Hamming distance of the sign bits, same as Binarize().Distance()
+func signHamming(a, b Vector) float32 {
+	var n float32
+	for i := range a {
+		if (a[i] > 0) != (b[i] > 0) {
+			n++
+		}
+	}
+	return n
+}
+
+// MultiVector one vector per token (ColBERT-style late interaction)
+// All rows have the same dimension
+type MultiVector []Vector
+
+// MaxSim late-interaction score: for each query row the best similarity to any doc row, summed
+// Similarity per metric: cosine 1 - distance, inner product the dot product, L2 the negative distance
+// Higher is more similar
+func (m MultiVector) MaxSim(doc MultiVector, metric VectorDistance) float32 {
+	var sum float32
+	for _, q := range m {
+		best := float32(math.Inf(-1))
+		for _, d := range doc {
+			sim := -q.Distance(d, metric)
+			if metric == CosineDistance || metric == "" {
+				sim = 1 + sim
+			}
+			if sim > best {
+				best = sim
+			}
+		}
+		if len(doc) > 0 {
+			sum += best
+		}
+	}
+	return sum
+}
+
+// Dim returns the dimension of the rows, 0 if empty
+func (m MultiVector) Dim() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
 // Normalize vector normalization (L2 norm)
 func (v Vector) Normalize() Vector {
 	var norm float32
@@ -203,8 +259,352 @@ type DiversityParams struct {
 	// 0.5 = balanced (recommended)
 	Lambda float32
 
+	// DistanceMetric metric used by DiversityByDistance (defaults to the search metric)
+	DistanceMetric VectorDistance
+
 	// OverFetchFactor over-fetch factor
 	// First fetch TopK * OverFetchFactor results, then apply diversity filtering
 	// Default: 5 (fetch 5x results then filter)
 	OverFetchFactor int
 }
+
+// ============================================================================
+// Quantized encodings: float16, int8, binary
+// ============================================================================
+
+// VectorEncoding storage type of a vector column, set by VectorEncoding() on a VectorSearch
+type VectorEncoding string
+
+const (
+	// Float32Encoding pgvector vector, the default
+	Float32Encoding VectorEncoding = "vector"
+
+	// Float16Encoding pgvector halfvec: half the storage, ~3 significant digits
+	Float16Encoding VectorEncoding = "halfvec"
+
+	// Int8Encoding scalar quantization to int8 (Int8Vector); Qdrant datatype uint8, no pgvector type
+	Int8Encoding VectorEncoding = "int8"
+
+	// BinaryEncoding one bit per dimension (BinaryVector), pgvector bit, searched by HammingDistance
+	BinaryEncoding VectorEncoding = "bit"
+)
+
+// HalfVector float16 vector (IEEE 754 half precision bits), pgvector halfvec
+type HalfVector []uint16
+
+// ToHalf converts to float16, rounding to nearest even; out of range values become ±Inf
+func (v Vector) ToHalf() HalfVector {
+	if v == nil {
+		return nil
+	}
+	h := make(HalfVector, len(v))
+	for i, f := range v {
+		h[i] = float32ToHalf(f)
+	}
+	return h
+}
+
+// ToVector converts back to float32
+func (h HalfVector) ToVector() Vector {
+	if h == nil {
+		return nil
+	}
+	v := make(Vector, len(h))
+	for i, b := range h {
+		v[i] = halfToFloat32(b)
+	}
+	return v
+}
+
+// Dim returns vector dimension
+func (h HalfVector) Dim() int {
+	return len(h)
+}
+
+// Distance calculates the distance in float32
+func (h HalfVector) Distance(other HalfVector, metric VectorDistance) float32 {
+	return h.ToVector().Distance(other.ToVector(), metric)
+}
+
+// Value implements driver.Valuer interface
+// pgvector halfvec format: '[1,2.5,3]'
+func (h HalfVector) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	return h.ToVector().Value()
+}
+
+// Scan implements sql.Scanner interface
+func (h *HalfVector) Scan(value interface{}) error {
+	var v Vector
+	if err := v.Scan(value); err != nil {
+		return err
+	}
+	*h = v.ToHalf()
+	return nil
+}
+
+// MarshalJSON writes the float values (Qdrant datatype float16 stores them as half)
+func (h HalfVector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.ToVector())
+}
+
+// float32ToHalf IEEE 754 float32 => float16 bits, round to nearest even
+func float32ToHalf(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int((b>>23)&0xff) - 127 + 15
+	mant := b & 0x7fffff
+
+	if (b>>23)&0xff == 0xff { // Inf, NaN
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	}
+	if exp >= 0x1f { // Overflow
+		return sign | 0x7c00
+	}
+	if exp <= 0 { // Subnormal half: m * 2^-24
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem, halfway := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+	half := sign | uint16(exp)<<10 | uint16(mant>>13)
+	// A carry out of the mantissa correctly increments the exponent
+	if rem := mant & 0x1fff; rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++
+	}
+	return half
+}
+
+// halfToFloat32 float16 bits => float32
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch exp {
+	case 0:
+		f := float32(mant) / (1 << 24) // Zero or subnormal
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp-15+127)<<23 | mant<<13)
+}
+
+// Int8Vector scalar-quantized vector: value = Min + (q + 128) * (Max - Min) / 255
+type Int8Vector struct {
+	Values []int8
+	Min    float32
+	Max    float32
+}
+
+// QuantizeInt8 quantizes with the vector's own min/max
+// Vectors compared with each other should share a range, see QuantizeInt8Range()
+func (v Vector) QuantizeInt8() Int8Vector {
+	if len(v) == 0 {
+		return Int8Vector{}
+	}
+	lo, hi := v[0], v[0]
+	for _, f := range v {
+		lo, hi = min(lo, f), max(hi, f)
+	}
+	return v.QuantizeInt8Range(lo, hi)
+}
+
+// QuantizeInt8Range quantizes into a fixed range, values outside are clamped
+// e.g. QuantizeInt8Range(-1, 1) for normalized embeddings
+func (v Vector) QuantizeInt8Range(lo, hi float32) Int8Vector {
+	if hi < lo {
+		panic(fmt.Sprintf("QuantizeInt8Range: max %v < min %v", hi, lo))
+	}
+	q := Int8Vector{Values: make([]int8, len(v)), Min: lo, Max: hi}
+	if hi == lo {
+		for i := range q.Values {
+			q.Values[i] = -128
+		}
+		return q
+	}
+	scale := 255 / (float64(hi) - float64(lo))
+	for i, f := range v {
+		n := math.Round((float64(f) - float64(lo)) * scale)
+		q.Values[i] = int8(math.Max(0, math.Min(255, n)) - 128)
+	}
+	return q
+}
+
+// ToVector dequantizes
+func (q Int8Vector) ToVector() Vector {
+	if q.Values == nil {
+		return nil
+	}
+	v := make(Vector, len(q.Values))
+	scale := (q.Max - q.Min) / 255
+	for i, b := range q.Values {
+		v[i] = q.Min + float32(int(b)+128)*scale
+	}
+	return v
+}
+
+// Dim returns vector dimension
+func (q Int8Vector) Dim() int {
+	return len(q.Values)
+}
+
+// Distance calculates the distance of the dequantized vectors
+func (q Int8Vector) Distance(other Int8Vector, metric VectorDistance) float32 {
+	return q.ToVector().Distance(other.ToVector(), metric)
+}
+
+// Value implements driver.Valuer interface
+// bytea: min, max (float32 little endian), then one byte per value
+func (q Int8Vector) Value() (driver.Value, error) {
+	if q.Values == nil {
+		return nil, nil
+	}
+	b := make([]byte, 8+len(q.Values))
+	binary.LittleEndian.PutUint32(b, math.Float32bits(q.Min))
+	binary.LittleEndian.PutUint32(b[4:], math.Float32bits(q.Max))
+	for i, v := range q.Values {
+		b[8+i] = byte(v)
+	}
+	return b, nil
+}
+
+// Scan implements sql.Scanner interface
+func (q *Int8Vector) Scan(value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		*q = Int8Vector{}
+		return nil
+	case []byte:
+		if len(value) < 8 {
+			return fmt.Errorf("invalid int8 vector: %d bytes", len(value))
+		}
+		out := Int8Vector{
+			Values: make([]int8, len(value)-8),
+			Min:    math.Float32frombits(binary.LittleEndian.Uint32(value)),
+			Max:    math.Float32frombits(binary.LittleEndian.Uint32(value[4:])),
+		}
+		for i, b := range value[8:] {
+			out.Values[i] = int8(b)
+		}
+		*q = out
+		return nil
+	default:
+		return fmt.Errorf("unsupported int8 vector type: %T", value)
+	}
+}
+
+// MarshalJSON writes q + 128 as 0..255, the values of a Qdrant uint8 vector
+// Min/Max are not sent: points of a collection must share one range
+func (q Int8Vector) MarshalJSON() ([]byte, error) {
+	out := make([]int, len(q.Values))
+	for i, v := range q.Values {
+		out[i] = int(v) + 128
+	}
+	return json.Marshal(out)
+}
+
+// BinaryVector one bit per dimension, most significant bit first (pgvector bit)
+type BinaryVector struct {
+	Bits []byte
+	Dim  int
+}
+
+// Binarize keeps the sign: bit i is set when v[i] > 0
+func (v Vector) Binarize() BinaryVector {
+	b := BinaryVector{Bits: make([]byte, (len(v)+7)/8), Dim: len(v)}
+	for i, f := range v {
+		if f > 0 {
+			b.Bits[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return b
+}
+
+// Bit returns bit i
+func (b BinaryVector) Bit(i int) bool {
+	return b.Bits[i/8]&(0x80>>(i%8)) != 0
+}
+
+// Hamming number of differing bits
+func (b BinaryVector) Hamming(other BinaryVector) int {
+	if b.Dim != other.Dim {
+		panic("vectors must have same dimension")
+	}
+	n := 0
+	for i := range b.Bits {
+		n += bits.OnesCount8(b.Bits[i] ^ other.Bits[i])
+	}
+	return n
+}
+
+// Distance Hamming distance as float32
+func (b BinaryVector) Distance(other BinaryVector) float32 {
+	return float32(b.Hamming(other))
+}
+
+// String bit string '0101...'
+func (b BinaryVector) String() string {
+	var sb strings.Builder
+	sb.Grow(b.Dim)
+	for i := 0; i < b.Dim; i++ {
+		if b.Bit(i) {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+// Value implements driver.Valuer interface
+// pgvector bit format: '0101...'
+func (b BinaryVector) Value() (driver.Value, error) {
+	if b.Bits == nil {
+		return nil, nil
+	}
+	return b.String(), nil
+}
+
+// Scan implements sql.Scanner interface
+func (b *BinaryVector) Scan(value interface{}) error {
+	var s string
+	switch value := value.(type) {
+	case nil:
+		*b = BinaryVector{}
+		return nil
+	case []byte:
+		s = string(value)
+	case string:
+		s = value
+	default:
+		return fmt.Errorf("unsupported binary vector type: %T", value)
+	}
+	out := BinaryVector{Bits: make([]byte, (len(s)+7)/8), Dim: len(s)}
+	for i, c := range s {
+		switch c {
+		case '1':
+			out.Bits[i/8] |= 0x80 >> (i % 8)
+		case '0':
+		default:
+			return fmt.Errorf("invalid bit string at %d: %s", i, strconv.Quote(s))
+		}
+	}
+	*b = out
+	return nil
+}